@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/database"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repositoryAuth "github.com/oriastanjung/stellar/internal/repository/auth"
+	repositoryNotification "github.com/oriastanjung/stellar/internal/repository/notification"
+	usecaseAuth "github.com/oriastanjung/stellar/internal/usecase/auth"
+	usecaseNotification "github.com/oriastanjung/stellar/internal/usecase/notification"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"golang.org/x/term"
+	"gorm.io/gorm"
+)
+
+const userUsage = `Usage: stellar user <command> <email>
+
+Commands:
+  verify    mark the account verified without the emailed link
+  disable   block new logins and API key calls, issued access tokens stay
+            valid until they expire
+  enable    undo disable
+`
+
+// runCreateAdmin creates an admin account straight in the database, it needs
+// neither ADMIN_BOOTSTRAP_TOKEN nor an invitation like SignUpAdmin does.
+func runCreateAdmin(args []string) int {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin (required)")
+	username := flags.String("username", "", "username of the admin (required)")
+	configPath := configFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *email == "" || *username == "" {
+		fmt.Fprintln(os.Stderr, "create-admin requires -email and -username")
+		flags.Usage()
+		return 2
+	}
+
+	cfg, ok := loadConfig(*configPath)
+	if !ok {
+		return 1
+	}
+	password, err := promptPassword()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read password: %v\n", err)
+		return 1
+	}
+
+	user, err := entities.NewUser(*username, strings.ToLower(strings.TrimSpace(*email)), password, entities.AdminRole)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create admin: %v\n", err)
+		return 1
+	}
+	authUseCase, db, err := newAuthUseCase(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create admin: %v\n", err)
+		return 1
+	}
+	defer database.CloseDatabase(db)
+	if err := authUseCase.RegisterAdmin(user); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create admin: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Created admin %s (%s)\n", user.Email, user.ID)
+	return 0
+}
+
+// runRotateKeys makes a new JWT signing key active. Running servers read
+// the key ring again every 30 seconds and then sign with the new key, the
+// old one keeps verifying for JWT_KEY_OVERLAP.
+func runRotateKeys(args []string) int {
+	flags := flag.NewFlagSet("rotate-keys", flag.ContinueOnError)
+	configPath := configFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	cfg, ok := loadConfig(*configPath)
+	if !ok {
+		return 1
+	}
+
+	keyRing, err := utils.LoadKeyRing(cfg.JWTKeysDir, cfg.JWTSigningAlgorithm, cfg.JWTKeyOverlap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load JWT key ring: %v\n", err)
+		return 1
+	}
+	if err := keyRing.Rotate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to rotate JWT signing key: %v\n", err)
+		return 1
+	}
+	key, err := keyRing.SigningKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to rotate JWT signing key: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Active signing key is now %s, running servers sign with it within 30s\n", key.KID)
+	return 0
+}
+
+func runUser(args []string) int {
+	flags := flag.NewFlagSet("user", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, userUsage) }
+	configPath := configFlag(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	command, email := flags.Arg(0), flags.Arg(1)
+
+	var run func(authUseCase usecaseAuth.AuthUseCase) error
+	switch command {
+	case "verify":
+		run = func(authUseCase usecaseAuth.AuthUseCase) error { return authUseCase.VerifyUserByEmail(email) }
+	case "disable":
+		run = func(authUseCase usecaseAuth.AuthUseCase) error { return authUseCase.SetUserDisabled(email, true) }
+	case "enable":
+		run = func(authUseCase usecaseAuth.AuthUseCase) error { return authUseCase.SetUserDisabled(email, false) }
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	cfg, ok := loadConfig(*configPath)
+	if !ok {
+		return 1
+	}
+	authUseCase, db, err := newAuthUseCase(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s %s: %v\n", command, email, err)
+		return 1
+	}
+	defer database.CloseDatabase(db)
+	if err := run(authUseCase); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s %s: %v\n", command, email, err)
+		return 1
+	}
+	fmt.Printf("User %s: %s done\n", email, command)
+	return 0
+}
+
+// newAuthUseCase connects to the database without migrating it, commands
+// expect `stellar migrate up` to have run.
+func newAuthUseCase(cfg *config.Config) (usecaseAuth.AuthUseCase, *gorm.DB, error) {
+	components, err := newComponents(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	db := database.ConnectDB(cfg)
+	notificationUseCase := usecaseNotification.NewNotificationUseCase(repositoryNotification.NewNotificationRepository(db), cfg)
+	return usecaseAuth.NewAuthUseCase(repositoryAuth.NewAuthRepository(db), notificationUseCase, components.authDependencies(), cfg), db, nil
+}
+
+// promptPassword asks for the password twice without echo on a terminal,
+// otherwise it reads one line so it can be piped in from scripts.
+func promptPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "Confirm password: ")
+	confirm, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(password) != string(confirm) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(password), nil
+}
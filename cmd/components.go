@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/config"
+	usecaseAuth "github.com/oriastanjung/stellar/internal/usecase/auth"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/oauth"
+)
+
+// components are the utilities built once from config and passed to the
+// usecases, nothing below cmd reads config or opens connections itself.
+type components struct {
+	keyRing   *utils.KeyRing
+	cipher    *utils.AESCipher
+	tokens    utils.TokenIssuer
+	hasher    utils.Hasher
+	clock     utils.Clock
+	ids       utils.IDGenerator
+	providers *oauth.Registry
+}
+
+func newComponents(cfg *config.Config) (*components, error) {
+	keyRing, err := utils.LoadKeyRing(cfg.JWTKeysDir, cfg.JWTSigningAlgorithm, cfg.JWTKeyOverlap)
+	if err != nil {
+		return nil, fmt.Errorf("error loading JWT key ring: %w", err)
+	}
+	cipher, err := utils.NewAESCipher(cfg.AESSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := oauth.NewRegistry(cfg.OAuthProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokens are only wrapped in JWE when enabled, the cipher is still
+	// needed for the secrets sealed at rest
+	var tokenCipher *utils.AESCipher
+	if cfg.JWTEncryptionEnabled {
+		tokenCipher = cipher
+	}
+	clock := utils.SystemClock{}
+	return &components{
+		keyRing:   keyRing,
+		cipher:    cipher,
+		tokens:    utils.NewJWTIssuer(keyRing, tokenCipher, cfg.JWTIssuer, cfg.JWTAccessTokenTTL, clock),
+		hasher:    utils.BcryptHasher{Cost: cfg.BcryptSalt},
+		clock:     clock,
+		ids:       utils.KSUIDGenerator{},
+		providers: providers,
+	}, nil
+}
+
+// authDependencies are the components the auth usecase takes.
+func (c *components) authDependencies() usecaseAuth.Dependencies {
+	return usecaseAuth.Dependencies{
+		Tokens:    c.tokens,
+		Hasher:    c.hasher,
+		Cipher:    c.cipher,
+		Clock:     c.clock,
+		IDs:       c.ids,
+		Providers: c.providers,
+	}
+}
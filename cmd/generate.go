@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	usecaseImage "github.com/oriastanjung/stellar/internal/usecase/image"
+)
+
+// runGenerate runs the generation pipeline of the ImageService locally,
+// without the server, database, quota or job history.
+func runGenerate(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	var prompt entities.ImagePrompt
+	flags.StringVar(&prompt.CoreSubject, "subject", "", "core subject of the image (required)")
+	flags.StringVar(&prompt.KeyDescriptors, "descriptors", "", "materials, textures, colors")
+	flags.StringVar(&prompt.Environment, "environment", "", "setting that frames the subject")
+	flags.StringVar(&prompt.Style, "style", "", "artistic or photographic style")
+	flags.StringVar(&prompt.MoodTone, "mood", "", "mood or tone")
+	flags.StringVar(&prompt.Composition, "composition", "", "camera angle, shot type or layout")
+	flags.StringVar(&prompt.AdditionalInstructions, "instructions", "", "additional instructions")
+	out := flags.String("out", ".", "directory the JPEG and WebP files are written to")
+	configPath := configFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if strings.TrimSpace(prompt.CoreSubject) == "" {
+		fmt.Fprintln(os.Stderr, "generate requires -subject")
+		flags.Usage()
+		return 2
+	}
+
+	cfg, ok := loadConfig(*configPath)
+	if !ok {
+		return 1
+	}
+	imageURL, filename, err := usecaseImage.GenerateToDir(cfg, prompt.String(), *out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate image: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Generated %s\nSaved %s and %s\n", imageURL,
+		filepath.Join(*out, filename+".jpeg"), filepath.Join(*out, filename+".webp"))
+	return 0
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/health"
+	"github.com/oriastanjung/stellar/internal/utils/circuitbreaker"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	pbAPIKey "github.com/oriastanjung/stellar/proto/apikey"
+	pbAuth "github.com/oriastanjung/stellar/proto/auth"
+	pbImage "github.com/oriastanjung/stellar/proto/image"
+	pbNotification "github.com/oriastanjung/stellar/proto/notification"
+	pbOrganization "github.com/oriastanjung/stellar/proto/organization"
+	pbWebhook "github.com/oriastanjung/stellar/proto/webhook"
+	grpchealth "google.golang.org/grpc/health"
+	"gorm.io/gorm"
+)
+
+// newHealthMonitor checks the database for every service, image also
+// needs its storage and the generation API, notification the SMTP server.
+func newHealthMonitor(cfg *config.Config, server *grpchealth.Server, db *gorm.DB, mail mailer.Mailer, upstream *circuitbreaker.Breaker) *health.Monitor {
+	monitor := health.NewMonitor(server, cfg.HealthCheckInterval, cfg.HealthCheckTimeout)
+
+	monitor.AddCheck(health.Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}})
+	monitor.AddCheck(health.Check{Name: "storage", Run: func(ctx context.Context) error {
+		return checkWritable(cfg.ImageStorageDir)
+	}})
+	// tidak memanggil upstream, cukup status circuit breaker dari request sungguhan
+	monitor.AddCheck(health.Check{Name: "image_api", Run: func(ctx context.Context) error {
+		if state, err := upstream.State(); state == circuitbreaker.StateOpen {
+			return fmt.Errorf("%s circuit breaker is open: %v", upstream.Name(), err)
+		}
+		return nil
+	}})
+	smtpChecks := []string{}
+	if pinger, ok := mail.(mailer.Pinger); ok {
+		monitor.AddCheck(health.Check{Name: "smtp", Run: pinger.Ping})
+		smtpChecks = append(smtpChecks, "smtp")
+	}
+
+	monitor.AddService(pbAuth.AuthServiceRoutes_ServiceDesc.ServiceName)
+	monitor.AddService(pbImage.ImageService_ServiceDesc.ServiceName, "storage", "image_api")
+	monitor.AddService(pbNotification.NotificationService_ServiceDesc.ServiceName, smtpChecks...)
+	monitor.AddService(pbWebhook.WebhookService_ServiceDesc.ServiceName)
+	monitor.AddService(pbAPIKey.APIKeyService_ServiceDesc.ServiceName)
+	monitor.AddService(pbOrganization.OrganizationService_ServiceDesc.ServiceName)
+	return monitor
+}
+
+// checkWritable creates and removes a file in dir.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".healthcheck-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
@@ -1,86 +1,80 @@
 package main
 
 import (
-	"log"
-	"net"
+	"flag"
+	"fmt"
+	"os"
 
 	"github.com/oriastanjung/stellar/internal/config"
-	"github.com/oriastanjung/stellar/internal/database"
-	serverAuth "github.com/oriastanjung/stellar/internal/grpc/auth"
-	"github.com/oriastanjung/stellar/internal/middleware"
-	repositoryAuth "github.com/oriastanjung/stellar/internal/repository/auth"
-	servicesAuth "github.com/oriastanjung/stellar/internal/services/auth"
-	usecaseAuth "github.com/oriastanjung/stellar/internal/usecase/auth"
-	pbAuth "github.com/oriastanjung/stellar/proto/auth"
-
-	serverImage "github.com/oriastanjung/stellar/internal/grpc/image"
-	servicesImage "github.com/oriastanjung/stellar/internal/services/image"
-	usecaseImage "github.com/oriastanjung/stellar/internal/usecase/image"
-	pbImage "github.com/oriastanjung/stellar/proto/image"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection"
 )
 
-func main() {
-	// load env
-	config := config.LoadEnv()
-	var port string = config.Port
-	var addr string = "0.0.0.0:" + port
-
-	// load DB
-	database.InitDB()
-
-	// Handle graceful shutdown
-	database.GracefulShutdown()
-
-	// auth service
-	authRepository := repositoryAuth.NewAuthRepository(database.DB)
-	authUseCase := usecaseAuth.NewAuthUseCase(authRepository)
-	authService := servicesAuth.NewAuthService(authUseCase)
-	authServer := serverAuth.NewAuthServer(authService, config.BcryptSalt)
-	// end auth service
-
-	//image service
-	imageUseCase := usecaseImage.NewImageUseCase()
-	imageService := servicesImage.NewImageService(imageUseCase)
-	imageServer := serverImage.NewImageServer(imageService)
-	//end image service
+const usage = `Usage: stellar <command> [arguments]
+
+Commands:
+  serve          start the gRPC and JWKS servers (default)
+  migrate        apply, revert or create database migrations
+  create-admin   create an admin account, the password is prompted for
+  rotate-keys    generate a new JWT signing key
+  user           verify, disable or enable an account by email
+  generate       generate an image locally without the server
+
+Run "stellar <command> -h" for the arguments of a command. Every command
+accepts -config <file.yaml|file.toml>, CONFIG_FILE by default, environment
+variables and .env override the file.
+`
+
+var commands = map[string]func(args []string) int{
+	"serve":        runServe,
+	"migrate":      runMigrate,
+	"create-admin": runCreateAdmin,
+	"rotate-keys":  runRotateKeys,
+	"user":         runUser,
+	"generate":     runGenerate,
+}
 
-	listener, err := net.Listen("tcp", addr)
-	if err != nil {
-		log.Printf("Error on Listening %v\n", err)
+func main() {
+	// tanpa argumen tetap menjalankan server seperti sebelumnya
+	if len(os.Args) < 2 {
+		os.Exit(runServe(nil))
 	}
 
-	defer listener.Close()
-	log.Printf("listening on %s\n", addr)
-
-	// set ssl certificate
-	options := []grpc.ServerOption{}
-	tls := true
-
-	if tls {
-		certFile := "ssl/server.crt"
-		keyFile := "ssl/server.pem"
-		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
-		if err != nil {
-			log.Fatalf("Failed login certificate %v", err)
-		}
-		options = append(options, grpc.Creds(creds))
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage)
+		return
+	}
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
 	}
+	os.Exit(command(os.Args[2:]))
+}
 
-	// register middleware
-	options = append(options, grpc.UnaryInterceptor(middleware.TokenValidationUnaryInterceptor))
-	serverInstance := grpc.NewServer(options...)
+// configFlag adds -config to the flags of a command.
+func configFlag(flags *flag.FlagSet) *string {
+	return flags.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file, environment variables override it")
+}
 
-	pbAuth.RegisterAuthServiceRoutesServer(serverInstance, authServer)
-	pbImage.RegisterImageServiceServer(serverInstance, imageServer)
-	// pbFinance.RegisterFinanceRoutesServiceServer(serverInstance, fincanceServer)
-	// pbBusiness.RegisterBusinessRoutesServiceServer(serverInstance, businessServer)
+// loadDatabaseConfig is loadConfig for the commands that only open the
+// database, the config is not Set since most of it is left empty.
+func loadDatabaseConfig(path string) (*config.Config, bool) {
+	cfg, err := config.LoadDatabase(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return nil, false
+	}
+	return cfg, true
+}
 
-	reflection.Register(serverInstance)
-	if err = serverInstance.Serve(listener); err != nil {
-		log.Fatalf("Failed on Serve %v\n", err)
+// loadConfig loads and validates the config once for the command and
+// installs it for the packages that read config.Current.
+func loadConfig(path string) (*config.Config, bool) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return nil, false
 	}
+	config.Set(cfg)
+	return cfg, true
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/database"
+)
+
+const migrateUsage = `Usage: stellar migrate <command>
+
+Commands:
+  up           apply every pending migration
+  down [n]     revert the newest n applied migrations (default 1)
+  status       list migrations and when they were applied
+  new <name>   create empty up and down files for a new migration in
+               every backend folder
+`
+
+// runMigrate handles `stellar migrate`, it returns the exit code.
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	// sama seperti ../public, binary dijalankan dari folder cmd
+	dir := flags.String("dir", "../internal/database/migrations", "directory `new` writes migrations to")
+	configPath := configFlag(flags)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	command, rest := flags.Arg(0), flags.Args()[1:]
+
+	if command == "new" {
+		if len(rest) != 1 {
+			flags.Usage()
+			return 2
+		}
+		paths, err := database.NewMigrationFiles(*dir, rest[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create migration: %v\n", err)
+			return 1
+		}
+		for _, path := range paths {
+			fmt.Printf("Created %s\n", path)
+		}
+		return 0
+	}
+
+	if command != "up" && command != "down" && command != "status" {
+		flags.Usage()
+		return 2
+	}
+
+	cfg, ok := loadDatabaseConfig(*configPath)
+	if !ok {
+		return 1
+	}
+	db := database.ConnectDB(cfg)
+	defer database.CloseDatabase(db)
+	migrator, err := database.NewDBMigrator(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load migrations: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to migrate database: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d migration(s) applied\n", len(applied))
+	case "down":
+		steps := 1
+		if len(rest) > 0 {
+			steps, err = strconv.Atoi(rest[0])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "down expects a positive number of migrations")
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to revert migrations: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d migration(s) reverted\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read migrations: %v\n", err)
+			return 1
+		}
+		out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(out, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			name, appliedAt := status.Name, "pending"
+			if name == "" {
+				name = "(unknown to this binary)"
+			}
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%04d\t%s\t%s\n", status.Version, name, appliedAt)
+		}
+		out.Flush()
+	}
+	return 0
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/database"
+	serverAuth "github.com/oriastanjung/stellar/internal/grpc/auth"
+	httpServer "github.com/oriastanjung/stellar/internal/http"
+	"github.com/oriastanjung/stellar/internal/lifecycle"
+	"github.com/oriastanjung/stellar/internal/metrics"
+	"github.com/oriastanjung/stellar/internal/middleware"
+	repositoryAuth "github.com/oriastanjung/stellar/internal/repository/auth"
+	servicesAuth "github.com/oriastanjung/stellar/internal/services/auth"
+	usecaseAuth "github.com/oriastanjung/stellar/internal/usecase/auth"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	"github.com/oriastanjung/stellar/internal/utils/ratelimit"
+	pbAuth "github.com/oriastanjung/stellar/proto/auth"
+
+	serverImage "github.com/oriastanjung/stellar/internal/grpc/image"
+	repositoryImage "github.com/oriastanjung/stellar/internal/repository/image"
+	servicesImage "github.com/oriastanjung/stellar/internal/services/image"
+	usecaseImage "github.com/oriastanjung/stellar/internal/usecase/image"
+	pbImage "github.com/oriastanjung/stellar/proto/image"
+
+	serverNotification "github.com/oriastanjung/stellar/internal/grpc/notification"
+	repositoryNotification "github.com/oriastanjung/stellar/internal/repository/notification"
+	servicesNotification "github.com/oriastanjung/stellar/internal/services/notification"
+	usecaseNotification "github.com/oriastanjung/stellar/internal/usecase/notification"
+	pbNotification "github.com/oriastanjung/stellar/proto/notification"
+
+	serverWebhook "github.com/oriastanjung/stellar/internal/grpc/webhook"
+	repositoryWebhook "github.com/oriastanjung/stellar/internal/repository/webhook"
+	servicesWebhook "github.com/oriastanjung/stellar/internal/services/webhook"
+	usecaseWebhook "github.com/oriastanjung/stellar/internal/usecase/webhook"
+	"github.com/oriastanjung/stellar/internal/utils/webhook"
+	pbWebhook "github.com/oriastanjung/stellar/proto/webhook"
+
+	serverAPIKey "github.com/oriastanjung/stellar/internal/grpc/apikey"
+	repositoryAPIKey "github.com/oriastanjung/stellar/internal/repository/apikey"
+	servicesAPIKey "github.com/oriastanjung/stellar/internal/services/apikey"
+	usecaseAPIKey "github.com/oriastanjung/stellar/internal/usecase/apikey"
+	pbAPIKey "github.com/oriastanjung/stellar/proto/apikey"
+
+	serverOrganization "github.com/oriastanjung/stellar/internal/grpc/organization"
+	repositoryOrganization "github.com/oriastanjung/stellar/internal/repository/organization"
+	servicesOrganization "github.com/oriastanjung/stellar/internal/services/organization"
+	usecaseOrganization "github.com/oriastanjung/stellar/internal/usecase/organization"
+	pbOrganization "github.com/oriastanjung/stellar/proto/organization"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// runServe starts the gRPC and JWKS servers and returns once SIGINT or
+// SIGTERM has shut them down.
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := configFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// load config, once for the whole process
+	cfg, ok := loadConfig(*configPath)
+	if !ok {
+		return 1
+	}
+	log.Printf("Loaded configuration:\n%s", cfg)
+	var port string = cfg.Port
+	var addr string = "0.0.0.0:" + port
+
+	// load JWT signing keys and the other shared components
+	components, err := newComponents(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create components %v", err)
+	}
+
+	// SIGHUP reloads rate limits and OAuth providers without a restart
+	config.OnReload(func(cfg *config.Config) {
+		if err := components.providers.Reload(cfg.OAuthProviders); err != nil {
+			log.Printf("Error reloading OAuth providers %v\n", err)
+		}
+	})
+	go reloadOnSIGHUP(*configPath)
+
+	// load DB
+	db := database.InitDB(cfg)
+
+	// shutdown steps are added at the end, in the order they run
+	lifecycleManager := lifecycle.New(cfg.ShutdownTimeout)
+
+	// notification service, used by auth and image to email users
+	notificationRepository := repositoryNotification.NewNotificationRepository(db)
+	notificationUseCase := usecaseNotification.NewNotificationUseCase(notificationRepository, cfg)
+	notificationService := servicesNotification.NewNotificationService(notificationUseCase)
+	notificationServer := serverNotification.NewNotificationServer(notificationService)
+	// end notification service
+
+	// auth service
+	authRepository := repositoryAuth.NewAuthRepository(db)
+	authUseCase := usecaseAuth.NewAuthUseCase(authRepository, notificationUseCase, components.authDependencies(), cfg)
+	authService := servicesAuth.NewAuthService(authUseCase)
+	authServer := serverAuth.NewAuthServer(authService)
+	lifecycleManager.Go("session metrics", func(ctx context.Context) {
+		recordActiveSessions(ctx, authRepository, components.clock, cfg.JWTAccessTokenTTL)
+	})
+	// end auth service
+
+	// mail outbox worker, emails are enqueued in the same transaction as
+	// the change that triggers them and sent from here
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create mailer %v", err)
+	}
+	outboxWorker := mailer.NewOutboxWorker(db, mail, cfg.MailOutboxPollInterval, cfg.MailOutboxBatchSize, cfg.MailOutboxMaxAttempts)
+	lifecycleManager.Go("mail outbox", outboxWorker.Run)
+
+	// webhook service, generation events are queued as deliveries and
+	// posted by the delivery worker
+	webhookRepository := repositoryWebhook.NewWebhookRepository(db)
+	webhookUseCase := usecaseWebhook.NewWebhookUseCase(webhookRepository, components.cipher, components.clock, components.ids, cfg)
+	webhookService := servicesWebhook.NewWebhookService(webhookUseCase)
+	webhookServer := serverWebhook.NewWebhookServer(webhookService)
+	deliveryWorker := webhook.NewDeliveryWorker(db, components.cipher, cfg.WebhookTimeout, cfg.WebhookAllowInsecureURL, cfg.WebhookPollInterval, cfg.WebhookBatchSize, cfg.WebhookMaxAttempts)
+	lifecycleManager.Go("webhook deliveries", deliveryWorker.Run)
+	// end webhook service
+
+	//image service, the breaker state is also reported by the health checks
+	imageUpstream := usecaseImage.NewUpstreamBreaker(cfg, components.clock)
+	imageRepository := repositoryImage.NewImageRepository(db)
+	imageUseCase := usecaseImage.NewImageUseCase(imageRepository, notificationUseCase, webhookUseCase, imageUpstream, components.clock, components.ids, cfg)
+	imageService := servicesImage.NewImageService(imageUseCase)
+	imageServer := serverImage.NewImageServer(imageService)
+	//end image service
+
+	// api key service, keys are also checked by the token interceptor
+	apiKeyRepository := repositoryAPIKey.NewAPIKeyRepository(db)
+	apiKeyUseCase := usecaseAPIKey.NewAPIKeyUseCase(apiKeyRepository, components.clock, components.ids, cfg)
+	apiKeyService := servicesAPIKey.NewAPIKeyService(apiKeyUseCase)
+	apiKeyServer := serverAPIKey.NewAPIKeyServer(apiKeyService)
+	// end api key service
+
+	// organization service, x-org-id is resolved by the token interceptor
+	organizationRepository := repositoryOrganization.NewOrganizationRepository(db)
+	organizationUseCase := usecaseOrganization.NewOrganizationUseCase(organizationRepository, components.clock, components.ids, cfg)
+	organizationService := servicesOrganization.NewOrganizationService(organizationUseCase)
+	organizationServer := serverOrganization.NewOrganizationServer(organizationService)
+	// end organization service
+
+	// health checks, served by grpc.health.v1 and /healthz, /readyz
+	healthServer := health.NewServer()
+	healthMonitor := newHealthMonitor(cfg, healthServer, db, mail, imageUpstream)
+	lifecycleManager.Go("health checks", healthMonitor.Run)
+
+	// http server for JWKS and the probes
+	httpAddr := "0.0.0.0:" + cfg.HTTPPort
+	probeServer := &http.Server{Addr: httpAddr, Handler: httpServer.NewServeMux(components.keyRing, healthMonitor)}
+	go func() {
+		log.Printf("http listening on %s\n", httpAddr)
+		if err := probeServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed on HTTP Serve %v\n", err)
+		}
+	}()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("Error on Listening %v\n", err)
+	}
+
+	defer listener.Close()
+	log.Printf("listening on %s\n", addr)
+
+	// set ssl certificate, TLS_INSECURE serves plaintext for local development
+	options := []grpc.ServerOption{}
+	if cfg.TLSInsecure {
+		log.Println("WARNING: TLS_INSECURE is set, gRPC is served without TLS")
+	} else {
+		creds, err := newServerCredentials(cfg)
+		if err != nil {
+			log.Fatalf("Failed login certificate %v", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	// rate limit store, postgres shares the buckets between replicas
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "postgres" {
+		postgresStore := ratelimit.NewPostgresStore(db)
+		lifecycleManager.Go("rate limit cleanup", func(ctx context.Context) {
+			ticker := time.NewTicker(time.Hour)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				if err := postgresStore.Cleanup(ctx, time.Hour); err != nil {
+					log.Printf("Error cleaning rate limit buckets %v\n", err)
+				}
+			}
+		})
+		rateLimitStore = postgresStore
+	}
+
+	// register middleware, metrics wrap everything to see the final status
+	// codes, errors are converted next so every interceptor benefits, the
+	// token interceptor runs before the rate limiter so it can key
+	// authenticated calls by user, requests are validated last so invalid
+	// ones still count towards the limits
+	options = append(options, grpc.ChainUnaryInterceptor(
+		middleware.MetricsUnaryInterceptor,
+		middleware.ErrorUnaryInterceptor,
+		middleware.NewTokenValidationUnaryInterceptor(components.tokens, apiKeyUseCase, organizationUseCase),
+		middleware.NewRateLimitUnaryInterceptor(rateLimitStore, func() map[string]config.RateLimitRule {
+			return config.Current().RateLimits
+		}),
+		middleware.ValidationUnaryInterceptor,
+	))
+	serverInstance := grpc.NewServer(options...)
+
+	pbAuth.RegisterAuthServiceRoutesServer(serverInstance, authServer)
+	pbImage.RegisterImageServiceServer(serverInstance, imageServer)
+	pbNotification.RegisterNotificationServiceServer(serverInstance, notificationServer)
+	pbWebhook.RegisterWebhookServiceServer(serverInstance, webhookServer)
+	pbAPIKey.RegisterAPIKeyServiceServer(serverInstance, apiKeyServer)
+	pbOrganization.RegisterOrganizationServiceServer(serverInstance, organizationServer)
+	// pbFinance.RegisterFinanceRoutesServiceServer(serverInstance, fincanceServer)
+	// pbBusiness.RegisterBusinessRoutesServiceServer(serverInstance, businessServer)
+
+	// grpc.health.v1, load balancers stop routing here once it is NOT_SERVING
+	healthpb.RegisterHealthServer(serverInstance, healthServer)
+
+	reflection.Register(serverInstance)
+	go func() {
+		if err := serverInstance.Serve(listener); err != nil {
+			log.Fatalf("Failed on Serve %v\n", err)
+		}
+	}()
+
+	// 1. report NOT_SERVING first and give callers time to notice
+	lifecycleManager.OnShutdown("readiness", func(ctx context.Context) error {
+		healthMonitor.Shutdown()
+		select {
+		case <-time.After(cfg.ShutdownReadinessDelay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	// 2. stop accepting, let running RPCs finish until the deadline
+	lifecycleManager.OnShutdown("grpc server", func(ctx context.Context) error {
+		return stopGRPCServer(ctx, serverInstance)
+	})
+	lifecycleManager.OnShutdown("http server", probeServer.Shutdown)
+	// 3. finish claimed emails and webhook deliveries, then generation jobs
+	lifecycleManager.OnShutdown("background workers", lifecycleManager.DrainWorkers)
+	lifecycleManager.OnShutdown("image jobs", imageUseCase.Drain)
+	// 4. the database goes last, everything above may still write to it
+	lifecycleManager.OnShutdown("database", func(context.Context) error {
+		return database.CloseDatabase(db)
+	})
+
+	lifecycleManager.WaitForSignal()
+	return 0
+}
+
+// recordActiveSessions updates the active sessions gauge every minute.
+// Access tokens are not stored, a device seen within their TTL may still
+// hold a valid one.
+func recordActiveSessions(ctx context.Context, repo repositoryAuth.AuthRepository, clock utils.Clock, accessTokenTTL time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		count, err := repo.CountActiveDevices(clock.Now().Add(-accessTokenTTL))
+		if err != nil {
+			log.Printf("Error counting active sessions %v\n", err)
+		} else {
+			metrics.ActiveSessions.Set(float64(count))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopGRPCServer waits for running RPCs and closes their connections when
+// ctx is done first.
+func stopGRPCServer(ctx context.Context, server *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// newServerCredentials serves the certificate files of cfg, they are read
+// again when they change. TLS_CLIENT_AUTH verifies client certificates of
+// service-to-service callers against TLS_CLIENT_CA_FILE.
+func newServerCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	caFile := ""
+	if cfg.TLSClientAuth != "none" {
+		caFile = cfg.TLSClientCAFile
+	}
+	certificates, err := utils.LoadCertificateStore(cfg.TLSCertFile, cfg.TLSKeyFile, caFile, cfg.TLSReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := utils.CipherSuiteIDs(cfg.TLSCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := certificates.ServerConfig(utils.TLSVersions[cfg.TLSMinVersion], cipherSuites, utils.TLSClientAuth[cfg.TLSClientAuth])
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// reloadOnSIGHUP applies config changes on SIGHUP, an invalid config is
+// logged and the running one kept.
+func reloadOnSIGHUP(path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		if _, err := config.Reload(path); err != nil {
+			log.Printf("Config reload rejected, keeping the running config: %v\n", err)
+			continue
+		}
+		log.Println("Config reloaded")
+	}
+}
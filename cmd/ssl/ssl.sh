@@ -6,6 +6,7 @@
 # server.csr: Server certificate signing request (this should be shared with the CA owner)
 # server.crt: Server certificate signed by the CA (this would be sent back by the CA owner) - keep on server
 # server.pem: Conversion of server.key into a format gRPC likes (this shouldn't be shared)
+# client.crt, client.pem: Client certificate and key for mTLS callers (TLS_CLIENT_AUTH)
 
 # Summary 
 # Private files: ca.key, server.key, server.pem, server.crt
@@ -29,4 +30,9 @@ openssl req -passin pass:1111 -new -key server.key -out server.csr -subj ${MY_SU
 openssl x509 -req -extfile <(printf "subjectAltName=DNS:${SERVER_CN},IP:0.0.0.0") -passin pass:1111 -sha256 -days 1095 -in server.csr -CA ca.crt -CAkey ca.key -set_serial 01 -out server.crt 
 
 # Step 5: Convert the server certificate to .pem format (server.pem) - usable by gRPC
-openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
\ No newline at end of file
+openssl pkcs8 -topk8 -nocrypt -passin pass:1111 -in server.key -out server.pem
+
+# Step 6: Generate a client certificate signed by the same CA for mTLS callers
+openssl genrsa -out client.pem 4096
+openssl req -new -key client.pem -out client.csr -subj "/CN=${2:-service}"
+openssl x509 -req -extfile <(printf "extendedKeyUsage=clientAuth") -passin pass:1111 -sha256 -days 1095 -in client.csr -CA ca.crt -CAkey ca.key -set_serial 02 -out client.crt
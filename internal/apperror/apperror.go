@@ -0,0 +1,140 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is reported in every errdetails.ErrorInfo.
+const Domain = "stellar"
+
+// Error is a domain error with a stable reason code. Layers return the
+// predefined errors, optionally wrapping the cause or adding details, and
+// the gRPC layer turns them into a status with ErrorInfo and a localized
+// message.
+type Error struct {
+	code     codes.Code
+	reason   string
+	message  string
+	metadata map[string]string
+	details  []protoadapt.MessageV1
+	cause    error
+}
+
+func New(code codes.Code, reason string, message string) *Error {
+	return &Error{code: code, reason: reason, message: message}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.message + ": " + e.cause.Error()
+	}
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors by reason, so errors.Is(err, apperror.ErrUserNotFound)
+// holds for wrapped copies too.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.reason == e.reason
+}
+
+func (e *Error) Code() codes.Code {
+	return e.code
+}
+
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+func (e *Error) Message() string {
+	return e.message
+}
+
+// Wrap returns a copy of e caused by err, the cause is logged but never sent to clients.
+func (e *Error) Wrap(err error) *Error {
+	copied := e.clone()
+	copied.cause = err
+	return copied
+}
+
+// WithMetadata returns a copy of e with key added to the ErrorInfo metadata.
+func (e *Error) WithMetadata(key string, value string) *Error {
+	copied := e.clone()
+	copied.metadata = map[string]string{}
+	for k, v := range e.metadata {
+		copied.metadata[k] = v
+	}
+	copied.metadata[key] = value
+	return copied
+}
+
+// WithDetails returns a copy of e carrying extra details such as
+// errdetails.BadRequest or errdetails.RetryInfo.
+func (e *Error) WithDetails(details ...protoadapt.MessageV1) *Error {
+	copied := e.clone()
+	copied.details = append(append([]protoadapt.MessageV1{}, e.details...), details...)
+	return copied
+}
+
+// GRPCStatus lets status.FromError understand Error, the message is not localized.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.Status(i18n.DefaultLocale)
+}
+
+// Status builds the client facing status with the message in locale.
+func (e *Error) Status(locale string) *status.Status {
+	locale, message := localize(e, locale)
+	st := status.New(e.code, message)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{Reason: e.reason, Domain: Domain, Metadata: e.metadata},
+		&errdetails.LocalizedMessage{Locale: locale, Message: message},
+	}
+	detailed, err := st.WithDetails(append(details, e.details...)...)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+func (e *Error) clone() *Error {
+	copied := *e
+	return &copied
+}
+
+// Internal wraps an unexpected error, clients only see "Internal Server Error".
+func Internal(err error) *Error {
+	return ErrInternal.Wrap(err)
+}
+
+// ToStatus converts any error returned by a handler into a client facing
+// status. Unknown errors are logged and reported as internal errors.
+func ToStatus(err error, locale string) *status.Status {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		if appErr.code == codes.Internal || appErr.code == codes.Unknown {
+			log.Printf("Internal error: %v", err)
+		}
+		return appErr.Status(locale)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	log.Printf("Internal error: %v", err)
+	return ErrInternal.Status(locale)
+}
@@ -0,0 +1,99 @@
+package apperror
+
+import "google.golang.org/grpc/codes"
+
+// General
+var (
+	ErrInternal         = New(codes.Internal, "INTERNAL", "Internal Server Error")
+	ErrInvalidRequest   = New(codes.InvalidArgument, "INVALID_REQUEST", "Invalid Request")
+	ErrTooManyRequests  = New(codes.ResourceExhausted, "RATE_LIMITED", "Too Many Requests")
+	ErrPermissionDenied = New(codes.PermissionDenied, "PERMISSION_DENIED", "Permission Denied")
+)
+
+// Authentication
+var (
+	ErrUnauthenticated      = New(codes.Unauthenticated, "UNAUTHENTICATED", "Unauthenticated")
+	ErrInvalidAccessToken   = New(codes.Unauthenticated, "INVALID_ACCESS_TOKEN", "Invalid Or Missing Token")
+	ErrTokenNotAllowed      = New(codes.PermissionDenied, "TOKEN_NOT_ALLOWED", "Token Not Valid For This Method")
+	ErrInvalidCredentials   = New(codes.Unauthenticated, "INVALID_CREDENTIALS", "Invalid Email Or Password")
+	ErrUserNotVerified      = New(codes.Unauthenticated, "USER_NOT_VERIFIED", "User Not Verified")
+	ErrAccountDisabled      = New(codes.PermissionDenied, "ACCOUNT_DISABLED", "Account Disabled")
+	ErrAdminBootstrapClosed = New(codes.FailedPrecondition, "ADMIN_BOOTSTRAP_CLOSED", "An Admin Already Exists, Ask For An Invitation")
+	ErrPasswordPolicy       = New(codes.InvalidArgument, "PASSWORD_POLICY_VIOLATION", "Password Does Not Meet Policy")
+	ErrInvalidToken         = New(codes.NotFound, "INVALID_TOKEN", "Invalid Or Expired Token")
+	ErrUserNotFound         = New(codes.NotFound, "USER_NOT_FOUND", "User Not Found")
+	ErrUserAlreadyExists    = New(codes.AlreadyExists, "USER_ALREADY_EXISTS", "User Already Exists")
+	ErrRecoveryCodeNotFound = New(codes.NotFound, "RECOVERY_CODE_NOT_FOUND", "Recovery Code Not Found")
+)
+
+// Two-factor authentication
+var (
+	ErrMFAAlreadyEnabled       = New(codes.FailedPrecondition, "MFA_ALREADY_ENABLED", "2FA Already Enabled")
+	ErrMFANotEnabled           = New(codes.FailedPrecondition, "MFA_NOT_ENABLED", "2FA Not Enabled")
+	ErrMFAEnrollmentNotStarted = New(codes.FailedPrecondition, "MFA_ENROLLMENT_NOT_STARTED", "2FA Enrollment Not Started")
+	ErrMFAMandatory            = New(codes.FailedPrecondition, "MFA_MANDATORY", "2FA Is Mandatory For Admin")
+	ErrInvalidMFACode          = New(codes.Unauthenticated, "INVALID_MFA_CODE", "Invalid 2FA Code")
+	ErrInvalidMFAToken         = New(codes.Unauthenticated, "INVALID_MFA_TOKEN", "Invalid MFA Token")
+)
+
+// Social login
+var (
+	ErrUnknownProvider                = New(codes.InvalidArgument, "UNKNOWN_PROVIDER", "Unknown Login Provider")
+	ErrProviderUnavailable            = New(codes.Unavailable, "PROVIDER_UNAVAILABLE", "Login Provider Unavailable")
+	ErrProviderLoginFailed            = New(codes.Unauthenticated, "PROVIDER_LOGIN_FAILED", "Provider Login Failed")
+	ErrProviderEmailNotVerified       = New(codes.PermissionDenied, "PROVIDER_EMAIL_NOT_VERIFIED", "Provider Email Not Verified")
+	ErrInvalidOAuthState              = New(codes.InvalidArgument, "INVALID_OAUTH_STATE", "Invalid OAuth State")
+	ErrIdentityNotFound               = New(codes.NotFound, "IDENTITY_NOT_FOUND", "Identity Not Found")
+	ErrIdentityAlreadyLinked          = New(codes.AlreadyExists, "IDENTITY_ALREADY_LINKED", "Identity Already Linked")
+	ErrIdentityLinkedToAnotherAccount = New(codes.AlreadyExists, "IDENTITY_LINKED_TO_ANOTHER_ACCOUNT", "Identity Linked To Another Account")
+	ErrLastLoginMethod                = New(codes.FailedPrecondition, "LAST_LOGIN_METHOD", "Cannot Unlink The Only Login Method")
+)
+
+// Image generation
+var (
+	ErrImageGenerationFailed      = New(codes.Unavailable, "IMAGE_GENERATION_FAILED", "Image Generation Failed")
+	ErrImageGenerationUnavailable = New(codes.Unavailable, "IMAGE_GENERATION_UNAVAILABLE", "Image Generation Is Temporarily Unavailable")
+	ErrImageDownloadFailed        = New(codes.Unavailable, "IMAGE_DOWNLOAD_FAILED", "Image Download Failed")
+	ErrImageJobNotFound           = New(codes.NotFound, "IMAGE_JOB_NOT_FOUND", "Image Job Not Found")
+	ErrQuotaExceeded              = New(codes.ResourceExhausted, "QUOTA_EXCEEDED", "Generation Quota Exceeded")
+)
+
+// Notifications
+var (
+	ErrUnknownNotificationEvent = New(codes.InvalidArgument, "UNKNOWN_NOTIFICATION_EVENT", "Unknown Notification Event")
+	ErrInvalidUnsubscribeToken  = New(codes.InvalidArgument, "INVALID_UNSUBSCRIBE_TOKEN", "Invalid Unsubscribe Link")
+)
+
+// Webhooks
+var (
+	ErrWebhookNotFound         = New(codes.NotFound, "WEBHOOK_NOT_FOUND", "Webhook Not Found")
+	ErrWebhookDeliveryNotFound = New(codes.NotFound, "WEBHOOK_DELIVERY_NOT_FOUND", "Webhook Delivery Not Found")
+	ErrInvalidWebhookURL       = New(codes.InvalidArgument, "INVALID_WEBHOOK_URL", "Invalid Webhook URL")
+	ErrUnknownWebhookEvent     = New(codes.InvalidArgument, "UNKNOWN_WEBHOOK_EVENT", "Unknown Webhook Event")
+	ErrWebhookLimitReached     = New(codes.ResourceExhausted, "WEBHOOK_LIMIT_REACHED", "Webhook Limit Reached")
+)
+
+// API keys
+var (
+	ErrInvalidAPIKey        = New(codes.Unauthenticated, "INVALID_API_KEY", "Invalid API Key")
+	ErrAPIKeyNotFound       = New(codes.NotFound, "API_KEY_NOT_FOUND", "API Key Not Found")
+	ErrAPIKeyLimitReached   = New(codes.ResourceExhausted, "API_KEY_LIMIT_REACHED", "API Key Limit Reached")
+	ErrUnknownAPIKeyScope   = New(codes.InvalidArgument, "UNKNOWN_API_KEY_SCOPE", "Unknown API Key Scope")
+	ErrInvalidAPIKeyExpiry  = New(codes.InvalidArgument, "INVALID_API_KEY_EXPIRY", "Invalid API Key Expiry")
+	ErrInsufficientScope    = New(codes.PermissionDenied, "INSUFFICIENT_SCOPE", "API Key Is Missing The Required Scope")
+	ErrAmbiguousCredentials = New(codes.InvalidArgument, "AMBIGUOUS_CREDENTIALS", "Send Either An Access Token Or An API Key")
+)
+
+// Organizations
+var (
+	ErrOrganizationNotFound     = New(codes.NotFound, "ORGANIZATION_NOT_FOUND", "Organization Not Found")
+	ErrNotOrganizationMember    = New(codes.PermissionDenied, "NOT_ORGANIZATION_MEMBER", "Not A Member Of The Organization")
+	ErrOrganizationRole         = New(codes.PermissionDenied, "ORGANIZATION_ROLE_REQUIRED", "Organization Role Does Not Allow This")
+	ErrMemberNotFound           = New(codes.NotFound, "MEMBER_NOT_FOUND", "Member Not Found")
+	ErrAlreadyMember            = New(codes.AlreadyExists, "ALREADY_MEMBER", "Already A Member Of The Organization")
+	ErrLastOwner                = New(codes.FailedPrecondition, "LAST_OWNER", "Organization Must Keep At Least One Owner")
+	ErrInvitationNotFound       = New(codes.NotFound, "INVITATION_NOT_FOUND", "Invitation Not Found")
+	ErrInvalidInvitation        = New(codes.InvalidArgument, "INVALID_INVITATION", "Invalid Or Expired Invitation")
+	ErrOrganizationRequired     = New(codes.InvalidArgument, "ORGANIZATION_REQUIRED", "Select An Organization With x-org-id")
+	ErrOrganizationLimitReached = New(codes.ResourceExhausted, "ORGANIZATION_LIMIT_REACHED", "Organization Limit Reached")
+)
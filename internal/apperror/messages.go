@@ -0,0 +1,14 @@
+package apperror
+
+import "github.com/oriastanjung/stellar/internal/i18n"
+
+// localize returns the locale actually used and the message in it. The
+// catalogs translate "error.<REASON>", the English message of the error
+// is the fallback.
+func localize(e *Error, locale string) (string, string) {
+	locale = i18n.Normalize(locale)
+	if translated, ok := i18n.Lookup(locale, "error."+e.reason); ok {
+		return locale, translated
+	}
+	return i18n.DefaultLocale, e.message
+}
@@ -1,76 +1,412 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io/fs"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Config holds every setting of the service. Fields tagged secret are
+// masked by String so the config can be logged.
 type Config struct {
 	IMAGE_GENERATION_MODEL          string
 	IMAGE_API_GENERATION_URL        string
 	IMAGE_API_GENERATION_ORIGIN     string
 	IMAGE_API_GENERATION_VALIDATED  string
 	IMAGE_API_GENERATION_MESSAGE_ID string
+	ImageGenerationWorkers          int
+	ImageGenerationQuota            int
+	ImageGenerationQuotaPeriod      time.Duration
+	ImageGenerationQuotaWarning     int
+	ImageStorageDir                 string
+	ImageAPIBreakerThreshold        int
+	ImageAPIBreakerCooldown         time.Duration
+	ImageAPITimeout                 time.Duration
+	ImageDownloadTimeout            time.Duration
 	Port                            string
-	DatabaseURL                     string
-	JWTSecretKey                    string
+	HTTPPort                        string
+	TLSInsecure                     bool
+	TLSCertFile                     string
+	TLSKeyFile                      string
+	TLSMinVersion                   string
+	TLSCipherSuites                 []string
+	TLSClientAuth                   string
+	TLSClientCAFile                 string
+	TLSReloadInterval               time.Duration
+	ShutdownTimeout                 time.Duration
+	ShutdownReadinessDelay          time.Duration
+	HealthCheckInterval             time.Duration
+	HealthCheckTimeout              time.Duration
+	DatabaseURL                     string `secret:"url"`
+	DatabaseAutoMigrate             bool
+	JWTIssuer                       string
+	JWTSigningAlgorithm             string
+	JWTKeysDir                      string
+	JWTKeyOverlap                   time.Duration
+	JWTAccessTokenTTL               time.Duration
+	JWTEncryptionEnabled            bool
+	MFAIssuer                       string
+	MFAChallengeTTL                 time.Duration
+	AdminMFARequired                bool
+	AdminBootstrapToken             string `secret:"true"`
+	AdminInvitationTTL              time.Duration
+	AdminInvitationMaxTTL           time.Duration
+	AdminInvitationLink             string
 	BcryptSalt                      int
 	GoogleAuthClientID              string
-	GoogleAuthClientSecret          string
+	GoogleAuthClientSecret          string `secret:"true"`
 	GoogleAuthRedirectURL           string
-	GoogleOAuthStateString          string
-	AESSecretKey                    string
-	GmailEmail                      string
-	GmailPassword                   string
+	GoogleOAuthIssuer               string
+	OAuthProviders                  []OAuthProviderConfig
+	OAuthStateTTL                   time.Duration
+	AESSecretKey                    string `secret:"true"`
+	MailDriver                      string
+	MailFrom                        string
+	MailFileDir                     string
+	SMTPHost                        string
+	SMTPPort                        int
+	SMTPUsername                    string
+	SMTPPassword                    string `secret:"true"`
+	SMTPTLS                         string
+	MailOutboxPollInterval          time.Duration
+	MailOutboxBatchSize             int
+	MailOutboxMaxAttempts           int
 	EmailVerificationLink           string
 	EmailForgetPasswordFrontendLink string
+	EmailUnlockAccountLink          string
+	NotificationUnsubscribeLink     string
+	WebhookMaxPerUser               int
+	WebhookAllowInsecureURL         bool
+	WebhookTimeout                  time.Duration
+	WebhookPollInterval             time.Duration
+	WebhookBatchSize                int
+	WebhookMaxAttempts              int
+	APIKeyMaxPerUser                int
+	APIKeyLastUsedInterval          time.Duration
+	OrganizationMaxPerUser          int
+	OrganizationGenerationQuota     int
+	OrganizationInvitationTTL       time.Duration
+	OrganizationInvitationLink      string
+	VerificationTokenTTL            time.Duration
+	ResetPasswordTokenTTL           time.Duration
+	UnlockAccountTokenTTL           time.Duration
+	VerificationResendCooldown      time.Duration
+	PasswordMinLength               int
+	PasswordMaxLength               int
+	PasswordRequireUpper            bool
+	PasswordRequireLower            bool
+	PasswordRequireDigit            bool
+	PasswordRequireSymbol           bool
+	PasswordCheckBreached           bool
+	PasswordBreachedListFile        string
+	RateLimitStore                  string
+	RateLimits                      map[string]RateLimitRule
+	LoginLockoutThreshold           int
+	LoginLockoutBase                time.Duration
+	LoginLockoutMax                 time.Duration
 }
 
-func LoadEnv() *Config {
-	// load .env file
-	err := godotenv.Load()
+// Load reads the optional YAML or TOML file at path, .env and the
+// environment, in increasing priority, and validates the result. It is
+// called once per process, the config is then passed to whatever needs it.
+func Load(path string) (*Config, error) {
+	l, err := newLoader(path)
 	if err != nil {
-		fmt.Println("Error loading .env file")
+		return nil, err
 	}
-	// Konversi SALT_KEY dari string ke int
-	saltKey, err := strconv.Atoi(getEnv("SALT_KEY", "12")) // Default 12
+	config := l.load()
+	if err := errors.Join(l.errs...); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadDatabase reads and validates only DATABASE_URL, for the commands
+// that do nothing but open the database such as `stellar migrate`, so they
+// run without the secrets and mail settings of the server.
+func LoadDatabase(path string) (*Config, error) {
+	l, err := newLoader(path)
 	if err != nil {
-		log.Fatalf("Invalid SALT_KEY value: %v", err)
+		return nil, err
+	}
+	config := &Config{DatabaseURL: l.get("DATABASE_URL", "")}
+	v := &validator{}
+	v.database(config)
+	if err := errors.Join(v.errs...); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func newLoader(path string) (*loader, error) {
+	// .env is optional, deployments usually set real environment variables.
+	// It is read instead of loaded into the environment so Reload sees edits.
+	dotenv, err := godotenv.Read()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading .env: %w", err)
+	}
+	l := &loader{dotenv: dotenv}
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file = values
 	}
+	return l, nil
+}
+
+func (l *loader) load() *Config {
 	config := &Config{
-		IMAGE_GENERATION_MODEL:          getEnv("IMAGE_GENERATION_MODEL", ""),
-		IMAGE_API_GENERATION_URL:        getEnv("IMAGE_API_GENERATION_URL", ""),
-		IMAGE_API_GENERATION_ORIGIN:     getEnv("IMAGE_API_GENERATION_ORIGIN", ""),
-		IMAGE_API_GENERATION_VALIDATED:  getEnv("IMAGE_API_GENERATION_VALIDATED", ""),
-		IMAGE_API_GENERATION_MESSAGE_ID: getEnv("IMAGE_API_GENERATION_MESSAGE_ID", ""),
-		Port:                            getEnv("PORT", "2701"), // defaultnya 3000,
-		DatabaseURL:                     getEnv("DATABASE_URL", ""),
-		JWTSecretKey:                    getEnv("JWT_SECRET_KEY", ""),
-		AESSecretKey:                    getEnv("AES_SECRET_KEY", ""),
-		GoogleAuthClientID:              getEnv("GOOGLE_AUTH_CLIENT_ID", ""),
-		GoogleAuthClientSecret:          getEnv("GOOGLE_AUTH_CLIENT_SECRET", ""),
-		GoogleAuthRedirectURL:           getEnv("GOOGLE_AUTH_REDIRECT_URL", ""),
-		GoogleOAuthStateString:          getEnv("GOOGLE_OAUTH_STATE_STRING", ""),
-		GmailEmail:                      getEnv("GMAIL_EMAIL", ""),
-		GmailPassword:                   getEnv("GMAIL_PASSWORD", ""),
-		EmailVerificationLink:           getEnv("EMAIL_VERIFICATION_LINK", ""),
-		EmailForgetPasswordFrontendLink: getEnv("EMAIL_FORGET_PASSWORD_FRONTEND_LINK", ""),
-		BcryptSalt:                      saltKey,
+		IMAGE_GENERATION_MODEL:          l.get("IMAGE_GENERATION_MODEL", ""),
+		IMAGE_API_GENERATION_URL:        l.get("IMAGE_API_GENERATION_URL", ""),
+		IMAGE_API_GENERATION_ORIGIN:     l.get("IMAGE_API_GENERATION_ORIGIN", ""),
+		IMAGE_API_GENERATION_VALIDATED:  l.get("IMAGE_API_GENERATION_VALIDATED", ""),
+		IMAGE_API_GENERATION_MESSAGE_ID: l.get("IMAGE_API_GENERATION_MESSAGE_ID", ""),
+		ImageGenerationWorkers:          l.int("IMAGE_GENERATION_WORKERS", "4"),
+		ImageGenerationQuota:            l.int("IMAGE_GENERATION_QUOTA", "100"), // 0 = tanpa batas
+		ImageGenerationQuotaPeriod:      l.duration("IMAGE_GENERATION_QUOTA_PERIOD", "720h"),
+		ImageGenerationQuotaWarning:     l.int("IMAGE_GENERATION_QUOTA_WARNING_PERCENT", "80"),
+		ImageStorageDir:                 l.get("IMAGE_STORAGE_DIR", "../public"),
+		ImageAPIBreakerThreshold:        l.int("IMAGE_API_BREAKER_THRESHOLD", "5"), // gagal berturut-turut sebelum upstream tidak dipanggil
+		ImageAPIBreakerCooldown:         l.duration("IMAGE_API_BREAKER_COOLDOWN", "30s"),
+		ImageAPITimeout:                 l.duration("IMAGE_API_TIMEOUT", "2m"),
+		ImageDownloadTimeout:            l.duration("IMAGE_DOWNLOAD_TIMEOUT", "1m"),
+		Port:                            l.get("PORT", "2701"), // defaultnya 3000,
+		HTTPPort:                        l.get("HTTP_PORT", "2702"),
+		TLSInsecure:                     l.bool("TLS_INSECURE", "false"), // true = gRPC tanpa TLS, hanya untuk development
+		TLSCertFile:                     l.get("TLS_CERT_FILE", "ssl/server.crt"),
+		TLSKeyFile:                      l.get("TLS_KEY_FILE", "ssl/server.pem"),
+		TLSMinVersion:                   l.get("TLS_MIN_VERSION", "1.2"),
+		TLSClientAuth:                   l.get("TLS_CLIENT_AUTH", "none"),
+		TLSClientCAFile:                 l.get("TLS_CLIENT_CA_FILE", "ssl/ca.crt"),
+		TLSReloadInterval:               l.duration("TLS_RELOAD_INTERVAL", "30s"),
+		ShutdownTimeout:                 l.duration("SHUTDOWN_TIMEOUT", "30s"),
+		ShutdownReadinessDelay:          l.duration("SHUTDOWN_READINESS_DELAY", "0s"), // beri waktu load balancer melihat NOT_SERVING
+		HealthCheckInterval:             l.duration("HEALTH_CHECK_INTERVAL", "10s"),
+		HealthCheckTimeout:              l.duration("HEALTH_CHECK_TIMEOUT", "3s"),
+		DatabaseURL:                     l.get("DATABASE_URL", ""),
+		DatabaseAutoMigrate:             l.bool("DATABASE_AUTO_MIGRATE", "true"), // false = jalankan `stellar migrate up` saat deploy
+		JWTIssuer:                       l.get("JWT_ISSUER", "stellar"),
+		JWTSigningAlgorithm:             l.get("JWT_SIGNING_ALGORITHM", "EdDSA"),
+		JWTKeysDir:                      l.get("JWT_KEYS_DIR", "keys"),
+		JWTKeyOverlap:                   l.duration("JWT_KEY_OVERLAP", "24h"),
+		JWTAccessTokenTTL:               l.duration("JWT_ACCESS_TOKEN_TTL", "3h"),
+		JWTEncryptionEnabled:            l.bool("JWT_ENCRYPTION_ENABLED", "false"),
+		AESSecretKey:                    l.get("AES_SECRET_KEY", ""),
+		MFAIssuer:                       l.get("MFA_ISSUER", "Stellar"),
+		MFAChallengeTTL:                 l.duration("MFA_CHALLENGE_TTL", "5m"),
+		AdminMFARequired:                l.bool("ADMIN_MFA_REQUIRED", "true"),
+		AdminBootstrapToken:             l.get("ADMIN_BOOTSTRAP_TOKEN", ""), // kosong = hanya lewat undangan
+		AdminInvitationTTL:              l.duration("ADMIN_INVITATION_TTL", "72h"),
+		AdminInvitationMaxTTL:           l.duration("ADMIN_INVITATION_MAX_TTL", "720h"),
+		AdminInvitationLink:             l.get("ADMIN_INVITATION_LINK", ""),
+		GoogleAuthClientID:              l.get("GOOGLE_AUTH_CLIENT_ID", ""),
+		GoogleAuthClientSecret:          l.get("GOOGLE_AUTH_CLIENT_SECRET", ""),
+		GoogleAuthRedirectURL:           l.get("GOOGLE_AUTH_REDIRECT_URL", ""),
+		GoogleOAuthIssuer:               l.get("GOOGLE_OAUTH_ISSUER", "https://accounts.google.com"),
+		OAuthStateTTL:                   l.duration("OAUTH_STATE_TTL", "10m"),
+		MailDriver:                      l.get("MAIL_DRIVER", "smtp"),
+		MailFileDir:                     l.get("MAIL_FILE_DIR", "mail"),
+		SMTPHost:                        l.get("SMTP_HOST", "smtp.gmail.com"),
+		SMTPPort:                        l.int("SMTP_PORT", "587"),
+		SMTPUsername:                    l.get("SMTP_USERNAME", ""),
+		SMTPPassword:                    l.get("SMTP_PASSWORD", ""),
+		SMTPTLS:                         l.get("SMTP_TLS", "starttls"),
+		MailOutboxPollInterval:          l.duration("MAIL_OUTBOX_POLL_INTERVAL", "5s"),
+		MailOutboxBatchSize:             l.int("MAIL_OUTBOX_BATCH_SIZE", "20"),
+		MailOutboxMaxAttempts:           l.int("MAIL_OUTBOX_MAX_ATTEMPTS", "8"),
+		EmailVerificationLink:           l.get("EMAIL_VERIFICATION_LINK", ""),
+		EmailForgetPasswordFrontendLink: l.get("EMAIL_FORGET_PASSWORD_FRONTEND_LINK", ""),
+		EmailUnlockAccountLink:          l.get("EMAIL_UNLOCK_ACCOUNT_LINK", ""),
+		NotificationUnsubscribeLink:     l.get("NOTIFICATION_UNSUBSCRIBE_LINK", ""),
+		WebhookMaxPerUser:               l.int("WEBHOOK_MAX_PER_USER", "10"),
+		WebhookAllowInsecureURL:         l.bool("WEBHOOK_ALLOW_INSECURE_URL", "false"),
+		WebhookTimeout:                  l.duration("WEBHOOK_TIMEOUT", "10s"),
+		WebhookPollInterval:             l.duration("WEBHOOK_POLL_INTERVAL", "5s"),
+		WebhookBatchSize:                l.int("WEBHOOK_BATCH_SIZE", "20"),
+		WebhookMaxAttempts:              l.int("WEBHOOK_MAX_ATTEMPTS", "10"),
+		APIKeyMaxPerUser:                l.int("API_KEY_MAX_PER_USER", "20"),
+		APIKeyLastUsedInterval:          l.duration("API_KEY_LAST_USED_INTERVAL", "1m"),
+		OrganizationMaxPerUser:          l.int("ORGANIZATION_MAX_PER_USER", "3"),
+		OrganizationGenerationQuota:     l.int("ORGANIZATION_GENERATION_QUOTA", "1000"), // 0 = tanpa batas
+		OrganizationInvitationTTL:       l.duration("ORGANIZATION_INVITATION_TTL", "168h"),
+		OrganizationInvitationLink:      l.get("ORGANIZATION_INVITATION_LINK", ""),
+		VerificationTokenTTL:            l.duration("VERIFICATION_TOKEN_TTL", "24h"),
+		ResetPasswordTokenTTL:           l.duration("RESET_PASSWORD_TOKEN_TTL", "1h"),
+		UnlockAccountTokenTTL:           l.duration("UNLOCK_ACCOUNT_TOKEN_TTL", "24h"),
+		VerificationResendCooldown:      l.duration("VERIFICATION_RESEND_COOLDOWN", "1m"),
+		PasswordMinLength:               l.int("PASSWORD_MIN_LENGTH", "8"),
+		PasswordMaxLength:               l.int("PASSWORD_MAX_LENGTH", "72"),
+		PasswordRequireUpper:            l.bool("PASSWORD_REQUIRE_UPPER", "true"),
+		PasswordRequireLower:            l.bool("PASSWORD_REQUIRE_LOWER", "true"),
+		PasswordRequireDigit:            l.bool("PASSWORD_REQUIRE_DIGIT", "true"),
+		PasswordRequireSymbol:           l.bool("PASSWORD_REQUIRE_SYMBOL", "false"),
+		PasswordCheckBreached:           l.bool("PASSWORD_CHECK_BREACHED", "true"),
+		PasswordBreachedListFile:        l.get("PASSWORD_BREACHED_LIST_FILE", ""),
+		RateLimitStore:                  l.get("RATE_LIMIT_STORE", "memory"),
+		RateLimits:                      l.rateLimits(l.get("RATE_LIMITS", "")),
+		LoginLockoutThreshold:           l.int("LOGIN_LOCKOUT_THRESHOLD", "5"),
+		LoginLockoutBase:                l.duration("LOGIN_LOCKOUT_BASE", "1m"),
+		LoginLockoutMax:                 l.duration("LOGIN_LOCKOUT_MAX", "24h"),
+		BcryptSalt:                      l.int("SALT_KEY", "12"),
+	}
+	config.OAuthProviders = l.oauthProviders(config)
+	// kosong = cipher suite bawaan Go
+	if suites := l.get("TLS_CIPHER_SUITES", ""); suites != "" {
+		for _, suite := range strings.Split(suites, ",") {
+			config.TLSCipherSuites = append(config.TLSCipherSuites, strings.TrimSpace(suite))
+		}
+	}
+	// GMAIL_* are still read for existing deployments
+	if config.SMTPUsername == "" {
+		config.SMTPUsername, _ = l.lookup("GMAIL_EMAIL")
+	}
+	if config.SMTPPassword == "" {
+		config.SMTPPassword, _ = l.lookup("GMAIL_PASSWORD")
 	}
+	config.MailFrom = l.get("MAIL_FROM", config.SMTPUsername)
 
 	return config
 }
 
-func getEnv(key, fallback string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		log.Printf("Environment variable %s not set, defaulting to %s", key, fallback)
-		value = fallback
+// OAuthProviderConfig describes one social login provider. Type "oidc"
+// discovers endpoints from Issuer, type "github" uses plain OAuth2 with
+// the GitHub REST API at APIURL.
+type OAuthProviderConfig struct {
+	Name         string
+	Type         string
+	Issuer       string
+	APIURL       string
+	ClientID     string
+	ClientSecret string `secret:"true"`
+	RedirectURL  string
+	Scopes       []string
+}
+
+// oauthProviders reads OAUTH_PROVIDERS=name,... with OAUTH_<NAME>_*
+// settings per provider. Google keeps its GOOGLE_AUTH_* variables.
+func (l *loader) oauthProviders(cfg *Config) []OAuthProviderConfig {
+	providers := []OAuthProviderConfig{}
+	if cfg.GoogleAuthClientID != "" {
+		providers = append(providers, OAuthProviderConfig{
+			Name:         "google",
+			Type:         "oidc",
+			Issuer:       cfg.GoogleOAuthIssuer,
+			ClientID:     cfg.GoogleAuthClientID,
+			ClientSecret: cfg.GoogleAuthClientSecret,
+			RedirectURL:  cfg.GoogleAuthRedirectURL,
+		})
 	}
-	return value
 
+	for _, name := range strings.Split(l.get("OAUTH_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "google" {
+			continue
+		}
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+		defaultType, defaultIssuer := "oidc", ""
+		if name == "github" {
+			defaultType, defaultIssuer = "github", "https://github.com"
+		}
+
+		provider := OAuthProviderConfig{
+			Name:         name,
+			Type:         l.get(prefix+"TYPE", defaultType),
+			Issuer:       l.get(prefix+"ISSUER", defaultIssuer),
+			APIURL:       l.get(prefix+"API_URL", "https://api.github.com"),
+			ClientID:     l.get(prefix+"CLIENT_ID", ""),
+			ClientSecret: l.get(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  l.get(prefix+"REDIRECT_URL", ""),
+		}
+		if scopes := l.get(prefix+"SCOPES", ""); scopes != "" {
+			provider.Scopes = strings.Split(scopes, ",")
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// RateLimitRule allows Rate requests per second with bursts up to Burst,
+// applied separately per peer IP, email and user ID.
+type RateLimitRule struct {
+	Rate  float64
+	Burst int
+}
+
+// defaultRateLimits are keyed by RPC method name.
+var defaultRateLimits = map[string]string{
+	"LoginUser":               "10/1m:10",
+	"LoginAdmin":              "10/1m:10",
+	"VerifyMFA":               "10/1m:10",
+	"RequestForgetPassword":   "3/10m:3",
+	"SignUpUser":              "5/10m:5",
+	"SignUpAdmin":             "5/10m:5",
+	"UnlockAccount":           "10/1m:10",
+	"ResendVerificationEmail": "3/10m:3",
+	"Unsubscribe":             "10/1m:10",
+	"RedeliverWebhook":        "10/1m:10",
+	"InviteMember":            "20/1h:10",
+	"AcceptInvitation":        "10/1m:10",
+}
+
+// rateLimits parses RATE_LIMITS="Method=count/period:burst;..." on top
+// of the defaults, e.g. "LoginUser=5/1m:5;SignUpUser=2/1h:2".
+func (l *loader) rateLimits(raw string) map[string]RateLimitRule {
+	specs := map[string]string{}
+	for method, spec := range defaultRateLimits {
+		specs[method] = spec
+	}
+	for _, entry := range strings.Split(raw, ";") {
+		method, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			if entry != "" {
+				l.errs = append(l.errs, fmt.Errorf("invalid RATE_LIMITS entry: %s", entry))
+			}
+			continue
+		}
+		specs[strings.TrimSpace(method)] = strings.TrimSpace(spec)
+	}
+
+	rules := map[string]RateLimitRule{}
+	for method, spec := range specs {
+		rule, err := parseRateLimitRule(spec)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("invalid RATE_LIMITS value for %s: %w", method, err))
+			continue
+		}
+		rules[method] = rule
+	}
+	return rules
+}
+
+func parseRateLimitRule(spec string) (RateLimitRule, error) {
+	spec, burstText, hasBurst := strings.Cut(spec, ":")
+	countText, periodText, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RateLimitRule{}, fmt.Errorf("expected count/period[:burst], got %q", spec)
+	}
+	count, err := strconv.Atoi(countText)
+	if err != nil {
+		return RateLimitRule{}, err
+	}
+	period, err := time.ParseDuration(periodText)
+	if err != nil || period <= 0 {
+		return RateLimitRule{}, fmt.Errorf("invalid period %q", periodText)
+	}
+	burst := count
+	if hasBurst {
+		if burst, err = strconv.Atoi(burstText); err != nil {
+			return RateLimitRule{}, err
+		}
+	}
+	return RateLimitRule{Rate: float64(count) / period.Seconds(), Burst: burst}, nil
 }
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// loader looks keys up in the environment, then .env and then the config
+// file, parse errors are collected so Load reports all of them at once.
+type loader struct {
+	dotenv map[string]string
+	file   map[string]string
+	errs   []error
+}
+
+func (l *loader) lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	if value, ok := l.dotenv[key]; ok {
+		return value, true
+	}
+	value, ok := l.file[key]
+	return value, ok
+}
+
+func (l *loader) get(key, fallback string) string {
+	if value, ok := l.lookup(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (l *loader) duration(key, fallback string) time.Duration {
+	value, err := time.ParseDuration(l.get(key, fallback))
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s value: %w", key, err))
+	}
+	return value
+}
+
+func (l *loader) int(key, fallback string) int {
+	value, err := strconv.Atoi(l.get(key, fallback))
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s value: %w", key, err))
+	}
+	return value
+}
+
+func (l *loader) bool(key, fallback string) bool {
+	value, err := strconv.ParseBool(l.get(key, fallback))
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s value: %w", key, err))
+	}
+	return value
+}
+
+// readFile reads a YAML or TOML config file into environment variable
+// names: nested keys are joined with underscores and upper cased, so
+//
+//	jwt:
+//	  access_token_ttl: 3h
+//
+// or
+//
+//	[jwt]
+//	access_token_ttl = "3h"
+//
+// sets JWT_ACCESS_TOKEN_TTL, and lists are joined with commas. rate_limits
+// may be a map of method to count/period:burst.
+func readFile(path string) (map[string]string, error) {
+	var unmarshal func(data []byte, v any) error
+	switch strings.ToLower(path[strings.LastIndex(path, ".")+1:]) {
+	case "yaml", "yml":
+		unmarshal = yaml.Unmarshal
+	case "toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return nil, fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	var root map[string]any
+	if err := unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	values := map[string]string{}
+	if err := flatten(values, "", root); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(values map[string]string, prefix string, node any) error {
+	switch node := node.(type) {
+	case map[string]any:
+		if prefix == "RATE_LIMITS" {
+			entries := []string{}
+			for method, spec := range node {
+				entries = append(entries, fmt.Sprintf("%s=%v", method, spec))
+			}
+			sort.Strings(entries)
+			values[prefix] = strings.Join(entries, ";")
+			return nil
+		}
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			if err := flatten(values, name, node[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items := make([]string, 0, len(node))
+		for _, item := range node {
+			switch item.(type) {
+			case map[string]any, []any:
+				return fmt.Errorf("%s: lists may only hold plain values", prefix)
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(node)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// String lists every field on its own line with secrets masked, it is what
+// the server logs at startup. GoString keeps %#v from bypassing it.
+func (cfg *Config) String() string {
+	var b strings.Builder
+	value := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fmt.Fprintf(&b, "%s=%s\n", field.Name, redact(field.Tag.Get("secret"), value.Field(i)))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (cfg *Config) GoString() string {
+	return cfg.String()
+}
+
+func (provider OAuthProviderConfig) String() string {
+	secret := ""
+	if provider.ClientSecret != "" {
+		secret = redacted
+	}
+	return fmt.Sprintf("{%s %s issuer=%s client=%s secret=%s redirect=%s scopes=%v}",
+		provider.Name, provider.Type, provider.Issuer, provider.ClientID, secret, provider.RedirectURL, provider.Scopes)
+}
+
+func (provider OAuthProviderConfig) GoString() string {
+	return provider.String()
+}
+
+func redact(tag string, value reflect.Value) string {
+	switch tag {
+	case "true":
+		if value.String() == "" {
+			return ""
+		}
+		return redacted
+	case "url":
+		return redactURL(value.String())
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// redactURL keeps the host and database of a DSN but masks the password,
+// key=value DSNs are masked completely.
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		if raw == "" {
+			return ""
+		}
+		return redacted
+	}
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), "xxxxx")
+	}
+	return parsed.String()
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	current   atomic.Pointer[Config]
+	loadOnce  sync.Once
+	reloadMu  sync.Mutex
+	listeners []func(*Config)
+)
+
+// reloadable are the fields Reload applies to a running server, everything
+// else is read once at startup by the components built from it.
+var reloadable = []string{"RateLimits", "OAuthProviders"}
+
+// Set installs cfg as the config of the process, commands call it right
+// after Load.
+func Set(cfg *Config) {
+	current.Store(cfg)
+}
+
+// Current returns the config installed by Set. Code that is not handed
+// the config yet falls back to loading it from CONFIG_FILE and the
+// environment once, an invalid config stops the process there.
+func Current() *Config {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	loadOnce.Do(func() {
+		cfg, err := Load(os.Getenv("CONFIG_FILE"))
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		current.CompareAndSwap(nil, cfg)
+	})
+	return current.Load()
+}
+
+// OnReload registers fn to receive the config after each successful Reload.
+func OnReload(fn func(*Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Reload loads the config again and applies only the reloadable fields,
+// changes to other fields are logged and wait for a restart. An invalid
+// config is rejected and the running one kept.
+func Reload(path string) (*Config, error) {
+	loaded, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	old := Current()
+	next := *old
+	oldValue, loadedValue, nextValue := reflect.ValueOf(old).Elem(), reflect.ValueOf(loaded).Elem(), reflect.ValueOf(&next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		name := oldValue.Type().Field(i).Name
+		if reflect.DeepEqual(oldValue.Field(i).Interface(), loadedValue.Field(i).Interface()) {
+			continue
+		}
+		if !slices.Contains(reloadable, name) {
+			log.Printf("Config %s changed, restart to apply it", name)
+			continue
+		}
+		nextValue.Field(i).Set(loadedValue.Field(i))
+		log.Printf("Config %s reloaded", name)
+	}
+
+	Set(&next)
+	for _, listener := range listeners {
+		listener(&next)
+	}
+	return &next, nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Validate reports every missing or malformed setting, it runs on Load so
+// a bad deployment fails at startup instead of on the first request.
+func (cfg *Config) Validate() error {
+	v := &validator{}
+
+	v.port("PORT", cfg.Port)
+	v.port("HTTP_PORT", cfg.HTTPPort)
+	if !cfg.TLSInsecure {
+		v.check(cfg.TLSCertFile != "", "TLS_CERT_FILE is required unless TLS_INSECURE is set")
+		v.check(cfg.TLSKeyFile != "", "TLS_KEY_FILE is required unless TLS_INSECURE is set")
+		v.oneOf("TLS_MIN_VERSION", cfg.TLSMinVersion, "1.2", "1.3")
+		v.cipherSuites("TLS_CIPHER_SUITES", cfg.TLSCipherSuites)
+		v.oneOf("TLS_CLIENT_AUTH", cfg.TLSClientAuth, "none", "optional", "require")
+		v.check(cfg.TLSClientAuth == "none" || cfg.TLSClientCAFile != "", "TLS_CLIENT_CA_FILE is required with TLS_CLIENT_AUTH=%s", cfg.TLSClientAuth)
+		v.positive("TLS_RELOAD_INTERVAL", cfg.TLSReloadInterval)
+	}
+	v.positive("SHUTDOWN_TIMEOUT", cfg.ShutdownTimeout)
+	v.check(cfg.ShutdownReadinessDelay >= 0 && cfg.ShutdownReadinessDelay < cfg.ShutdownTimeout, "SHUTDOWN_READINESS_DELAY must be between 0 and SHUTDOWN_TIMEOUT")
+	v.positive("HEALTH_CHECK_INTERVAL", cfg.HealthCheckInterval)
+	v.positive("HEALTH_CHECK_TIMEOUT", cfg.HealthCheckTimeout)
+	v.database(cfg)
+	v.check(cfg.BcryptSalt >= bcrypt.MinCost && cfg.BcryptSalt <= bcrypt.MaxCost,
+		"SALT_KEY must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+
+	// AES_SECRET_KEY seals TOTP secrets and signs unsubscribe links, not only JWE
+	v.check(slices.Contains([]int{16, 24, 32}, len(cfg.AESSecretKey)), "AES_SECRET_KEY must be 16, 24 or 32 bytes, got %d", len(cfg.AESSecretKey))
+	v.check(cfg.JWTIssuer != "", "JWT_ISSUER is required")
+	v.oneOf("JWT_SIGNING_ALGORITHM", cfg.JWTSigningAlgorithm, "EdDSA", "RS256")
+	v.positive("JWT_ACCESS_TOKEN_TTL", cfg.JWTAccessTokenTTL)
+	// servers switch to a rotated key within 30s, tokens they signed with
+	// the old key until then must stay verifiable until they expire
+	v.check(cfg.JWTKeyOverlap >= cfg.JWTAccessTokenTTL+time.Minute, "JWT_KEY_OVERLAP must be at least JWT_ACCESS_TOKEN_TTL plus 1m")
+	v.positive("MFA_CHALLENGE_TTL", cfg.MFAChallengeTTL)
+	v.check(cfg.AdminBootstrapToken == "" || len(cfg.AdminBootstrapToken) >= 16, "ADMIN_BOOTSTRAP_TOKEN must be at least 16 characters")
+	v.positive("ADMIN_INVITATION_TTL", cfg.AdminInvitationTTL)
+	v.check(cfg.AdminInvitationTTL <= cfg.AdminInvitationMaxTTL, "ADMIN_INVITATION_TTL must not exceed ADMIN_INVITATION_MAX_TTL")
+
+	v.url("IMAGE_API_GENERATION_URL", cfg.IMAGE_API_GENERATION_URL)
+	v.check(cfg.ImageGenerationWorkers >= 1, "IMAGE_GENERATION_WORKERS must be at least 1")
+	v.check(cfg.ImageGenerationQuota >= 0, "IMAGE_GENERATION_QUOTA must not be negative")
+	v.positive("IMAGE_GENERATION_QUOTA_PERIOD", cfg.ImageGenerationQuotaPeriod)
+	v.check(cfg.ImageGenerationQuotaWarning >= 0 && cfg.ImageGenerationQuotaWarning <= 100, "IMAGE_GENERATION_QUOTA_WARNING_PERCENT must be between 0 and 100")
+	v.check(cfg.ImageStorageDir != "", "IMAGE_STORAGE_DIR is required")
+	v.check(cfg.ImageAPIBreakerThreshold >= 1, "IMAGE_API_BREAKER_THRESHOLD must be at least 1")
+	v.positive("IMAGE_API_BREAKER_COOLDOWN", cfg.ImageAPIBreakerCooldown)
+	v.positive("IMAGE_API_TIMEOUT", cfg.ImageAPITimeout)
+	v.positive("IMAGE_DOWNLOAD_TIMEOUT", cfg.ImageDownloadTimeout)
+	v.check(cfg.OrganizationMaxPerUser >= 0, "ORGANIZATION_MAX_PER_USER must not be negative")
+	v.check(cfg.OrganizationGenerationQuota >= 0, "ORGANIZATION_GENERATION_QUOTA must not be negative")
+	v.positive("ORGANIZATION_INVITATION_TTL", cfg.OrganizationInvitationTTL)
+
+	v.oneOf("MAIL_DRIVER", cfg.MailDriver, "smtp", "file", "log")
+	if cfg.MailDriver == "smtp" {
+		v.check(cfg.SMTPHost != "", "SMTP_HOST is required with MAIL_DRIVER=smtp")
+		v.check(cfg.SMTPPort > 0 && cfg.SMTPPort <= 65535, "SMTP_PORT must be between 1 and 65535")
+		v.oneOf("SMTP_TLS", cfg.SMTPTLS, "starttls", "tls", "none")
+		_, err := mail.ParseAddress(cfg.MailFrom)
+		v.check(err == nil, "MAIL_FROM must be an email address, got %q", cfg.MailFrom)
+	}
+	v.positive("MAIL_OUTBOX_POLL_INTERVAL", cfg.MailOutboxPollInterval)
+	v.check(cfg.MailOutboxBatchSize >= 1, "MAIL_OUTBOX_BATCH_SIZE must be at least 1")
+	v.check(cfg.MailOutboxMaxAttempts >= 1, "MAIL_OUTBOX_MAX_ATTEMPTS must be at least 1")
+
+	v.url("EMAIL_VERIFICATION_LINK", cfg.EmailVerificationLink)
+	v.url("EMAIL_FORGET_PASSWORD_FRONTEND_LINK", cfg.EmailForgetPasswordFrontendLink)
+	v.url("EMAIL_UNLOCK_ACCOUNT_LINK", cfg.EmailUnlockAccountLink)
+	v.url("NOTIFICATION_UNSUBSCRIBE_LINK", cfg.NotificationUnsubscribeLink)
+	v.url("ORGANIZATION_INVITATION_LINK", cfg.OrganizationInvitationLink)
+	v.url("ADMIN_INVITATION_LINK", cfg.AdminInvitationLink)
+	v.positive("VERIFICATION_TOKEN_TTL", cfg.VerificationTokenTTL)
+	v.positive("RESET_PASSWORD_TOKEN_TTL", cfg.ResetPasswordTokenTTL)
+	v.positive("UNLOCK_ACCOUNT_TOKEN_TTL", cfg.UnlockAccountTokenTTL)
+
+	v.positive("WEBHOOK_TIMEOUT", cfg.WebhookTimeout)
+	v.positive("WEBHOOK_POLL_INTERVAL", cfg.WebhookPollInterval)
+	v.check(cfg.WebhookBatchSize >= 1, "WEBHOOK_BATCH_SIZE must be at least 1")
+	v.check(cfg.WebhookMaxAttempts >= 1, "WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	v.positive("API_KEY_LAST_USED_INTERVAL", cfg.APIKeyLastUsedInterval)
+
+	v.check(cfg.PasswordMinLength >= 1, "PASSWORD_MIN_LENGTH must be at least 1")
+	v.check(cfg.PasswordMinLength <= cfg.PasswordMaxLength, "PASSWORD_MIN_LENGTH must not exceed PASSWORD_MAX_LENGTH")
+	v.oneOf("RATE_LIMIT_STORE", cfg.RateLimitStore, "memory", "postgres")
+	v.check(cfg.LoginLockoutThreshold >= 1, "LOGIN_LOCKOUT_THRESHOLD must be at least 1")
+	v.positive("LOGIN_LOCKOUT_BASE", cfg.LoginLockoutBase)
+	v.check(cfg.LoginLockoutBase <= cfg.LoginLockoutMax, "LOGIN_LOCKOUT_BASE must not exceed LOGIN_LOCKOUT_MAX")
+
+	v.positive("OAUTH_STATE_TTL", cfg.OAuthStateTTL)
+	for _, provider := range cfg.OAuthProviders {
+		name := "oauth provider " + provider.Name
+		v.oneOf(name+" type", provider.Type, "oidc", "github")
+		v.check(provider.ClientID != "", "%s needs a client id", name)
+		v.check(provider.RedirectURL != "", "%s needs a redirect url", name)
+		v.url(name+" redirect url", provider.RedirectURL)
+		v.check(provider.Issuer != "", "%s needs an issuer", name)
+		v.url(name+" issuer", provider.Issuer)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+// database checks the settings LoadDatabase reads.
+func (v *validator) database(cfg *Config) {
+	v.check(cfg.DatabaseURL != "", "DATABASE_URL is required")
+	// keyword/value DSNs (host=... user=...) are Postgres
+	if scheme, _, ok := strings.Cut(cfg.DatabaseURL, ":"); ok && !strings.ContainsAny(scheme, "= ") {
+		v.oneOf("DATABASE_URL scheme", strings.ToLower(scheme), "postgres", "postgresql", "sqlite")
+	}
+}
+
+func (v *validator) oneOf(key string, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), "%s must be one of %v, got %q", key, allowed, value)
+}
+
+func (v *validator) positive(key string, value time.Duration) {
+	v.check(value > 0, "%s must be positive", key)
+}
+
+func (v *validator) port(key string, value string) {
+	port, err := strconv.Atoi(value)
+	v.check(err == nil && port > 0 && port <= 65535, "%s must be a port number, got %q", key, value)
+}
+
+// cipherSuites accepts the names of the secure suites crypto/tls supports.
+func (v *validator) cipherSuites(key string, names []string) {
+	for _, name := range names {
+		v.check(slices.ContainsFunc(tls.CipherSuites(), func(suite *tls.CipherSuite) bool { return suite.Name == name }),
+			"%s has an unknown or insecure cipher suite %q", key, name)
+	}
+}
+
+// url accepts an empty value, required links are checked separately.
+func (v *validator) url(key string, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	v.check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "",
+		"%s must be an absolute http(s) URL, got %q", key, value)
+}
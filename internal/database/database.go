@@ -1,71 +1,41 @@
 package database
 
 import (
-	"database/sql"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/oriastanjung/stellar/internal/config"
 
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var (
-	DB    *gorm.DB
-	sqlDB *sql.DB
-)
-
-func InitDB() {
-	cfg := config.LoadEnv()
-	database_url := cfg.DatabaseURL
-	var err error
+func InitDB(cfg *config.Config) *gorm.DB {
+	db := ConnectDB(cfg)
 
-	DB, err = gorm.Open(postgres.Open(database_url), &gorm.Config{})
-
-	if err != nil {
-		log.Fatal("Failed to connect to DB : ", err)
-		panic(err)
-	}
-	sqlDB, err = DB.DB()
-	if err != nil {
-		log.Fatal("Failed to obtain db instance: ", err)
-		panic(err)
+	// migration, bisa dimatikan supaya dijalankan lewat `stellar migrate up`
+	if cfg.DatabaseAutoMigrate {
+		MigrateDB(db)
 	}
+	return db
+}
 
-	err = sqlDB.Ping()
+// ConnectDB opens DB without migrating it, used by the migrate command.
+// The driver follows the scheme of DATABASE_URL, see Dialector.
+func ConnectDB(cfg *config.Config) *gorm.DB {
+	db, err := Open(cfg.DatabaseURL)
 	if err != nil {
-		log.Fatal("Failed to ping DB: ", err)
-		panic(err)
+		log.Fatal("Failed to connect to DB : ", err)
 	}
-
-	// migration
-	// Auto migrate all models
-	MigrateDB(DB) // Call MigrateDB function to auto migrate models
-
+	return db
 }
 
 // CloseDatabase closes the database connection
-func CloseDatabase() error {
-	if sqlDB != nil {
-		return sqlDB.Close()
+func CloseDatabase(db *gorm.DB) error {
+	if db == nil {
+		return nil
 	}
-	return nil
-}
-
-func GracefulShutdown() {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-c
-		log.Println("Shutting down gracefully...")
-		if err := CloseDatabase(); err != nil {
-			log.Fatal("Failed to close database: ", err)
-		}
-		log.Println("Database connection closed")
-		os.Exit(0)
-	}()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
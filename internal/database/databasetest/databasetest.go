@@ -0,0 +1,88 @@
+// Package databasetest opens migrated databases for the repository tests.
+// Every test gets an in-memory SQLite database, and a Postgres one too when
+// DATABASE_URL points at Postgres. Postgres tests run in a schema of their
+// own that is dropped afterwards, so the data already in the database is
+// never touched.
+package databasetest
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oriastanjung/stellar/internal/database"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+// Backend is one migrated database.
+type Backend struct {
+	Name string
+	DB   *gorm.DB
+}
+
+// Backends returns SQLite and, when DATABASE_URL is a postgres:// URL,
+// Postgres, both with every migration applied.
+func Backends(t *testing.T) []Backend {
+	t.Helper()
+	backends := []Backend{{Name: "sqlite", DB: open(t, "sqlite::memory:")}}
+	if dsn := os.Getenv("DATABASE_URL"); strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		backends = append(backends, Backend{Name: "postgres", DB: openPostgres(t, dsn)})
+	}
+	return backends
+}
+
+// Run runs fn as a subtest against every backend.
+func Run(t *testing.T, fn func(t *testing.T, db *gorm.DB)) {
+	t.Helper()
+	for _, backend := range Backends(t) {
+		t.Run(backend.Name, func(t *testing.T) {
+			fn(t, backend.DB)
+		})
+	}
+}
+
+func open(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { database.CloseDatabase(db) })
+	migrator, err := database.NewDBMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db
+}
+
+// openPostgres migrates a new schema and connects with it as search_path.
+func openPostgres(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	admin, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	schema := "stellar_test_" + strings.ToLower(ksuid.New().String())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		database.CloseDatabase(admin)
+	})
+
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parse DATABASE_URL: %v", err)
+	}
+	query := parsed.Query()
+	query.Set("search_path", schema)
+	parsed.RawQuery = query.Encode()
+	return open(t, parsed.String())
+}
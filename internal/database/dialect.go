@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// sqlitePragmas are applied to every SQLite connection, foreign keys are
+// off by default and a busy connection would otherwise fail right away.
+var sqlitePragmas = []string{"foreign_keys(1)", "busy_timeout(5000)"}
+
+// Dialector picks the driver from the scheme of dsn: postgres:// or
+// postgresql:// for Postgres, sqlite:<path> or sqlite::memory: for SQLite.
+// A Postgres keyword/value DSN (host=... user=...) has no scheme.
+func Dialector(dsn string) (gorm.Dialector, error) {
+	scheme, rest, ok := strings.Cut(dsn, ":")
+	if !ok || strings.ContainsAny(scheme, "= ") {
+		return postgres.Open(dsn), nil
+	}
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		return sqlite.Open(sqliteDSN(rest)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database %q, expected postgres:// or sqlite:", scheme)
+	}
+}
+
+// sqliteDSN turns the part after sqlite: into a file name for the driver,
+// sqlite://stellar.db and sqlite:stellar.db both open ./stellar.db.
+func sqliteDSN(rest string) string {
+	path, query, _ := strings.Cut(strings.TrimPrefix(rest, "//"), "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		values = url.Values{}
+	}
+	if !values.Has("_pragma") {
+		values["_pragma"] = sqlitePragmas
+	}
+	return path + "?" + values.Encode()
+}
+
+// Open connects to dsn and checks the connection. SQLite allows a single
+// writer, its pool is limited to one connection so transactions queue up
+// instead of failing with SQLITE_BUSY, which also keeps a :memory:
+// database alive for the lifetime of the pool.
+func Open(dsn string) (*gorm.DB, error) {
+	dialector, err := Dialector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting database handle: %w", err)
+	}
+	if db.Dialector.Name() == "sqlite" {
+		sqlDB.SetMaxOpenConns(1)
+	}
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("error pinging database: %w", err)
+	}
+	return db, nil
+}
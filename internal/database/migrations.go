@@ -1,22 +1,39 @@
 package database
 
 import (
+	"context"
 	"log"
 
-	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/database/migrations"
 
 	"gorm.io/gorm"
 )
 
-// MigrateDB migrates all database tables based on the models
+// MigrateDB applies the pending SQL migrations in internal/database/migrations
 func MigrateDB(db *gorm.DB) {
-	err := db.AutoMigrate(
-		&entities.User{}, // tambahkan semua model di sini
-	)
+	migrator, err := NewDBMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
 
+	applied, err := migrator.Up(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
-	} else {
-		log.Println("Database migrated successfully")
 	}
+	log.Printf("Database migrated successfully, %d migration(s) applied", len(applied))
+}
+
+// NewDBMigrator returns a Migrator for the embedded migrations of the
+// database behind db.
+func NewDBMigrator(db *gorm.DB) (*Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	dialect := db.Dialector.Name()
+	source, err := migrations.FS(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(sqlDB, dialect, source)
 }
@@ -0,0 +1,28 @@
+// Package migrations embeds the versioned SQL files applied by
+// database.Migrator, one folder per backend. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql, create them with
+// `stellar migrate new <name>` which adds the version to every folder.
+// A migration must not be edited once it has been applied anywhere, add a
+// new one instead.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dirs are the folders of the backends, named after the gorm dialector.
+var Dirs = []string{"postgres", "sqlite"}
+
+// FS returns the migrations of dialect, postgres or sqlite.
+func FS(dialect string) (fs.FS, error) {
+	if !slices.Contains(Dirs, dialect) {
+		return nil, fmt.Errorf("no migrations for database %s", dialect)
+	}
+	return fs.Sub(files, dialect)
+}
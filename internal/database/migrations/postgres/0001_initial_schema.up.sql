@@ -0,0 +1,242 @@
+-- Baseline of the schema previously created by gorm AutoMigrate. Every
+-- statement is IF NOT EXISTS so it also runs on a database created by
+-- AutoMigrate, where it creates the missing tables but leaves the existing
+-- users table as it is. 0004 adds the users columns that table lacks.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bytea NOT NULL,
+    username text NOT NULL,
+    email text NOT NULL,
+    password text NOT NULL,
+    no_password boolean DEFAULT false,
+    role text NOT NULL,
+    profile_picture text DEFAULT '',
+    profile_picture_url text DEFAULT '',
+    is_verified boolean DEFAULT false,
+    subscription_status boolean DEFAULT false,
+    subscription_token text DEFAULT '',
+    totp_secret text DEFAULT '',
+    totp_enabled boolean DEFAULT false,
+    totp_last_used_step bigint DEFAULT 0,
+    failed_login_attempts bigint DEFAULT 0,
+    locked_until timestamptz,
+    preferred_locale varchar(10) NOT NULL DEFAULT 'en',
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT chk_users_role CHECK (role IN ('admin', 'user'))
+);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
+CREATE INDEX IF NOT EXISTS idx_users_subscription_status ON users (subscription_status);
+CREATE INDEX IF NOT EXISTS idx_users_subscription_token ON users (subscription_token);
+CREATE INDEX IF NOT EXISTS idx_users_updated_at ON users (updated_at);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users (username);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    code_hash text NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS o_auth_states (
+    state_hash text NOT NULL,
+    provider text NOT NULL,
+    code_verifier text NOT NULL,
+    nonce text NOT NULL,
+    link_user_id bytea,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (state_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_o_auth_states_expires_at ON o_auth_states (expires_at);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    provider text NOT NULL,
+    subject text NOT NULL,
+    email text DEFAULT '',
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities (provider, subject);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key text NOT NULL,
+    tokens decimal NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (key)
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    purpose text NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_user_tokens_purpose CHECK (purpose IN ('verification', 'reset_password', 'unlock_account'))
+);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_created_at ON user_tokens (created_at);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_expires_at ON user_tokens (expires_at);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_purpose ON user_tokens (purpose);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_tokens_token_hash ON user_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS outbox_emails (
+    id bytea NOT NULL,
+    "to" text NOT NULL,
+    subject text NOT NULL,
+    html_body text NOT NULL,
+    text_body text NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts bigint DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_error text DEFAULT '',
+    sent_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_outbox_emails_status CHECK (status IN ('pending', 'sent', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_emails_created_at ON outbox_emails (created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_emails_next_attempt_at ON outbox_emails (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_emails_status ON outbox_emails (status);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id bytea NOT NULL,
+    event text NOT NULL,
+    email_enabled boolean NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, event)
+);
+
+CREATE TABLE IF NOT EXISTS user_devices (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    fingerprint text NOT NULL,
+    user_agent text DEFAULT '',
+    ip_address text DEFAULT '',
+    last_seen_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_devices_user_fingerprint ON user_devices (user_id, fingerprint);
+
+CREATE TABLE IF NOT EXISTS image_jobs (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    organization_id bytea,
+    status text NOT NULL DEFAULT 'pending',
+    async boolean DEFAULT false,
+    prompt text NOT NULL,
+    image_url text DEFAULT '',
+    filename text DEFAULT '',
+    error text DEFAULT '',
+    finished_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_image_jobs_status CHECK (status IN ('pending', 'running', 'succeeded', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS idx_image_jobs_created_at ON image_jobs (created_at);
+CREATE INDEX IF NOT EXISTS idx_image_jobs_organization_id ON image_jobs (organization_id);
+CREATE INDEX IF NOT EXISTS idx_image_jobs_status ON image_jobs (status);
+CREATE INDEX IF NOT EXISTS idx_image_jobs_user_id ON image_jobs (user_id);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bytea NOT NULL,
+    webhook_id bytea NOT NULL,
+    event_id bytea NOT NULL,
+    event text NOT NULL,
+    payload text NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts bigint DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    response_status bigint DEFAULT 0,
+    last_error text DEFAULT '',
+    delivered_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created_at ON webhook_deliveries (created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    name text NOT NULL,
+    prefix text NOT NULL,
+    key_hash text NOT NULL,
+    scopes text NOT NULL,
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    last_used_ip text DEFAULT '',
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id bytea NOT NULL,
+    name text NOT NULL,
+    generation_quota bigint NOT NULL DEFAULT 0,
+    created_by bytea NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    organization_id bytea NOT NULL,
+    user_id bytea NOT NULL,
+    role text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT chk_memberships_role CHECK (role IN ('owner', 'admin', 'member'))
+);
+CREATE INDEX IF NOT EXISTS idx_memberships_user_id ON memberships (user_id);
+
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id bytea NOT NULL,
+    organization_id bytea NOT NULL,
+    email text NOT NULL,
+    role text NOT NULL,
+    token_hash text NOT NULL,
+    invited_by bytea NOT NULL,
+    expires_at timestamptz NOT NULL,
+    accepted_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_organization_invitations_role CHECK (role IN ('owner', 'admin', 'member'))
+);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_email ON organization_invitations (email);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_organization_id ON organization_invitations (organization_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_invitations_token_hash ON organization_invitations (token_hash);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS admin_invitations (
+    id bytea NOT NULL,
+    email text NOT NULL,
+    token_hash text NOT NULL,
+    invited_by bytea NOT NULL,
+    expires_at timestamptz NOT NULL,
+    accepted_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_invitations_email ON admin_invitations (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_invitations_token_hash ON admin_invitations (token_hash);
@@ -0,0 +1,3 @@
+-- the columns are part of 0001 on databases it created, reverting this
+-- version keeps them.
+SELECT 1;
@@ -0,0 +1,10 @@
+-- users tables created by AutoMigrate before the migrator existed only have
+-- the columns of the first release, 0001 skipped them because the table
+-- already existed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS no_password boolean DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret text DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled boolean DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_used_step bigint DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts bigint DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_locale varchar(10) NOT NULL DEFAULT 'en';
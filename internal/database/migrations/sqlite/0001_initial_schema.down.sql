@@ -0,0 +1,17 @@
+-- Drops every table, all data is lost.
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS image_jobs;
+DROP TABLE IF EXISTS user_devices;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS outbox_emails;
+DROP TABLE IF EXISTS user_tokens;
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS o_auth_states;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS admin_invitations (
+    id text NOT NULL,
+    email text NOT NULL,
+    token_hash text NOT NULL,
+    invited_by text NOT NULL,
+    expires_at datetime NOT NULL,
+    accepted_at datetime,
+    created_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_invitations_email ON admin_invitations (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_invitations_token_hash ON admin_invitations (token_hash);
@@ -0,0 +1,3 @@
+-- SQLite databases were always created by 0001, their users table already
+-- has every column.
+SELECT 1;
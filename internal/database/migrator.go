@@ -0,0 +1,326 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/database/migrations"
+)
+
+// migrationLockID is the key of the advisory lock held while migrating, so
+// replicas starting at the same time apply each migration once.
+const migrationLockID = 7_270_143_001
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 of Up
+}
+
+// MigrationStatus is a known migration with when it was applied, AppliedAt
+// is nil while it is pending.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies the versioned SQL migrations and records them in the
+// schema_migrations table with the checksum of each up file.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// NewMigrator reads the migrations from source, every version needs both
+// an up and a down file. dialect is the gorm dialector name, postgres or
+// sqlite.
+func NewMigrator(db *sql.DB, dialect string, source fs.FS) (*Migrator, error) {
+	files, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range files {
+		match := migrationFileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		body, err := fs.ReadFile(source, file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", file.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(body)
+			sum := sha256.Sum256(body)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in version order and returns them.
+// It refuses to run when an applied migration was edited afterwards.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if checksum, ok := done[migration.Version]; ok {
+				if checksum != migration.Checksum {
+					return fmt.Errorf("migration %d_%s was changed after it was applied", migration.Version, migration.Name)
+				}
+				continue
+			}
+			err := m.run(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+				migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the newest steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			err := m.run(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("error reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			log.Printf("Reverted migration %d_%s", migration.Version, migration.Name)
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration, applied versions missing from the
+// binary are returned with an empty Name.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	appliedAt := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+			delete(appliedAt, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for version, at := range appliedAt {
+		at := at
+		statuses = append(statuses, MigrationStatus{Migration: Migration{Version: version}, AppliedAt: &at})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// locked runs fn on a single connection holding the migration advisory
+// lock, concurrent callers wait for it. SQLite has no advisory locks, its
+// pool has a single connection so fn is already alone.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect == "sqlite" {
+		if err := m.ensureTable(ctx, conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer func() {
+		// context bisa sudah dibatalkan, lepaskan lock tetap dijalankan
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			log.Printf("Error releasing migration lock: %v", err)
+		}
+	}()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, db execer) error {
+	appliedAt := "timestamptz NOT NULL DEFAULT now()"
+	if m.dialect == "sqlite" {
+		appliedAt = "datetime NOT NULL DEFAULT CURRENT_TIMESTAMP"
+	}
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at `+appliedAt+`
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied returns the checksum of every applied version.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]string, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	done := map[int64]string{}
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		done[version] = checksum
+	}
+	return done, rows.Err()
+}
+
+// run executes the migration script and the bookkeeping statement in one
+// transaction, a failing migration leaves nothing behind.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// NewMigrationFiles creates empty up and down files for the version after
+// the newest one in every backend folder of dir and returns their paths,
+// the SQL has to be written for each backend.
+func NewMigrationFiles(dir string, name string) ([]string, error) {
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("migration name must be lower case letters, digits and underscores")
+	}
+	var latest int64
+	for _, dialect := range migrations.Dirs {
+		files, err := os.ReadDir(filepath.Join(dir, dialect))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migrations: %w", err)
+		}
+		for _, file := range files {
+			if match := migrationFileName.FindStringSubmatch(file.Name()); match != nil {
+				version, _ := strconv.ParseInt(match[1], 10, 64)
+				latest = max(latest, version)
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", latest+1, name)
+	var paths []string
+	for _, dialect := range migrations.Dirs {
+		up := filepath.Join(dir, dialect, base+".up.sql")
+		down := filepath.Join(dir, dialect, base+".down.sql")
+		if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0644); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(down, []byte("-- revert "+name+"\n"), 0644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, up, down)
+	}
+	return paths, nil
+}
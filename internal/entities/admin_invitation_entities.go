@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// AdminInvitation lets an existing admin invite another one, the invitee
+// redeems the emailed token through SignUpAdmin with the invited email.
+// Only the hash of the token is stored.
+type AdminInvitation struct {
+	ID         ksuid.KSUID `gorm:"primary_key;not null"`
+	Email      string      `gorm:"not null;index"`
+	TokenHash  string      `gorm:"not null;uniqueIndex"`
+	InvitedBy  ksuid.KSUID `gorm:"not null"`
+	ExpiresAt  time.Time   `gorm:"not null"`
+	AcceptedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type APIKeyScope string
+
+const (
+	// APIKeyScopeImageGenerate allows GenerateImage, GenerateImageAsync and
+	// DownloadAndSaveImage
+	APIKeyScopeImageGenerate APIKeyScope = "image:generate"
+	// APIKeyScopeImageRead allows GetImageJob
+	APIKeyScopeImageRead APIKeyScope = "image:read"
+)
+
+// APIKeyScopes lists every scope a key can be given.
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeImageGenerate,
+	APIKeyScopeImageRead,
+}
+
+// Valid reports whether s is one of APIKeyScopes.
+func (s APIKeyScope) Valid() bool {
+	for _, scope := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a personal key used by scripts instead of logging in. The key
+// is stl_<prefix>_<secret>, the prefix finds the row and only the SHA-256
+// of the whole key is stored.
+type APIKey struct {
+	ID         ksuid.KSUID   `gorm:"primary_key;not null"`
+	UserID     ksuid.KSUID   `gorm:"not null;index"`
+	Name       string        `gorm:"not null"`
+	Prefix     string        `gorm:"not null;uniqueIndex"`
+	KeyHash    string        `gorm:"not null"`
+	Scopes     []APIKeyScope `gorm:"serializer:json;type:text;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string    `gorm:"default:''"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// HasScope reports whether the key was given scope.
+func (key *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range key.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the key can no longer be used at now.
+func (key *APIKey) Expired(now time.Time) bool {
+	return key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)
+}
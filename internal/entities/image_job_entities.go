@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type ImageJobStatus string
+
+const (
+	ImageJobPending   ImageJobStatus = "pending"
+	ImageJobRunning   ImageJobStatus = "running"
+	ImageJobSucceeded ImageJobStatus = "succeeded"
+	ImageJobFailed    ImageJobStatus = "failed"
+)
+
+// ImageJob records every generation, failed jobs do not count towards the
+// generation quota. Jobs with an OrganizationID belong to the library of
+// the organization and count towards its pooled quota instead.
+type ImageJob struct {
+	ID             ksuid.KSUID    `gorm:"primary_key;not null"`
+	UserID         ksuid.KSUID    `gorm:"not null;index"`
+	OrganizationID *ksuid.KSUID   `gorm:"index"`
+	Status         ImageJobStatus `gorm:"type:text;not null;default:'pending';index;check:status IN ('pending', 'running', 'succeeded', 'failed')"`
+	Async          bool           `gorm:"default:false"`
+	Prompt         string         `gorm:"type:text;not null"`
+	ImageURL       string         `gorm:"type:text;default:''"`
+	Filename       string         `gorm:"default:''"`
+	Error          string         `gorm:"type:text;default:''"`
+	FinishedAt     *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime;index"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+// ImagePrompt is the structured description an image is generated from,
+// shared by the ImageService and `stellar generate`.
+type ImagePrompt struct {
+	CoreSubject            string
+	KeyDescriptors         string
+	Environment            string
+	Style                  string
+	MoodTone               string
+	Composition            string
+	AdditionalInstructions string
+}
+
+// String builds the prompt sent to the generation API.
+func (p ImagePrompt) String() string {
+	return fmt.Sprintf(`Core Subject: %s
+			Key Descriptors: %s
+			Environment: %s
+			Style: %s
+			Mood/Tone: %s
+			Composition: %s
+			Additional Instructions: %s`,
+		p.CoreSubject,
+		p.KeyDescriptors,
+		p.Environment,
+		p.Style,
+		p.MoodTone,
+		p.Composition,
+		p.AdditionalInstructions,
+	)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// RecoveryCode is a single-use 2FA fallback code, only its SHA-256 hash is stored.
+type RecoveryCode struct {
+	ID        ksuid.KSUID `gorm:"primary_key;not null"`
+	UserID    ksuid.KSUID `gorm:"not null;index"`
+	CodeHash  string      `gorm:"not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type NotificationEvent string
+
+const (
+	NotificationGenerationFinished   NotificationEvent = "generation_finished"
+	NotificationGenerationFailed     NotificationEvent = "generation_failed"
+	NotificationPasswordChanged      NotificationEvent = "password_changed"
+	NotificationNewLogin             NotificationEvent = "new_login"
+	NotificationQuotaNearlyExhausted NotificationEvent = "quota_nearly_exhausted"
+	NotificationSubscriptionChanged  NotificationEvent = "subscription_changed"
+)
+
+// NotificationEvents lists every event a user can receive emails for.
+var NotificationEvents = []NotificationEvent{
+	NotificationGenerationFinished,
+	NotificationGenerationFailed,
+	NotificationPasswordChanged,
+	NotificationNewLogin,
+	NotificationQuotaNearlyExhausted,
+	NotificationSubscriptionChanged,
+}
+
+// NotificationPreference overrides the default of one event, events
+// without a row are emailed.
+type NotificationPreference struct {
+	UserID       ksuid.KSUID       `gorm:"primaryKey;not null"`
+	Event        NotificationEvent `gorm:"primaryKey;type:text;not null"`
+	EmailEnabled bool              `gorm:"not null"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime"`
+}
+
+// Valid reports whether e is one of NotificationEvents.
+func (e NotificationEvent) Valid() bool {
+	for _, event := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// OAuthState is the server side half of an OAuth authorization request,
+// looked up by the hash of the state parameter and consumed on callback.
+type OAuthState struct {
+	StateHash    string      `gorm:"primary_key;not null"`
+	Provider     string      `gorm:"not null"`
+	CodeVerifier string      `gorm:"not null"`
+	Nonce        string      `gorm:"not null"`
+	LinkUserID   ksuid.KSUID // set when the flow links an identity to a logged in user
+	ExpiresAt    time.Time   `gorm:"not null;index"`
+	CreatedAt    time.Time   `gorm:"autoCreateTime"`
+}
+
+// UserIdentity links a social login (provider, subject) to a user.
+type UserIdentity struct {
+	ID        ksuid.KSUID `gorm:"primary_key;not null"`
+	UserID    ksuid.KSUID `gorm:"not null;index"`
+	Provider  string      `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string      `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string      `gorm:"default:''"`
+	CreatedAt time.Time   `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type OrganizationRole string
+
+const (
+	OrganizationOwner  OrganizationRole = "owner"
+	OrganizationAdmin  OrganizationRole = "admin"
+	OrganizationMember OrganizationRole = "member"
+)
+
+// rank orders the roles, a member can only manage members ranked below
+// them, owners can manage everyone.
+func (r OrganizationRole) rank() int {
+	switch r {
+	case OrganizationOwner:
+		return 3
+	case OrganizationAdmin:
+		return 2
+	case OrganizationMember:
+		return 1
+	}
+	return 0
+}
+
+// Valid reports whether r is one of the organization roles.
+func (r OrganizationRole) Valid() bool {
+	return r.rank() > 0
+}
+
+// AtLeast reports whether r has the permissions of role.
+func (r OrganizationRole) AtLeast(role OrganizationRole) bool {
+	return r.rank() >= role.rank()
+}
+
+// CanManage reports whether r may change or remove a member with role
+// target, or grant target to someone.
+func (r OrganizationRole) CanManage(target OrganizationRole) bool {
+	return r == OrganizationOwner || (r.AtLeast(OrganizationAdmin) && r.rank() > target.rank())
+}
+
+// Organization shares an image library and a pooled generation quota
+// between its members. Requests act on an organization when they carry
+// its ID in the x-org-id metadata.
+type Organization struct {
+	ID              ksuid.KSUID `gorm:"primary_key;not null"`
+	Name            string      `gorm:"not null"`
+	GenerationQuota int         `gorm:"not null;default:0"` // 0 = tanpa batas
+	CreatedBy       ksuid.KSUID `gorm:"not null"`
+	CreatedAt       time.Time   `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time   `gorm:"autoUpdateTime"`
+}
+
+type Membership struct {
+	OrganizationID ksuid.KSUID      `gorm:"primaryKey;not null"`
+	UserID         ksuid.KSUID      `gorm:"primaryKey;not null;index"`
+	Role           OrganizationRole `gorm:"type:text;not null;check:role IN ('owner', 'admin', 'member')"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime"`
+}
+
+// OrganizationInvitation is emailed to someone who may not have an account
+// yet, it is accepted by the user with the invited email. Only the hash of
+// the token is stored.
+type OrganizationInvitation struct {
+	ID             ksuid.KSUID      `gorm:"primary_key;not null"`
+	OrganizationID ksuid.KSUID      `gorm:"not null;index"`
+	Email          string           `gorm:"not null;index"`
+	Role           OrganizationRole `gorm:"type:text;not null;check:role IN ('owner', 'admin', 'member')"`
+	TokenHash      string           `gorm:"not null;uniqueIndex"`
+	InvitedBy      ksuid.KSUID      `gorm:"not null"`
+	ExpiresAt      time.Time        `gorm:"not null"`
+	AcceptedAt     *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// OutboxEmail is an email saved in the same transaction as the change that
+// triggered it, a worker sends it and retries failures with backoff.
+type OutboxEmail struct {
+	ID            ksuid.KSUID  `gorm:"primary_key;not null"`
+	To            string       `gorm:"not null"`
+	Subject       string       `gorm:"not null"`
+	HTMLBody      string       `gorm:"type:text;not null"`
+	TextBody      string       `gorm:"type:text;not null"`
+	Status        OutboxStatus `gorm:"type:text;not null;default:'pending';index;check:status IN ('pending', 'sent', 'failed')"`
+	Attempts      int          `gorm:"default:0"`
+	NextAttemptAt time.Time    `gorm:"not null;index"`
+	LastError     string       `gorm:"type:text;default:''"`
+	SentAt        *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+// RateLimitBucket is a token bucket shared between instances.
+type RateLimitBucket struct {
+	Key       string    `gorm:"primary_key;not null"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index"`
+}
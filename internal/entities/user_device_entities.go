@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// UserDevice is a client a user has logged in from, identified by the hash
+// of its user agent, a login from an unknown one is notified by email.
+type UserDevice struct {
+	ID          ksuid.KSUID `gorm:"primary_key;not null"`
+	UserID      ksuid.KSUID `gorm:"not null;uniqueIndex:idx_user_devices_user_fingerprint"`
+	Fingerprint string      `gorm:"not null;uniqueIndex:idx_user_devices_user_fingerprint"`
+	UserAgent   string      `gorm:"type:text;default:''"`
+	IPAddress   string      `gorm:"default:''"`
+	LastSeenAt  time.Time   `gorm:"not null"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime"`
+}
@@ -37,16 +37,22 @@ type User struct {
 	Username            string      `gorm:"not null;index"`
 	Email               string      `gorm:"unique;not null;index"`
 	Password            string      `gorm:"not null"`
+	NoPassword          bool        `gorm:"default:false"`                                            // social-only account, Password is empty
 	Role                string      `gorm:"type:text;not null;index;check:role IN ('admin', 'user')"` // Use CHECK constraint
 	ProfilePicture      string      `gorm:"default:''"`
 	ProfilePictureUrl   string      `gorm:"default:''"`
 	IsVerified          bool        `gorm:"default:false"`
-	VerificationToken   string      `gorm:"default:'';index"`
-	ForgetPasswordToken string      `gorm:"default:'';index"`
 	SubscriptionStatus  bool        `gorm:"default:false;index"`
 	SubscriptionToken   string      `gorm:"default:'';index"`
-	CreatedAt           time.Time   `gorm:"autoCreateTime;index"`
-	UpdatedAt           time.Time   `gorm:"autoCreateTime;index"`
+	TOTPSecret          string      `gorm:"default:''"` // sealed with AES_SECRET_KEY
+	TOTPEnabled         bool        `gorm:"default:false"`
+	TOTPLastUsedStep    int64       `gorm:"default:0"`
+	FailedLoginAttempts int         `gorm:"default:0"`
+	LockedUntil         *time.Time
+	DisabledAt          *time.Time // set by `stellar user disable`, blocks logins and API keys
+	PreferredLocale     string     `gorm:"type:varchar(10);not null;default:'en'"` // locale of emails, see i18n
+	CreatedAt           time.Time  `gorm:"autoCreateTime;index"`
+	UpdatedAt           time.Time  `gorm:"autoCreateTime;index"`
 }
 
 func NewUser(username, email, password string, role Role) (*User, error) {
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type TokenPurpose string
+
+const (
+	TokenPurposeVerification  TokenPurpose = "verification"
+	TokenPurposeResetPassword TokenPurpose = "reset_password"
+	TokenPurposeUnlockAccount TokenPurpose = "unlock_account"
+)
+
+// UserToken is a single-use token sent by email, only its SHA-256 hash is stored.
+type UserToken struct {
+	ID        ksuid.KSUID  `gorm:"primary_key;not null"`
+	UserID    ksuid.KSUID  `gorm:"not null;index"`
+	Purpose   TokenPurpose `gorm:"type:text;not null;index;check:purpose IN ('verification', 'reset_password', 'unlock_account')"`
+	TokenHash string       `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time    `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookImageGenerated  WebhookEvent = "image.generated"
+	WebhookImageFailed     WebhookEvent = "image.failed"
+	WebhookImageDownloaded WebhookEvent = "image.downloaded"
+)
+
+// WebhookEvents lists every event an endpoint can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookImageGenerated,
+	WebhookImageFailed,
+	WebhookImageDownloaded,
+}
+
+// Valid reports whether e is one of WebhookEvents.
+func (e WebhookEvent) Valid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is an endpoint a user registered to receive events. The signing
+// secret is sealed with AES_SECRET_KEY and only shown when it is created.
+type Webhook struct {
+	ID        ksuid.KSUID    `gorm:"primary_key;not null"`
+	UserID    ksuid.KSUID    `gorm:"not null;index"`
+	URL       string         `gorm:"type:text;not null"`
+	Secret    string         `gorm:"type:text;not null"`
+	Events    []WebhookEvent `gorm:"serializer:json;type:text;not null"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+}
+
+// Subscribed reports whether the endpoint wants event.
+func (webhook *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one attempt to push an event to an endpoint, it is
+// kept after delivery as the log shown by ListWebhookDeliveries. EventID
+// stays the same when a delivery is redelivered so receivers can dedupe.
+type WebhookDelivery struct {
+	ID             ksuid.KSUID           `gorm:"primary_key;not null"`
+	WebhookID      ksuid.KSUID           `gorm:"not null;index"`
+	EventID        ksuid.KSUID           `gorm:"not null"`
+	Event          WebhookEvent          `gorm:"type:text;not null"`
+	Payload        string                `gorm:"type:text;not null"`
+	Status         WebhookDeliveryStatus `gorm:"type:text;not null;default:'pending';index;check:status IN ('pending', 'succeeded', 'failed')"`
+	Attempts       int                   `gorm:"default:0"`
+	NextAttemptAt  time.Time             `gorm:"not null;index"`
+	ResponseStatus int                   `gorm:"default:0"`
+	LastError      string                `gorm:"type:text;default:''"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,102 @@
+package apikey_server
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	services "github.com/oriastanjung/stellar/internal/services/apikey"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/apikey"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type APIKeyServer struct {
+	pb.APIKeyServiceServer
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyServer(apiKeyService services.APIKeyService) *APIKeyServer {
+	return &APIKeyServer{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (server *APIKeyServer) CreateAPIKey(ctx context.Context, input *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]entities.APIKeyScope, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		scopes = append(scopes, entities.APIKeyScope(scope))
+	}
+
+	key, rawKey, err := server.apiKeyService.CreateAPIKey(ctx, userID, input.Name, scopes, int(input.ExpiresInDays))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateAPIKeyResponse{
+		ApiKey: toAPIKey(key),
+		Key:    rawKey,
+	}, nil
+}
+
+func (server *APIKeyServer) ListAPIKeys(ctx context.Context, _ *emptypb.Empty) (*pb.ListAPIKeysResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := server.apiKeyService.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListAPIKeysResponse{}
+	for i := range keys {
+		response.ApiKeys = append(response.ApiKeys, toAPIKey(&keys[i]))
+	}
+	return response, nil
+}
+
+func (server *APIKeyServer) RevokeAPIKey(ctx context.Context, input *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrAPIKeyNotFound
+	}
+
+	if err := server.apiKeyService.RevokeAPIKey(ctx, userID, keyID); err != nil {
+		return nil, err
+	}
+	return &pb.RevokeAPIKeyResponse{
+		Message: i18n.Translate(ctx, "message.revoke_api_key"),
+	}, nil
+}
+
+func toAPIKey(key *entities.APIKey) *pb.APIKey {
+	result := &pb.APIKey{
+		Id:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		LastUsedIp: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt.Format(time.RFC3339),
+	}
+	for _, scope := range key.Scopes {
+		result.Scopes = append(result.Scopes, string(scope))
+	}
+	if key.ExpiresAt != nil {
+		result.ExpiresAt = key.ExpiresAt.Format(time.RFC3339)
+	}
+	if key.LastUsedAt != nil {
+		result.LastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package auth_server
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/auth"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (server *AuthServer) CreateAdminInvitation(ctx context.Context, input *pb.CreateAdminInvitationRequest) (*pb.AdminInvitation, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	inviterID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(input.ExpiresInHours) * time.Hour
+	invitation, err := server.authService.CreateAdminInvitation(ctx, inviterID, input.Email, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return toAdminInvitation(invitation), nil
+}
+
+func (server *AuthServer) ListAdminInvitations(ctx context.Context, _ *emptypb.Empty) (*pb.ListAdminInvitationsResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	invitations, err := server.authService.ListAdminInvitations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListAdminInvitationsResponse{}
+	for i := range invitations {
+		response.Invitations = append(response.Invitations, toAdminInvitation(&invitations[i]))
+	}
+	return response, nil
+}
+
+func (server *AuthServer) RevokeAdminInvitation(ctx context.Context, input *pb.RevokeAdminInvitationRequest) (*pb.RevokeAdminInvitationResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	invitationID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrInvitationNotFound
+	}
+
+	if err := server.authService.RevokeAdminInvitation(ctx, invitationID); err != nil {
+		return nil, err
+	}
+	return &pb.RevokeAdminInvitationResponse{
+		Message: i18n.Translate(ctx, "message.revoke_invitation"),
+	}, nil
+}
+
+func requireAdmin(ctx context.Context) error {
+	claims, err := utils.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+	if claims.Role != string(entities.AdminRole) {
+		return apperror.ErrPermissionDenied
+	}
+	return nil
+}
+
+func toAdminInvitation(invitation *entities.AdminInvitation) *pb.AdminInvitation {
+	return &pb.AdminInvitation{
+		Id:        invitation.ID.String(),
+		Email:     invitation.Email,
+		InvitedBy: invitation.InvitedBy.String(),
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
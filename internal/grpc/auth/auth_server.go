@@ -2,95 +2,94 @@ package auth_server
 
 import (
 	"context"
-	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/i18n"
 
 	"github.com/oriastanjung/stellar/internal/entities"
 	services "github.com/oriastanjung/stellar/internal/services/auth"
+	usecase "github.com/oriastanjung/stellar/internal/usecase/auth"
+	"github.com/oriastanjung/stellar/internal/utils"
 	pb "github.com/oriastanjung/stellar/proto/auth"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/segmentio/ksuid"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type AuthServer struct {
 	pb.AuthServiceRoutesServer
 	authService services.AuthService
-	salt        int
 }
 
-func NewAuthServer(authService services.AuthService, salt int) *AuthServer {
+func NewAuthServer(authService services.AuthService) *AuthServer {
 	return &AuthServer{
 		authService: authService,
-		salt:        salt,
 	}
 }
 
-func (server *AuthServer) SignUpAdmin(ctx context.Context, input *pb.SignUpRequest) (*pb.SignUpResponse, error) {
+func (server *AuthServer) SignUpAdmin(ctx context.Context, input *pb.SignUpAdminRequest) (*pb.SignUpResponse, error) {
 	newUser, err := entities.NewUser(input.Username, input.Email, input.Password, entities.AdminRole)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error on NewUser with err : %v", err))
+		return nil, apperror.Internal(err)
 	}
+	// emails are sent in the language the user signed up in
+	newUser.PreferredLocale = i18n.FromContext(ctx)
 
-	err = server.authService.RegisterAdmin(context.Background(), newUser, server.salt)
+	err = server.authService.RegisterAdmin(context.Background(), newUser, input.Token)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 
 	}
 
 	return &pb.SignUpResponse{
-		Message: "SignUpAdmin Successfully",
+		Message: i18n.Translate(ctx, "message.sign_up_admin"),
 	}, nil
 
 }
 
 func (server *AuthServer) LoginAdmin(ctx context.Context, input *pb.LoginRequest) (*pb.LoginResponse, error) {
-	token, err := server.authService.LoginAdmin(context.Background(), &entities.User{
+	result, err := server.authService.LoginAdmin(ctx, &entities.User{
 		Email:    input.Email,
 		Password: input.Password,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
-	return &pb.LoginResponse{
-		Message: "Login Admin Successfully",
-		Token:   token,
-	}, nil
+	return toLoginResponse(ctx, result, "message.login_admin"), nil
 
 }
 
 func (server *AuthServer) SignUpUser(ctx context.Context, input *pb.SignUpRequest) (*pb.SignUpResponse, error) {
 	newUser, err := entities.NewUser(input.Username, input.Email, input.Password, entities.UserRole)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error on NewUser with err : %v", err))
+		return nil, apperror.Internal(err)
 	}
+	// emails are sent in the language the user signed up in
+	newUser.PreferredLocale = i18n.FromContext(ctx)
 
-	err = server.authService.RegisterUser(context.Background(), newUser, server.salt)
+	err = server.authService.RegisterUser(context.Background(), newUser)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 
 	}
 
 	return &pb.SignUpResponse{
-		Message: "SignUpUser Successfully",
+		Message: i18n.Translate(ctx, "message.sign_up_user"),
 	}, nil
 
 }
 
 func (server *AuthServer) LoginUser(ctx context.Context, input *pb.LoginRequest) (*pb.LoginResponse, error) {
 
-	token, err := server.authService.LoginUser(context.Background(), &entities.User{
+	result, err := server.authService.LoginUser(ctx, &entities.User{
 		Email:    input.Email,
 		Password: input.Password,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
-	return &pb.LoginResponse{
-		Message: "Login User Successfully",
-		Token:   token,
-	}, nil
+	return toLoginResponse(ctx, result, "message.login_user"), nil
 
 }
 
@@ -98,11 +97,11 @@ func (server *AuthServer) VerifyUser(ctx context.Context, input *pb.VerifyUserRe
 	token := input.Token
 	err := server.authService.VerifyUser(ctx, token)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
 	return &pb.VerifyUserResponse{
-		Message: "Verify User Successfully",
+		Message: i18n.Translate(ctx, "message.verify_user"),
 	}, nil
 }
 
@@ -110,11 +109,11 @@ func (server *AuthServer) RequestForgetPassword(ctx context.Context, input *pb.R
 
 	err := server.authService.RequestForgetPassword(ctx, input.Email)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
 	return &pb.RequestForgetPasswordResponse{
-		Message: "Request Forget Password Successfully",
+		Message: i18n.Translate(ctx, "message.request_forget_password"),
 	}, nil
 }
 
@@ -122,18 +121,81 @@ func (server *AuthServer) ResetPasswordByToken(ctx context.Context, input *pb.Re
 
 	err := server.authService.ResetPasswordByToken(ctx, input.Token, input.Password)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
 	return &pb.ResetPasswordByTokenResponse{
-		Message: "Reset Password By Token Successfully",
+		Message: i18n.Translate(ctx, "message.reset_password_by_token"),
 	}, nil
 }
 
-func (server *AuthServer) LoginUserViaGoogle(context.Context, *emptypb.Empty) (*pb.LoginGoogleResponse, error) {
-	url, err := server.authService.LoginUserViaGoogle(context.Background())
+func (server *AuthServer) UnlockAccount(ctx context.Context, input *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+
+	err := server.authService.UnlockAccount(ctx, input.Token)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
+	}
+
+	return &pb.UnlockAccountResponse{
+		Message: i18n.Translate(ctx, "message.unlock_account"),
+	}, nil
+}
+
+func (server *AuthServer) ResendVerificationEmail(ctx context.Context, input *pb.ResendVerificationEmailRequest) (*pb.ResendVerificationEmailResponse, error) {
+
+	err := server.authService.ResendVerificationEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ResendVerificationEmailResponse{
+		Message: i18n.Translate(ctx, "message.resend_verification_email"),
+	}, nil
+}
+
+func (server *AuthServer) UpdatePreferredLocale(ctx context.Context, input *pb.UpdatePreferredLocaleRequest) (*pb.UpdatePreferredLocaleResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.authService.UpdatePreferredLocale(ctx, userID, input.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdatePreferredLocaleResponse{
+		Message: i18n.Translate(ctx, "message.update_preferred_locale"),
+	}, nil
+}
+
+func (server *AuthServer) UpdateSubscription(ctx context.Context, input *pb.UpdateSubscriptionRequest) (*pb.UpdateSubscriptionResponse, error) {
+	claims, err := utils.GetClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != string(entities.AdminRole) {
+		return nil, apperror.ErrPermissionDenied
+	}
+	userID, err := ksuid.Parse(input.UserId)
+	if err != nil {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	err = server.authService.UpdateSubscription(ctx, userID, input.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateSubscriptionResponse{
+		Message: i18n.Translate(ctx, "message.update_subscription"),
+	}, nil
+}
+
+func (server *AuthServer) LoginUserViaGoogle(ctx context.Context, _ *emptypb.Empty) (*pb.LoginGoogleResponse, error) {
+	url, err := server.authService.LoginUserViaGoogle(ctx)
+	if err != nil {
+		return nil, err
 	}
 	return &pb.LoginGoogleResponse{
 		Url: url,
@@ -142,13 +204,25 @@ func (server *AuthServer) LoginUserViaGoogle(context.Context, *emptypb.Empty) (*
 
 func (server *AuthServer) LoginUserViaGoogleCallback(ctx context.Context, input *pb.LoginGoogleRequest) (*pb.LoginResponse, error) {
 
-	token, err := server.authService.LoginUserViaGoogleCallback(ctx, input.Email, input.Username, input.PictureUrl)
+	result, err := server.authService.LoginUserViaGoogleCallback(ctx, input.Code, input.State)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
 
+	return toLoginResponse(ctx, result, "message.login_user"), nil
+}
+
+func toLoginResponse(ctx context.Context, result *usecase.LoginResult, messageKey string) *pb.LoginResponse {
+	if result.MFAEnrollmentRequired {
+		messageKey = "message.mfa_enrollment_required"
+	} else if result.MFARequired {
+		messageKey = "message.mfa_code_required"
+	}
 	return &pb.LoginResponse{
-		Message: "Login User Successfully",
-		Token:   token,
-	}, nil
+		Message:               i18n.Translate(ctx, messageKey),
+		Token:                 result.Token,
+		MfaRequired:           result.MFARequired,
+		MfaToken:              result.MFAToken,
+		MfaEnrollmentRequired: result.MFAEnrollmentRequired,
+	}
 }
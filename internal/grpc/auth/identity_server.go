@@ -0,0 +1,96 @@
+package auth_server
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/auth"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (server *AuthServer) LoginViaProvider(ctx context.Context, input *pb.ProviderLoginRequest) (*pb.ProviderLoginResponse, error) {
+	url, err := server.authService.LoginViaProvider(ctx, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProviderLoginResponse{
+		Url: url,
+	}, nil
+}
+
+func (server *AuthServer) LoginViaProviderCallback(ctx context.Context, input *pb.ProviderCallbackRequest) (*pb.LoginResponse, error) {
+	result, err := server.authService.LoginViaProviderCallback(ctx, input.Provider, input.Code, input.State)
+	if err != nil {
+		return nil, err
+	}
+	return toLoginResponse(ctx, result, "message.login_user"), nil
+}
+
+func (server *AuthServer) StartLinkIdentity(ctx context.Context, input *pb.ProviderLoginRequest) (*pb.ProviderLoginResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := server.authService.StartLinkIdentity(ctx, userID, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProviderLoginResponse{
+		Url: url,
+	}, nil
+}
+
+func (server *AuthServer) LinkIdentity(ctx context.Context, input *pb.ProviderCallbackRequest) (*pb.LinkIdentityResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.authService.LinkIdentity(ctx, userID, input.Provider, input.Code, input.State)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LinkIdentityResponse{
+		Message: i18n.Translate(ctx, "message.link_identity"),
+	}, nil
+}
+
+func (server *AuthServer) UnlinkIdentity(ctx context.Context, input *pb.UnlinkIdentityRequest) (*pb.UnlinkIdentityResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.authService.UnlinkIdentity(ctx, userID, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UnlinkIdentityResponse{
+		Message: i18n.Translate(ctx, "message.unlink_identity"),
+	}, nil
+}
+
+func (server *AuthServer) ListIdentities(ctx context.Context, _ *emptypb.Empty) (*pb.ListIdentitiesResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := server.authService.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListIdentitiesResponse{}
+	for _, identity := range identities {
+		response.Identities = append(response.Identities, &pb.Identity{
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
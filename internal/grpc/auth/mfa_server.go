@@ -0,0 +1,75 @@
+package auth_server
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/auth"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (server *AuthServer) EnrollTOTP(ctx context.Context, _ *emptypb.Empty) (*pb.EnrollTOTPResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := server.authService.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EnrollTOTPResponse{
+		OtpauthUrl: enrollment.OTPAuthURL,
+		Secret:     enrollment.Secret,
+		QrPng:      enrollment.QRPNG,
+	}, nil
+}
+
+func (server *AuthServer) ConfirmTOTP(ctx context.Context, input *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	claims, err := utils.GetClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	issueToken := claims.Use == utils.TokenUseMFAEnrollment
+	recoveryCodes, token, err := server.authService.ConfirmTOTP(ctx, claims.UserId, input.Code, issueToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConfirmTOTPResponse{
+		Message:       i18n.Translate(ctx, "message.mfa_enabled"),
+		RecoveryCodes: recoveryCodes,
+		Token:         token,
+	}, nil
+}
+
+func (server *AuthServer) VerifyMFA(ctx context.Context, input *pb.VerifyMFARequest) (*pb.LoginResponse, error) {
+	token, err := server.authService.VerifyMFA(ctx, input.MfaToken, input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{
+		Message: i18n.Translate(ctx, "message.login"),
+		Token:   token,
+	}, nil
+}
+
+func (server *AuthServer) DisableTOTP(ctx context.Context, input *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.authService.DisableTOTP(ctx, userID, input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DisableTOTPResponse{
+		Message: i18n.Translate(ctx, "message.mfa_disabled"),
+	}, nil
+}
@@ -2,7 +2,12 @@ package image_server
 
 import (
 	"context"
-	"fmt"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
 
 	services "github.com/oriastanjung/stellar/internal/services/image" // your existing usecase package
 	pb "github.com/oriastanjung/stellar/proto/image"                   // generated from image_service.proto
@@ -24,50 +29,122 @@ func NewImageServer(imageService services.ImageService) pb.ImageServiceServer {
 // GenerateImage is our gRPC method that constructs the prompt string,
 // calls the usecase, and returns the result.
 func (s *imageServer) GenerateImage(ctx context.Context, req *pb.ImageRequest) (*pb.ImageResponse, error) {
-	// Build the final prompt string (mirroring your original approach)
-	prompt := fmt.Sprintf(`Core Subject: %s
-			Key Descriptors: %s
-			Environment: %s
-			Style: %s
-			Mood/Tone: %s
-			Composition: %s
-			Additional Instructions: %s`,
-		req.CoreSubject,
-		req.KeyDescriptors,
-		req.Environment,
-		req.Style,
-		req.MoodTone,
-		req.Composition,
-		req.AdditionalInstructions,
-	)
-
-	imageURL, filename, err := s.imageService.GenerateImage(ctx, prompt)
+	userID, err := utils.GetUserId(ctx)
 	if err != nil {
-		return &pb.ImageResponse{
-			ImageUrl: "",
-			Filename: "",
-			Error:    err.Error(),
-		}, nil
+		return nil, err
+	}
+
+	imageURL, filename, err := s.imageService.GenerateImage(ctx, userID, organizationID(ctx), buildPrompt(req))
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.ImageResponse{
 		ImageUrl: imageURL,
 		Filename: filename,
-		Error:    "",
 	}, nil
 }
 
+// GenerateImageAsync queues the same prompt as GenerateImage.
+func (s *imageServer) GenerateImageAsync(ctx context.Context, req *pb.ImageRequest) (*pb.ImageJob, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.imageService.GenerateImageAsync(ctx, userID, organizationID(ctx), buildPrompt(req))
+	if err != nil {
+		return nil, err
+	}
+	return toImageJob(job), nil
+}
+
+// GetImageJob returns the state of a job started by GenerateImageAsync.
+func (s *imageServer) GetImageJob(ctx context.Context, req *pb.GetImageJobRequest) (*pb.ImageJob, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobID, err := ksuid.Parse(req.GetId())
+	if err != nil {
+		return nil, apperror.ErrImageJobNotFound
+	}
+
+	job, err := s.imageService.GetImageJob(ctx, userID, organizationID(ctx), jobID)
+	if err != nil {
+		return nil, err
+	}
+	return toImageJob(job), nil
+}
+
+// ListImageJobs pages through the personal library, or the shared library
+// of the organization selected with x-org-id.
+func (s *imageServer) ListImageJobs(ctx context.Context, req *pb.ListImageJobsRequest) (*pb.ListImageJobsResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, nextPageToken, err := s.imageService.ListImageJobs(ctx, userID, organizationID(ctx), int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	result := &pb.ListImageJobsResponse{NextPageToken: nextPageToken}
+	for i := range jobs {
+		result.Jobs = append(result.Jobs, toImageJob(&jobs[i]))
+	}
+	return result, nil
+}
+
 // DownloadAndSaveImage uses the image URL and filename to download locally.
 func (s *imageServer) DownloadAndSaveImage(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadResponse, error) {
-	err := s.imageService.DownloadAndSaveImages(ctx, req.GetImageUrl(), req.GetFilename())
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.imageService.DownloadAndSaveImages(ctx, userID, req.GetImageUrl(), req.GetFilename())
 	if err != nil {
-		return &pb.DownloadResponse{
-			Success: false,
-			Error:   err.Error(),
-		}, nil
+		return nil, err
 	}
 	return &pb.DownloadResponse{
 		Success: true,
-		Error:   "",
 	}, nil
 }
+
+// buildPrompt builds the final prompt string (mirroring your original approach)
+func buildPrompt(req *pb.ImageRequest) string {
+	return entities.ImagePrompt{
+		CoreSubject:            req.CoreSubject,
+		KeyDescriptors:         req.KeyDescriptors,
+		Environment:            req.Environment,
+		Style:                  req.Style,
+		MoodTone:               req.MoodTone,
+		Composition:            req.Composition,
+		AdditionalInstructions: req.AdditionalInstructions,
+	}.String()
+}
+
+// organizationID is the organization selected with x-org-id, ksuid.Nil for
+// the personal account.
+func organizationID(ctx context.Context) ksuid.KSUID {
+	if membership := utils.GetMembership(ctx); membership != nil {
+		return membership.OrganizationID
+	}
+	return ksuid.Nil
+}
+
+func toImageJob(job *entities.ImageJob) *pb.ImageJob {
+	result := &pb.ImageJob{
+		Id:        job.ID.String(),
+		Status:    string(job.Status),
+		ImageUrl:  job.ImageURL,
+		Filename:  job.Filename,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+		CreatedBy: job.UserID.String(),
+	}
+	if job.FinishedAt != nil {
+		result.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package notification_server
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	services "github.com/oriastanjung/stellar/internal/services/notification"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/notification"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type NotificationServer struct {
+	pb.NotificationServiceServer
+	notificationService services.NotificationService
+}
+
+func NewNotificationServer(notificationService services.NotificationService) *NotificationServer {
+	return &NotificationServer{
+		notificationService: notificationService,
+	}
+}
+
+func (server *NotificationServer) GetNotificationPreferences(ctx context.Context, _ *emptypb.Empty) (*pb.NotificationPreferences, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := server.notificationService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toPreferencesResponse(preferences), nil
+}
+
+func (server *NotificationServer) UpdateNotificationPreferences(ctx context.Context, input *pb.NotificationPreferences) (*pb.NotificationPreferences, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]entities.NotificationPreference, 0, len(input.Preferences))
+	for _, preference := range input.Preferences {
+		preferences = append(preferences, entities.NotificationPreference{
+			Event:        entities.NotificationEvent(preference.Event),
+			EmailEnabled: preference.Email,
+		})
+	}
+
+	updated, err := server.notificationService.UpdatePreferences(ctx, userID, preferences)
+	if err != nil {
+		return nil, err
+	}
+	return toPreferencesResponse(updated), nil
+}
+
+func (server *NotificationServer) Unsubscribe(ctx context.Context, input *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error) {
+	err := server.notificationService.Unsubscribe(ctx, input.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UnsubscribeResponse{
+		Message: i18n.Translate(ctx, "message.unsubscribe"),
+	}, nil
+}
+
+func toPreferencesResponse(preferences []entities.NotificationPreference) *pb.NotificationPreferences {
+	response := &pb.NotificationPreferences{}
+	for _, preference := range preferences {
+		response.Preferences = append(response.Preferences, &pb.NotificationPreference{
+			Event: string(preference.Event),
+			Email: preference.EmailEnabled,
+		})
+	}
+	return response
+}
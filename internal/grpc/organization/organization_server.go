@@ -0,0 +1,238 @@
+package organization_server
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	services "github.com/oriastanjung/stellar/internal/services/organization"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/organization"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type OrganizationServer struct {
+	pb.OrganizationServiceServer
+	organizationService services.OrganizationService
+}
+
+func NewOrganizationServer(organizationService services.OrganizationService) *OrganizationServer {
+	return &OrganizationServer{
+		organizationService: organizationService,
+	}
+}
+
+func (server *OrganizationServer) CreateOrganization(ctx context.Context, input *pb.CreateOrganizationRequest) (*pb.Organization, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := server.organizationService.CreateOrganization(ctx, userID, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	return toOrganization(organization, entities.OrganizationOwner), nil
+}
+
+// SetGenerationQuota is admin only, the caller does not need to be a member.
+func (server *OrganizationServer) SetGenerationQuota(ctx context.Context, input *pb.SetGenerationQuotaRequest) (*pb.Organization, error) {
+	claims, err := utils.GetClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != string(entities.AdminRole) {
+		return nil, apperror.ErrPermissionDenied
+	}
+	organizationID, err := ksuid.Parse(input.OrganizationId)
+	if err != nil {
+		return nil, apperror.ErrOrganizationNotFound
+	}
+
+	organization, err := server.organizationService.SetGenerationQuota(ctx, claims.UserId, organizationID, int(input.GenerationQuota))
+	if err != nil {
+		return nil, err
+	}
+	return toOrganization(organization, ""), nil
+}
+
+func (server *OrganizationServer) ListOrganizations(ctx context.Context, _ *emptypb.Empty) (*pb.ListOrganizationsResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	organizations, err := server.organizationService.ListOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListOrganizationsResponse{}
+	for i := range organizations {
+		response.Organizations = append(response.Organizations, toOrganization(&organizations[i].Organization, organizations[i].Role))
+	}
+	return response, nil
+}
+
+func (server *OrganizationServer) DeleteOrganization(ctx context.Context, _ *emptypb.Empty) (*pb.MessageResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := server.organizationService.DeleteOrganization(ctx, actor); err != nil {
+		return nil, err
+	}
+	return &pb.MessageResponse{
+		Message: i18n.Translate(ctx, "message.delete_organization"),
+	}, nil
+}
+
+func (server *OrganizationServer) InviteMember(ctx context.Context, input *pb.InviteMemberRequest) (*pb.Invitation, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := server.organizationService.InviteMember(ctx, actor, input.Email, entities.OrganizationRole(input.Role))
+	if err != nil {
+		return nil, err
+	}
+	return toInvitation(invitation), nil
+}
+
+func (server *OrganizationServer) ListInvitations(ctx context.Context, _ *emptypb.Empty) (*pb.ListInvitationsResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := server.organizationService.ListInvitations(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListInvitationsResponse{}
+	for i := range invitations {
+		response.Invitations = append(response.Invitations, toInvitation(&invitations[i]))
+	}
+	return response, nil
+}
+
+func (server *OrganizationServer) RevokeInvitation(ctx context.Context, input *pb.IdRequest) (*pb.MessageResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invitationID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrInvitationNotFound
+	}
+
+	if err := server.organizationService.RevokeInvitation(ctx, actor, invitationID); err != nil {
+		return nil, err
+	}
+	return &pb.MessageResponse{
+		Message: i18n.Translate(ctx, "message.revoke_invitation"),
+	}, nil
+}
+
+func (server *OrganizationServer) AcceptInvitation(ctx context.Context, input *pb.AcceptInvitationRequest) (*pb.Member, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := server.organizationService.AcceptInvitation(ctx, userID, input.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Member{
+		OrganizationId: membership.OrganizationID.String(),
+		UserId:         membership.UserID.String(),
+		Role:           string(membership.Role),
+		JoinedAt:       membership.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (server *OrganizationServer) ListMembers(ctx context.Context, _ *emptypb.Empty) (*pb.ListMembersResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := server.organizationService.ListMembers(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListMembersResponse{}
+	for _, member := range members {
+		response.Members = append(response.Members, &pb.Member{
+			OrganizationId: member.OrganizationID.String(),
+			UserId:         member.UserID.String(),
+			Username:       member.User.Username,
+			Email:          member.User.Email,
+			Role:           string(member.Role),
+			JoinedAt:       member.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+func (server *OrganizationServer) UpdateMemberRole(ctx context.Context, input *pb.UpdateMemberRoleRequest) (*pb.MessageResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := ksuid.Parse(input.UserId)
+	if err != nil {
+		return nil, apperror.ErrMemberNotFound
+	}
+
+	if err := server.organizationService.UpdateMemberRole(ctx, actor, userID, entities.OrganizationRole(input.Role)); err != nil {
+		return nil, err
+	}
+	return &pb.MessageResponse{
+		Message: i18n.Translate(ctx, "message.update_member_role"),
+	}, nil
+}
+
+func (server *OrganizationServer) RemoveMember(ctx context.Context, input *pb.IdRequest) (*pb.MessageResponse, error) {
+	actor, err := utils.RequireMembership(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrMemberNotFound
+	}
+
+	if err := server.organizationService.RemoveMember(ctx, actor, userID); err != nil {
+		return nil, err
+	}
+	return &pb.MessageResponse{
+		Message: i18n.Translate(ctx, "message.remove_member"),
+	}, nil
+}
+
+func toOrganization(organization *entities.Organization, role entities.OrganizationRole) *pb.Organization {
+	return &pb.Organization{
+		Id:              organization.ID.String(),
+		Name:            organization.Name,
+		GenerationQuota: int32(organization.GenerationQuota),
+		Role:            string(role),
+		CreatedAt:       organization.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toInvitation(invitation *entities.OrganizationInvitation) *pb.Invitation {
+	return &pb.Invitation{
+		Id:        invitation.ID.String(),
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		InvitedBy: invitation.InvitedBy.String(),
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
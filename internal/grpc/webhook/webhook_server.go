@@ -0,0 +1,154 @@
+package webhook_server
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	services "github.com/oriastanjung/stellar/internal/services/webhook"
+	"github.com/oriastanjung/stellar/internal/utils"
+	pb "github.com/oriastanjung/stellar/proto/webhook"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type WebhookServer struct {
+	pb.WebhookServiceServer
+	webhookService services.WebhookService
+}
+
+func NewWebhookServer(webhookService services.WebhookService) *WebhookServer {
+	return &WebhookServer{
+		webhookService: webhookService,
+	}
+}
+
+func (server *WebhookServer) CreateWebhook(ctx context.Context, input *pb.CreateWebhookRequest) (*pb.CreateWebhookResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]entities.WebhookEvent, 0, len(input.Events))
+	for _, event := range input.Events {
+		events = append(events, entities.WebhookEvent(event))
+	}
+
+	webhook, secret, err := server.webhookService.CreateWebhook(ctx, userID, input.Url, events)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateWebhookResponse{
+		Webhook: toWebhook(webhook),
+		Secret:  secret,
+	}, nil
+}
+
+func (server *WebhookServer) ListWebhooks(ctx context.Context, _ *emptypb.Empty) (*pb.ListWebhooksResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := server.webhookService.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListWebhooksResponse{}
+	for i := range webhooks {
+		response.Webhooks = append(response.Webhooks, toWebhook(&webhooks[i]))
+	}
+	return response, nil
+}
+
+func (server *WebhookServer) DeleteWebhook(ctx context.Context, input *pb.WebhookIdRequest) (*pb.DeleteWebhookResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhookID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrWebhookNotFound
+	}
+
+	if err := server.webhookService.DeleteWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteWebhookResponse{
+		Message: i18n.Translate(ctx, "message.delete_webhook"),
+	}, nil
+}
+
+func (server *WebhookServer) ListWebhookDeliveries(ctx context.Context, input *pb.WebhookIdRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhookID, err := ksuid.Parse(input.Id)
+	if err != nil {
+		return nil, apperror.ErrWebhookNotFound
+	}
+
+	deliveries, err := server.webhookService.ListDeliveries(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.ListWebhookDeliveriesResponse{}
+	for i := range deliveries {
+		response.Deliveries = append(response.Deliveries, toDelivery(&deliveries[i]))
+	}
+	return response, nil
+}
+
+func (server *WebhookServer) RedeliverWebhook(ctx context.Context, input *pb.RedeliverWebhookRequest) (*pb.WebhookDelivery, error) {
+	userID, err := utils.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deliveryID, err := ksuid.Parse(input.DeliveryId)
+	if err != nil {
+		return nil, apperror.ErrWebhookDeliveryNotFound
+	}
+
+	delivery, err := server.webhookService.RedeliverWebhook(ctx, userID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return toDelivery(delivery), nil
+}
+
+func toWebhook(webhook *entities.Webhook) *pb.Webhook {
+	result := &pb.Webhook{
+		Id:        webhook.ID.String(),
+		Url:       webhook.URL,
+		CreatedAt: webhook.CreatedAt.Format(time.RFC3339),
+	}
+	for _, event := range webhook.Events {
+		result.Events = append(result.Events, string(event))
+	}
+	return result
+}
+
+func toDelivery(delivery *entities.WebhookDelivery) *pb.WebhookDelivery {
+	result := &pb.WebhookDelivery{
+		Id:             delivery.ID.String(),
+		WebhookId:      delivery.WebhookID.String(),
+		EventId:        delivery.EventID.String(),
+		Event:          string(delivery.Event),
+		Status:         string(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		ResponseStatus: int32(delivery.ResponseStatus),
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		Payload:        delivery.Payload,
+	}
+	if delivery.Status == entities.WebhookDeliveryPending {
+		result.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+// Package health runs the dependency checks behind grpc.health.v1 and the
+// /healthz and /readyz HTTP probes.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check is one dependency. A failing critical check takes the whole server
+// out of rotation, the others only the services depending on them.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// Result is the outcome of the last run of a check.
+type Result struct {
+	Name      string    `json:"name"`
+	Critical  bool      `json:"critical"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Monitor runs the checks every interval and keeps the status of each
+// gRPC service in server up to date. The probes read the last results so
+// they stay cheap however often they are called.
+type Monitor struct {
+	server   *grpchealth.Server
+	interval time.Duration
+	timeout  time.Duration
+
+	mu       sync.RWMutex
+	checks   []Check
+	services map[string][]string
+	results  map[string]Result
+	checked  bool
+	draining atomic.Bool
+}
+
+// NewMonitor reports NOT_SERVING for everything until the first run.
+func NewMonitor(server *grpchealth.Server, interval time.Duration, timeout time.Duration) *Monitor {
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return &Monitor{
+		server:   server,
+		interval: interval,
+		timeout:  timeout,
+		services: map[string][]string{},
+		results:  map[string]Result{},
+	}
+}
+
+func (m *Monitor) AddCheck(check Check) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks = append(m.checks, check)
+}
+
+// AddService registers a gRPC service name with the non critical checks it
+// depends on, the critical ones apply to every service.
+func (m *Monitor) AddService(service string, checks ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.services[service] = checks
+	m.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Run checks right away and then every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.CheckNow(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow runs every check concurrently, each within the timeout, and
+// updates the service statuses.
+func (m *Monitor) CheckNow(ctx context.Context) {
+	m.mu.RLock()
+	checks := append([]Check(nil), m.checks...)
+	m.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, m.timeout)
+			defer cancel()
+			result := Result{Name: check.Name, Critical: check.Critical, Healthy: true, CheckedAt: time.Now()}
+			if err := check.Run(checkCtx); err != nil {
+				result.Healthy = false
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, result := range results {
+		m.results[result.Name] = result
+	}
+	m.checked = true
+	m.updateStatuses()
+}
+
+// updateStatuses must hold mu. Nothing is SERVING again once draining.
+func (m *Monitor) updateStatuses() {
+	if m.draining.Load() {
+		return
+	}
+	m.server.SetServingStatus("", servingStatus(m.healthy(nil)))
+	for service, checks := range m.services {
+		m.server.SetServingStatus(service, servingStatus(m.healthy(checks)))
+	}
+}
+
+// healthy reports whether the critical checks and the named ones passed.
+func (m *Monitor) healthy(names []string) bool {
+	if !m.checked {
+		return false
+	}
+	for _, result := range m.results {
+		if result.Critical && !result.Healthy {
+			return false
+		}
+	}
+	for _, name := range names {
+		if result, ok := m.results[name]; !ok || !result.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func servingStatus(healthy bool) healthpb.HealthCheckResponse_ServingStatus {
+	if healthy {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// Shutdown reports NOT_SERVING for every service from now on, it is the
+// first shutdown step so load balancers stop routing here.
+func (m *Monitor) Shutdown() {
+	m.draining.Store(true)
+	m.server.Shutdown()
+}
+
+// Ready reports whether the server should receive traffic, with the last
+// result of every check.
+func (m *Monitor) Ready() (bool, []Result) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := make([]Result, 0, len(m.checks))
+	for _, check := range m.checks {
+		if result, ok := m.results[check.Name]; ok {
+			results = append(results, result)
+		}
+	}
+	return !m.draining.Load() && m.healthy(nil), results
+}
@@ -0,0 +1,41 @@
+package http_server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/oriastanjung/stellar/internal/health"
+)
+
+// NewLivenessHandler answers /healthz, the process is alive as long as it
+// can respond, dependencies are left to /readyz so an outage of one does
+// not get every replica restarted.
+func NewLivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok\n"))
+	})
+}
+
+type readiness struct {
+	Ready  bool            `json:"ready"`
+	Checks []health.Result `json:"checks"`
+}
+
+// NewReadinessHandler answers /readyz with 200 while the critical checks
+// pass and 503 otherwise, the body lists the result of every check.
+func NewReadinessHandler(monitor *health.Monitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ready, results := monitor.Ready()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(readiness{Ready: ready, Checks: results}); err != nil {
+			log.Printf("Error encoding readiness: %v", err)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package http_server
+
+import (
+	"net/http"
+
+	"github.com/oriastanjung/stellar/internal/health"
+	"github.com/oriastanjung/stellar/internal/metrics"
+	"github.com/oriastanjung/stellar/internal/utils"
+)
+
+// NewServeMux builds the HTTP routes served next to the gRPC server.
+func NewServeMux(ring *utils.KeyRing, monitor *health.Monitor) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/.well-known/jwks.json", NewJWKSHandler(ring))
+	mux.Handle("/healthz", NewLivenessHandler())
+	mux.Handle("/readyz", NewReadinessHandler(monitor))
+	mux.Handle("/metrics", metrics.Handler())
+	return mux
+}
@@ -0,0 +1,28 @@
+package http_server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/oriastanjung/stellar/internal/utils"
+)
+
+// NewJWKSHandler serves the public signing keys so other services can
+// verify tokens issued by this one.
+func NewJWKSHandler(ring *utils.KeyRing) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		// keep caches shorter than the reload interval of verifiers
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(ring.JWKS()); err != nil {
+			log.Printf("Error encoding JWKS: %v", err)
+		}
+	})
+}
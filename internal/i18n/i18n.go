@@ -0,0 +1,177 @@
+// Package i18n holds the message catalogs of the supported locales and
+// negotiates the locale of a request from its accept-language.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLocale is used when the client asks for a locale without a catalog.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	catalogs map[string]map[string]string
+	loadOnce sync.Once
+)
+
+// load reads locales/<locale>.json, the catalogs are embedded so an invalid
+// one is a programming error.
+func load() {
+	catalogs = map[string]map[string]string{}
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: error reading catalogs: %v", err))
+	}
+	for _, file := range files {
+		data, err := localeFS.ReadFile("locales/" + file.Name())
+		if err != nil {
+			panic(fmt.Sprintf("i18n: error reading %s: %v", file.Name(), err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: error parsing %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = catalog
+	}
+}
+
+// Locales returns the supported locales.
+func Locales() []string {
+	loadOnce.Do(load)
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Normalize maps a language tag like "id-ID" to a supported locale, it
+// returns DefaultLocale for unsupported tags.
+func Normalize(tag string) string {
+	loadOnce.Do(load)
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	if _, ok := catalogs[language]; ok {
+		return language
+	}
+	return DefaultLocale
+}
+
+// Negotiate picks the supported locale the client prefers most from an
+// accept-language value such as "id-ID,id;q=0.9,en;q=0.8".
+func Negotiate(acceptLanguage string) string {
+	loadOnce.Do(load)
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return DefaultLocale
+		}
+		language, _, _ := strings.Cut(strings.ToLower(c.tag), "-")
+		if _, ok := catalogs[language]; ok {
+			return language
+		}
+	}
+	return DefaultLocale
+}
+
+// Lookup returns the message for key in locale without falling back.
+func Lookup(locale string, key string) (string, bool) {
+	loadOnce.Do(load)
+	message, ok := catalogs[locale][key]
+	return message, ok
+}
+
+// T returns the message for key in locale, falling back to DefaultLocale
+// and then to the key itself. Args are applied with fmt.Sprintf.
+func T(locale string, key string, args ...interface{}) string {
+	message, ok := Lookup(locale, key)
+	if !ok {
+		if message, ok = Lookup(DefaultLocale, key); !ok {
+			message = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// FormatDuration writes a lifetime such as the expiry of an emailed link
+// in the largest whole unit, e.g. "7 days" or "30 minutes".
+func FormatDuration(locale string, d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d == day:
+		return T(locale, "duration.day", 1)
+	case d > day && d%day == 0:
+		return T(locale, "duration.days", int(d/day))
+	case d == time.Hour:
+		return T(locale, "duration.hour", 1)
+	case d > time.Hour && d%time.Hour == 0:
+		return T(locale, "duration.hours", int(d/time.Hour))
+	case d == time.Minute:
+		return T(locale, "duration.minute", 1)
+	case d > time.Minute:
+		return T(locale, "duration.minutes", int(d/time.Minute))
+	default:
+		return d.String()
+	}
+}
+
+type contextKey struct{}
+
+// WithLocale stores the negotiated locale of a request in ctx.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale stored by WithLocale or DefaultLocale.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Translate is T with the locale of the request in ctx.
+func Translate(ctx context.Context, key string, args ...interface{}) string {
+	return T(FromContext(ctx), key, args...)
+}
@@ -0,0 +1,131 @@
+// Package lifecycle stops the parts of the server in a fixed order when
+// the process is asked to terminate.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type step struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager runs the registered shutdown steps one after another in the
+// order they were added, all of them share a single deadline.
+type Manager struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	steps   []step
+	// workers is the context of the workers started with Go
+	workers       context.Context
+	cancelWorkers context.CancelFunc
+	running       sync.WaitGroup
+	done          chan struct{}
+	once          sync.Once
+}
+
+// New returns a manager that gives the whole shutdown timeout to finish.
+func New(timeout time.Duration) *Manager {
+	workers, cancel := context.WithCancel(context.Background())
+	return &Manager{timeout: timeout, workers: workers, cancelWorkers: cancel, done: make(chan struct{})}
+}
+
+// OnShutdown adds a step, fn should give up once ctx is done.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.steps = append(m.steps, step{name: name, stop: fn})
+}
+
+// Go runs a background worker until DrainWorkers cancels its context.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		run(m.workers)
+		if m.workers.Err() == nil {
+			log.Printf("Background worker %s stopped before shutdown", name)
+		}
+	}()
+}
+
+// DrainWorkers is the shutdown step of the workers started with Go, it
+// cancels their context and waits for them to return.
+func (m *Manager) DrainWorkers(ctx context.Context) error {
+	m.cancelWorkers()
+	return Wait(ctx, &m.running)
+}
+
+// Wait waits for group or until ctx is done.
+func Wait(ctx context.Context, group *sync.WaitGroup) error {
+	finished := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitForSignal blocks until SIGINT or SIGTERM and then shuts down.
+func (m *Manager) WaitForSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down gracefully...", sig)
+		signal.Stop(signals)
+		m.Shutdown()
+	case <-m.done:
+	}
+}
+
+// Shutdown runs every step once, a failing or late step is logged and
+// the next one still runs so the database is always closed last.
+func (m *Manager) Shutdown() {
+	m.once.Do(func() {
+		defer close(m.done)
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		steps := append([]step(nil), m.steps...)
+		m.mu.Unlock()
+		for _, step := range steps {
+			started := time.Now()
+			if err := m.run(ctx, step); err != nil {
+				log.Printf("Shutdown step %s failed after %s: %v", step.name, time.Since(started).Round(time.Millisecond), err)
+				continue
+			}
+			log.Printf("Shutdown step %s done in %s", step.name, time.Since(started).Round(time.Millisecond))
+		}
+		log.Println("Shutdown complete")
+	})
+}
+
+// run recovers a panicking step so it cannot skip the ones after it.
+func (m *Manager) run(ctx context.Context, step step) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return step.stop(ctx)
+}
+
+// Done is closed once Shutdown has finished.
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
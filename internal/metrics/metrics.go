@@ -0,0 +1,99 @@
+// Package metrics defines the Prometheus metrics of the service, they are
+// served on /metrics of the HTTP server.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "stellar"
+
+// Registry holds every metric below plus the Go runtime and process
+// collectors, it is separate from the default registry so libraries
+// cannot add to /metrics by importing it.
+var Registry = prometheus.NewRegistry()
+
+// RPCs
+var (
+	RPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "gRPC requests handled, by method and status code.",
+	}, []string{"method", "code"})
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Time to handle a gRPC request, by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	RPCInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_in_flight",
+		Help:      "gRPC requests being handled, by method.",
+	}, []string{"method"})
+)
+
+// Image generation and downloads
+var (
+	GenerationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "image_generation_duration_seconds",
+		Help:      "Time the upstream API took to generate an image, by provider and outcome.",
+		// generations take seconds to minutes
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"provider", "outcome"})
+	UpstreamErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_upstream_errors_total",
+		Help:      "Failed calls to the image generation API, by provider and class: transport, timeout, status_4xx, status_5xx, read or circuit_open.",
+	}, []string{"provider", "class"})
+	ParseFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_parse_failures_total",
+		Help:      "Generation responses without a usable image URL, by reason.",
+	}, []string{"reason"})
+	DownloadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_download_bytes_total",
+		Help:      "Bytes of generated images downloaded from the upstream.",
+	})
+	EncodeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "image_encode_duration_seconds",
+		Help:      "Time to encode a downloaded image, by format.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"format"})
+)
+
+// Emails and sessions
+var (
+	Emails = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_total",
+		Help:      "Outbox emails by result: sent, retry (failed, will be tried again) or failed (gave up).",
+	}, []string{"result"})
+	ActiveSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Devices that logged in within the access token TTL, an upper bound of the valid access tokens.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RPCRequests, RPCDuration, RPCInFlight,
+		GenerationDuration, UpstreamErrors, ParseFailures, DownloadBytes, EncodeDuration,
+		Emails, ActiveSessions,
+	)
+}
+
+// Handler serves the metrics of Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
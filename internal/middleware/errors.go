@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrorUnaryInterceptor negotiates the locale from the accept-language
+// metadata, stores it in the context for the handlers and turns every
+// error into a status with ErrorInfo and a message in that locale.
+// MetricsUnaryInterceptor is the only one wrapping it, it records the
+// converted codes and returns no errors of its own. Every other interceptor
+// goes after it so their errors are converted too.
+func ErrorUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	locale := requestLocale(ctx)
+	resp, err := handler(i18n.WithLocale(ctx, locale), req)
+	if err != nil {
+		return nil, apperror.ToStatus(err, locale).Err()
+	}
+	return resp, nil
+}
+
+// requestLocale returns the supported locale the client prefers most.
+func requestLocale(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return i18n.DefaultLocale
+	}
+	return i18n.Negotiate(strings.Join(md.Get("accept-language"), ","))
+}
@@ -3,61 +3,172 @@ package middleware
 import (
 	"context"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
 	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 )
 
-func TokenValidationUnaryInterceptor(
-	ctx context.Context,
-	req interface{},
-	info *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (interface{}, error) {
-	// List of methods to skip token validation
-	skipMethods := map[string]bool{
-		"/auth.AuthServiceRoutes/SignUpAdmin":                true,
-		"/auth.AuthServiceRoutes/LoginAdmin":                 true,
-		"/auth.AuthServiceRoutes/SignUpUser":                 true,
-		"/auth.AuthServiceRoutes/LoginUser":                  true,
-		"/auth.AuthServiceRoutes/VerifyUser":                 true,
-		"/auth.AuthServiceRoutes/LoginUserViaGoogle":         true,
-		"/auth.AuthServiceRoutes/LoginUserViaGoogleCallback": true,
-		"/auth.AuthServiceRoutes/RequestForgetPassword":      true,
-		"/auth.AuthServiceRoutes/ResetPasswordByToken":       true,
-	}
+// APIKeyAuthenticator resolves the x-api-key metadata to the key and its
+// owner, implemented by the apikey usecase.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, *entities.User, error)
+}
+
+// apiKeyScopes lists the only methods API keys may call and the scope each
+// of them needs, everything else requires an access token.
+var apiKeyScopes = map[string]entities.APIKeyScope{
+	"/images.ImageService/GenerateImage":        entities.APIKeyScopeImageGenerate,
+	"/images.ImageService/GenerateImageAsync":   entities.APIKeyScopeImageGenerate,
+	"/images.ImageService/DownloadAndSaveImage": entities.APIKeyScopeImageGenerate,
+	"/images.ImageService/GetImageJob":          entities.APIKeyScopeImageRead,
+	"/images.ImageService/ListImageJobs":        entities.APIKeyScopeImageRead,
+}
+
+// MembershipFinder resolves the x-org-id metadata to the membership of the
+// caller, implemented by the organization usecase.
+type MembershipFinder interface {
+	FindMembership(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error)
+}
+
+// NewTokenValidationUnaryInterceptor authenticates calls with the access
+// token in the authorization metadata or a personal API key in x-api-key,
+// and checks the caller is a member of the organization in x-org-id.
+func NewTokenValidationUnaryInterceptor(tokens utils.TokenIssuer, apiKeys APIKeyAuthenticator, memberships MembershipFinder) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		// List of methods to skip token validation
+		skipMethods := map[string]bool{
+			"/auth.AuthServiceRoutes/SignUpAdmin":                true, // gated by the bootstrap or invitation token
+			"/auth.AuthServiceRoutes/LoginAdmin":                 true,
+			"/auth.AuthServiceRoutes/SignUpUser":                 true,
+			"/auth.AuthServiceRoutes/LoginUser":                  true,
+			"/auth.AuthServiceRoutes/VerifyUser":                 true,
+			"/auth.AuthServiceRoutes/LoginUserViaGoogle":         true,
+			"/auth.AuthServiceRoutes/LoginUserViaGoogleCallback": true,
+			"/auth.AuthServiceRoutes/RequestForgetPassword":      true,
+			"/auth.AuthServiceRoutes/ResetPasswordByToken":       true,
+			"/auth.AuthServiceRoutes/UnlockAccount":              true,
+			"/auth.AuthServiceRoutes/ResendVerificationEmail":    true,
+			"/auth.AuthServiceRoutes/VerifyMFA":                  true,
+			"/auth.AuthServiceRoutes/LoginViaProvider":           true,
+			"/auth.AuthServiceRoutes/LoginViaProviderCallback":   true,
+			"/notification.NotificationService/Unsubscribe":      true,
+			"/grpc.health.v1.Health/Check":                       true, // probes of the orchestrator
+		}
+
+		// Methods an MFA enrollment token may call, admins that must enroll
+		// 2FA receive such a token instead of an access token
+		enrollmentMethods := map[string]bool{
+			"/auth.AuthServiceRoutes/EnrollTOTP":  true,
+			"/auth.AuthServiceRoutes/ConfirmTOTP": true,
+		}
+
+		// Check if the method should skip token validation
+		if skipMethods[info.FullMethod] {
+			// Skip token validation
+			return handler(ctx, req)
+		}
+
+		// Extract metadata from the context
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, apperror.ErrInvalidAccessToken
+		}
 
-	// Check if the method should skip token validation
-	if skipMethods[info.FullMethod] {
-		// Skip token validation
+		// Get the authorization header value
+		values := md["authorization"]
+		var claims *utils.JWTClaims
+		var err error
+		if keys := md["x-api-key"]; len(keys) > 0 {
+			if len(values) > 0 {
+				return nil, apperror.ErrAmbiguousCredentials
+			}
+			claims, err = authenticateAPIKey(ctx, info, apiKeys, keys[0])
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			if len(values) == 0 {
+				return nil, apperror.ErrInvalidAccessToken
+			}
+
+			// Extract and clean up the token
+			token := values[0]
+
+			// Verify the token
+			claims, err = tokens.Verify(token, utils.TokenUseAccess, utils.TokenUseMFAEnrollment)
+			if err != nil {
+				return nil, apperror.ErrInvalidAccessToken.Wrap(err)
+			}
+
+			// Only access tokens are accepted, except for 2FA enrollment
+			switch {
+			case claims.Use == utils.TokenUseAccess:
+			case claims.Use == utils.TokenUseMFAEnrollment && enrollmentMethods[info.FullMethod]:
+			default:
+				return nil, apperror.ErrTokenNotAllowed.WithMetadata("method", info.FullMethod)
+			}
+		}
+		// Add claims to context for use in downstream handlers
+		ctx = context.WithValue(ctx, "claims", claims)
+
+		// x-org-id selects the organization the call acts on, the caller
+		// must be a member of it
+		if orgIDs := md["x-org-id"]; len(orgIDs) > 0 && claims.Use != utils.TokenUseMFAEnrollment {
+			organizationID, err := ksuid.Parse(orgIDs[0])
+			if err != nil {
+				return nil, apperror.ErrOrganizationNotFound
+			}
+			membership, err := memberships.FindMembership(organizationID, claims.UserId)
+			if err != nil {
+				return nil, err
+			}
+			ctx = context.WithValue(ctx, "membership", membership)
+		}
+
+		// Proceed to the handler
 		return handler(ctx, req)
 	}
+}
 
-	// Extract metadata from the context
-	md, ok := metadata.FromIncomingContext(ctx)
+// authenticateAPIKey returns claims of the key owner, like an access token
+// would carry, after checking the key has the scope of the method.
+func authenticateAPIKey(
+	ctx context.Context,
+	info *grpc.UnaryServerInfo,
+	apiKeys APIKeyAuthenticator,
+	rawKey string,
+) (*utils.JWTClaims, error) {
+	scope, ok := apiKeyScopes[info.FullMethod]
 	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "metadata not provided")
-	}
-
-	// Get the authorization header value
-	values := md["authorization"]
-	if len(values) == 0 {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid or missing token")
+		return nil, apperror.ErrTokenNotAllowed.WithMetadata("method", info.FullMethod)
 	}
 
-	// Extract and clean up the token
-	token := values[0]
-
-	// Verify the token
-	claims, err := utils.VerifyTokenJWT(token)
+	key, user, err := apiKeys.Authenticate(ctx, rawKey)
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		return nil, err
+	}
+	if !key.HasScope(scope) {
+		return nil, apperror.ErrInsufficientScope.WithMetadata("scope", string(scope))
 	}
-	// Add claims to context for use in downstream handlers
-	ctx = context.WithValue(ctx, "claims", claims)
 
-	// Proceed to the handler
-	return handler(ctx, req)
+	return &utils.JWTClaims{
+		UserId:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		Use:      utils.TokenUseAPIKey,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: user.ID.String(),
+			ID:      key.ID.String(),
+		},
+	}, nil
 }
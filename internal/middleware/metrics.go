@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsUnaryInterceptor records latency, status code and in-flight count
+// per method. It goes before ErrorUnaryInterceptor so it sees the codes the
+// client receives.
+func MetricsUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	inFlight := metrics.RPCInFlight.WithLabelValues(info.FullMethod)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	started := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.RPCDuration.WithLabelValues(info.FullMethod).Observe(time.Since(started).Seconds())
+	metrics.RPCRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	return resp, err
+}
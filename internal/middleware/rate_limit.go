@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// emailRequest is implemented by every request message with an email field.
+type emailRequest interface {
+	GetEmail() string
+}
+
+// NewRateLimitUnaryInterceptor throttles the methods listed by rules, which
+// is called per request so reloaded limits apply right away. Calls are
+// keyed separately by peer IP, request email and authenticated user so a
+// single attacker cannot spread attempts over many accounts or addresses.
+// It must run after the token validation interceptor to see the user.
+func NewRateLimitUnaryInterceptor(store ratelimit.Store, rules func() map[string]config.RateLimitRule) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+		rule, ok := rules()[method]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		keys := []string{}
+		if ip := utils.GetClientInfo(ctx).IP; ip != "" {
+			keys = append(keys, "ip:"+ip)
+		}
+		if r, ok := req.(emailRequest); ok && r.GetEmail() != "" {
+			keys = append(keys, "email:"+strings.ToLower(strings.TrimSpace(r.GetEmail())))
+		}
+		if userID, err := utils.GetUserId(ctx); err == nil {
+			keys = append(keys, "user:"+userID.String())
+		}
+
+		for _, key := range keys {
+			allowed, wait, err := store.Allow(ctx, method+"|"+key, ratelimit.Rule{Rate: rule.Rate, Burst: rule.Burst})
+			if err != nil {
+				// fail open, an unavailable store must not take logins down
+				log.Printf("Error checking rate limit for %s: %v", method, err)
+				continue
+			}
+			if !allowed {
+				return nil, rateLimitedError(wait)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func rateLimitedError(wait time.Duration) error {
+	return apperror.ErrTooManyRequests.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait.Round(time.Second))})
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/proto/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// patterns caches compiled (validate.field).string.pattern expressions.
+var patterns sync.Map
+
+// ValidationUnaryInterceptor checks requests against the (validate.field)
+// annotations in the protos and rejects them with InvalidArgument, listing
+// every violated field in an errdetails.BadRequest.
+func ValidationUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	violations := validateMessage(msg.ProtoReflect(), "")
+	if len(violations) == 0 {
+		return handler(ctx, req)
+	}
+
+	return nil, apperror.ErrInvalidRequest.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+}
+
+func validateMessage(msg protoreflect.Message, prefix string) []*errdetails.BadRequest_FieldViolation {
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	fields := msg.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := prefix + string(field.Name())
+		rules, _ := proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+
+		switch {
+		case field.IsList():
+			list := msg.Get(field).List()
+			if rules.GetRequired() && list.Len() == 0 {
+				violations = append(violations, violation(name, "is required"))
+			}
+			for j := 0; j < list.Len(); j++ {
+				violations = append(violations, validateValue(field, list.Get(j), rules, fmt.Sprintf("%s[%d]", name, j))...)
+			}
+		case field.IsMap():
+			// no constraints on maps yet
+		default:
+			if rules.GetRequired() && !msg.Has(field) {
+				violations = append(violations, violation(name, "is required"))
+				continue
+			}
+			if msg.Has(field) {
+				violations = append(violations, validateValue(field, msg.Get(field), rules, name)...)
+			}
+		}
+	}
+	return violations
+}
+
+func validateValue(field protoreflect.FieldDescriptor, value protoreflect.Value, rules *validate.FieldRules, name string) []*errdetails.BadRequest_FieldViolation {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return validateMessage(value.Message(), name+".")
+	case protoreflect.StringKind:
+		if stringRules := rules.GetString_(); stringRules != nil {
+			return validateString(value.String(), stringRules, name)
+		}
+	}
+	return nil
+}
+
+func validateString(value string, rules *validate.StringRules, name string) []*errdetails.BadRequest_FieldViolation {
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	length := uint64(utf8.RuneCountInString(value))
+
+	if rules.MinLen != nil && length < rules.GetMinLen() {
+		violations = append(violations, violation(name, fmt.Sprintf("must be at least %d characters", rules.GetMinLen())))
+	}
+	if rules.MaxLen != nil && length > rules.GetMaxLen() {
+		violations = append(violations, violation(name, fmt.Sprintf("must be at most %d characters", rules.GetMaxLen())))
+	}
+	if rules.Pattern != nil {
+		pattern, err := compilePattern(rules.GetPattern())
+		if err != nil || !pattern.MatchString(value) {
+			violations = append(violations, violation(name, "has an invalid format"))
+		}
+	}
+	if rules.GetEmail() && !isEmail(value) {
+		violations = append(violations, violation(name, "must be a valid email address"))
+	}
+	if rules.GetUri() && !isHTTPURL(value) {
+		violations = append(violations, violation(name, "must be an absolute http or https URL"))
+	}
+	if len(rules.GetIn()) > 0 && !contains(rules.GetIn(), value) {
+		violations = append(violations, violation(name, fmt.Sprintf("must be one of %s", strings.Join(rules.GetIn(), ", "))))
+	}
+	return violations
+}
+
+func compilePattern(expr string) (*regexp.Regexp, error) {
+	if cached, ok := patterns.Load(expr); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(expr, pattern)
+	return pattern, nil
+}
+
+// isEmail accepts a bare address only, "Name <name@example.com>" is rejected.
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value && strings.Contains(value[strings.LastIndex(value, "@"):], ".")
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func violation(field string, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
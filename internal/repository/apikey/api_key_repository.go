@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(key *entities.APIKey, limit int) error
+	FindAPIKeys(userID ksuid.KSUID) ([]entities.APIKey, error)
+	FindAPIKeyByPrefix(prefix string) (*entities.APIKey, error)
+	FindUserByID(id ksuid.KSUID) (*entities.User, error)
+	DeleteAPIKey(userID ksuid.KSUID, keyID ksuid.KSUID) error
+	TouchAPIKey(keyID ksuid.KSUID, usedAt time.Time, ip string) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// CreateAPIKey saves the key unless the user already has limit of them,
+// 0 is unlimited. The user row is locked so concurrent requests cannot go
+// over the limit.
+func (repo *apiKeyRepository) CreateAPIKey(key *entities.APIKey, limit int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var user entities.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", key.UserID).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.ErrUserNotFound
+		}
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error locking user: %w", err))
+		}
+
+		var count int64
+		if err := tx.Model(&entities.APIKey{}).Where("user_id = ?", key.UserID).Count(&count).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error counting api keys: %w", err))
+		}
+		if limit > 0 && count >= int64(limit) {
+			return apperror.ErrAPIKeyLimitReached.WithMetadata("limit", fmt.Sprint(limit))
+		}
+
+		if err := tx.Create(key).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving api key: %w", err))
+		}
+		return nil
+	})
+}
+
+func (repo *apiKeyRepository) FindAPIKeys(userID ksuid.KSUID) ([]entities.APIKey, error) {
+	var keys []entities.APIKey
+	err := repo.db.Where("user_id = ?", userID).Order("created_at").Find(&keys).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing api keys: %w", err))
+	}
+	return keys, nil
+}
+
+func (repo *apiKeyRepository) FindAPIKeyByPrefix(prefix string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := repo.db.Where("prefix = ?", prefix).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error finding api key: %w", err))
+	}
+	return &key, nil
+}
+
+func (repo *apiKeyRepository) FindUserByID(id ksuid.KSUID) (*entities.User, error) {
+	var user entities.User
+	err := repo.db.Where("id = ?", id).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &user, nil
+}
+
+func (repo *apiKeyRepository) DeleteAPIKey(userID ksuid.KSUID, keyID ksuid.KSUID) error {
+	result := repo.db.Where("id = ? AND user_id = ?", keyID, userID).Delete(&entities.APIKey{})
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error deleting api key: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (repo *apiKeyRepository) TouchAPIKey(keyID ksuid.KSUID, usedAt time.Time, ip string) error {
+	err := repo.db.Model(&entities.APIKey{}).Where("id = ?", keyID).Updates(map[string]interface{}{
+		"last_used_at": usedAt,
+		"last_used_ip": ip,
+	}).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error updating api key: %w", err))
+	}
+	return nil
+}
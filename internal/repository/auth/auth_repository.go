@@ -1,23 +1,61 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/oriastanjung/stellar/internal/apperror"
 	"github.com/oriastanjung/stellar/internal/entities"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/segmentio/ksuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// adminBootstrapLockID is the advisory lock serializing RegisterFirstAdmin.
+const adminBootstrapLockID = 7_270_143_002
+
 type AuthRepository interface {
 	RegisterAdmin(user *entities.User) error
+	RegisterFirstAdmin(user *entities.User) error
+	RegisterAdminWithInvitation(user *entities.User, tokenHash string) error
+	CreateAdminInvitation(invitation *entities.AdminInvitation, email *entities.OutboxEmail) error
+	FindAdminInvitations() ([]entities.AdminInvitation, error)
+	DeleteAdminInvitation(id ksuid.KSUID) error
 	LoginAdmin(user *entities.User) error
 	RegisterUser(user *entities.User) error
+	RegisterUserWithToken(user *entities.User, token *entities.UserToken, email *entities.OutboxEmail) error
 	LoginUser(user *entities.User) error
-	VerifyUser(token string) error
+	VerifyUser(tokenHash string) error
 	FindUserByEmail(email string) (*entities.User, error)
 	UpdateUserByEmail(Email string, dataUpdated *entities.User) error
 	FindOneUserByKey(key string, val string) (*entities.User, error)
+	FindUserByID(id ksuid.KSUID) (*entities.User, error)
+	UpdateUserMFA(user *entities.User) error
+	UseTOTPStep(userID ksuid.KSUID, step int64) error
+	UpdatePreferredLocale(userID ksuid.KSUID, locale string) error
+	SetUserVerified(email string) error
+	SetUserDisabled(email string, disabledAt *time.Time) error
+	UpdateSubscriptionStatus(userID ksuid.KSUID, active bool) (bool, error)
+	RecordUserDevice(device *entities.UserDevice) (bool, error)
+	CountActiveDevices(since time.Time) (int64, error)
+	ReplaceRecoveryCodes(userID ksuid.KSUID, codes []entities.RecoveryCode) error
+	UseRecoveryCode(userID ksuid.KSUID, codeHash string) error
+	CreateOAuthState(state *entities.OAuthState) error
+	ConsumeOAuthState(stateHash string) (*entities.OAuthState, error)
+	RegisterUserWithIdentity(user *entities.User, identity *entities.UserIdentity) error
+	FindUserIdentity(provider string, subject string) (*entities.UserIdentity, error)
+	CreateUserIdentity(identity *entities.UserIdentity) error
+	ListUserIdentities(userID ksuid.KSUID) ([]entities.UserIdentity, error)
+	DeleteUserIdentity(userID ksuid.KSUID, provider string) error
+	RegisterFailedLogin(userID ksuid.KSUID) (int, error)
+	ResetFailedLogins(userID ksuid.KSUID) error
+	LockUser(userID ksuid.KSUID, until time.Time, token *entities.UserToken, email *entities.OutboxEmail) error
+	UnlockUserByToken(tokenHash string) error
+	CreateUserToken(token *entities.UserToken, email *entities.OutboxEmail) error
+	FindLatestUserToken(userID ksuid.KSUID, purpose entities.TokenPurpose) (*entities.UserToken, error)
+	ResetPasswordByToken(tokenHash string, hashedPassword string) error
+	FindUserByToken(purpose entities.TokenPurpose, tokenHash string) (*entities.User, error)
 }
 
 type authRepository struct {
@@ -31,43 +69,130 @@ func NewAuthRepository(db *gorm.DB) AuthRepository {
 }
 
 func (repo *authRepository) RegisterAdmin(user *entities.User) error {
-	return repo.db.Create(user).Error
+	return createUserError(repo.db.Create(user).Error)
 }
 
-func (repo *authRepository) LoginAdmin(user *entities.User) error {
-	return repo.db.Where("email = ?", user.Email).First(user).Error
+// RegisterFirstAdmin saves the admin only while no admin exists, the lock
+// keeps two bootstrap requests from both passing the check.
+func (repo *authRepository) RegisterFirstAdmin(user *entities.User) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// SQLite runs one transaction at a time, only Postgres needs the lock
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", adminBootstrapLockID).Error; err != nil {
+				return apperror.Internal(fmt.Errorf("error locking admin bootstrap: %w", err))
+			}
+		}
+		var admins int64
+		if err := tx.Model(&entities.User{}).Where("role = ?", entities.AdminRole).Count(&admins).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error counting admins: %w", err))
+		}
+		if admins > 0 {
+			return apperror.ErrAdminBootstrapClosed
+		}
+		return createUserError(tx.Create(user).Error)
+	})
 }
 
-func (repo *authRepository) RegisterUser(user *entities.User) error {
-	return repo.db.Create(user).Error
+// RegisterAdminWithInvitation accepts the invitation sent to the email of
+// the user and saves the admin in one transaction, the conditional update
+// lets an invitation be used once.
+func (repo *authRepository) RegisterAdminWithInvitation(user *entities.User, tokenHash string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entities.AdminInvitation{}).
+			Where("token_hash = ? AND email = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, user.Email, now).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return apperror.Internal(fmt.Errorf("error saving admin invitation: %w", result.Error))
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrInvalidInvitation
+		}
+		return createUserError(tx.Create(user).Error)
+	})
 }
 
-func (repo *authRepository) LoginUser(user *entities.User) error {
-	return repo.db.Where("email = ?", user.Email).First(user).Error
+// CreateAdminInvitation replaces pending invitations of the same email and
+// saves the email carrying the link in the same transaction.
+func (repo *authRepository) CreateAdminInvitation(invitation *entities.AdminInvitation, email *entities.OutboxEmail) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("email = ? AND accepted_at IS NULL", invitation.Email).Delete(&entities.AdminInvitation{}).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error deleting admin invitations: %w", err))
+		}
+		if err := tx.Create(invitation).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving admin invitation: %w", err))
+		}
+		if err := tx.Create(email).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving outbox email: %w", err))
+		}
+		return nil
+	})
 }
 
-func (repo *authRepository) VerifyUser(token string) error {
-	var user entities.User
-	err := repo.db.Where("verification_token = ?", token).First(&user).Error
+// FindAdminInvitations returns the invitations that can still be accepted.
+func (repo *authRepository) FindAdminInvitations() ([]entities.AdminInvitation, error) {
+	var invitations []entities.AdminInvitation
+	err := repo.db.Where("accepted_at IS NULL AND expires_at > ?", time.Now()).Order("created_at").Find(&invitations).Error
 	if err != nil {
-		return status.Errorf(codes.NotFound, "User Not Found")
+		return nil, apperror.Internal(fmt.Errorf("error listing admin invitations: %w", err))
 	}
-	user.IsVerified = true
-	user.VerificationToken = ""
-	err = repo.db.Save(&user).Error
+	return invitations, nil
+}
 
-	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error saving user: %v", err))
+func (repo *authRepository) DeleteAdminInvitation(id ksuid.KSUID) error {
+	result := repo.db.Where("id = ? AND accepted_at IS NULL", id).Delete(&entities.AdminInvitation{})
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error deleting admin invitation: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrInvitationNotFound
 	}
-
 	return nil
 }
 
+func (repo *authRepository) LoginAdmin(user *entities.User) error {
+	return notFoundOr(repo.db.Where("email = ?", user.Email).First(user).Error, apperror.ErrUserNotFound)
+}
+
+func (repo *authRepository) RegisterUser(user *entities.User) error {
+	return createUserError(repo.db.Create(user).Error)
+}
+
+// RegisterUserWithToken saves a new user with its verification token and
+// email, so a failed registration never leaves an email in the outbox.
+func (repo *authRepository) RegisterUserWithToken(user *entities.User, token *entities.UserToken, email *entities.OutboxEmail) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return createUserError(err)
+		}
+		return createUserToken(tx, token, email)
+	})
+}
+
+func (repo *authRepository) LoginUser(user *entities.User) error {
+	return notFoundOr(repo.db.Where("email = ?", user.Email).First(user).Error, apperror.ErrUserNotFound)
+}
+
+func (repo *authRepository) VerifyUser(tokenHash string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(tx, entities.TokenPurposeVerification, tokenHash)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entities.User{}).Where("id = ?", token.UserID).Update("is_verified", true).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+		}
+		return nil
+	})
+}
+
 func (repo *authRepository) FindUserByEmail(email string) (*entities.User, error) {
 	var user entities.User
 	err := repo.db.Where("email = ?", email).First(&user).Error
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "User Not Found")
+		return nil, notFoundOr(err, apperror.ErrUserNotFound)
 	}
 	return &user, nil
 
@@ -76,20 +201,21 @@ func (repo *authRepository) UpdateUserByEmail(Email string, dataUpdated *entitie
 	var user entities.User
 	err := repo.db.Where("email = ?", Email).First(&user).Error
 	if err != nil {
-		return status.Errorf(codes.NotFound, "User Not Found")
+		return notFoundOr(err, apperror.ErrUserNotFound)
 	}
 
 	user.Username = dataUpdated.Username
 	user.Password = dataUpdated.Password
-	user.ForgetPasswordToken = dataUpdated.ForgetPasswordToken
 	user.ProfilePicture = dataUpdated.ProfilePicture
 	user.ProfilePictureUrl = dataUpdated.ProfilePictureUrl
 	user.Username = dataUpdated.Username
+	user.IsVerified = dataUpdated.IsVerified
+	user.NoPassword = dataUpdated.NoPassword
 
 	err = repo.db.Save(&user).Error
 
 	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error saving user: %v", err))
+		return apperror.Internal(fmt.Errorf("error saving user: %w", err))
 	}
 
 	return nil
@@ -99,8 +225,404 @@ func (repo *authRepository) FindOneUserByKey(key string, val string) (*entities.
 	var user entities.User
 	err := repo.db.Where(fmt.Sprintf("%s = ?", key), val).First(&user).Error
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "User Not Found")
+		return nil, notFoundOr(err, apperror.ErrUserNotFound)
+	}
+	return &user, nil
+
+}
+
+func (repo *authRepository) FindUserByID(id ksuid.KSUID) (*entities.User, error) {
+	var user entities.User
+	err := repo.db.Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, notFoundOr(err, apperror.ErrUserNotFound)
 	}
 	return &user, nil
+}
+
+func (repo *authRepository) UpdateUserMFA(user *entities.User) error {
+	err := repo.db.Model(&entities.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"totp_secret":         user.TOTPSecret,
+		"totp_enabled":        user.TOTPEnabled,
+		"totp_last_used_step": user.TOTPLastUsedStep,
+	}).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+	}
+	return nil
+}
+
+// UseTOTPStep records step as the last used one unless it (or a later
+// one) was already used, so concurrent requests cannot both accept a code.
+func (repo *authRepository) UseTOTPStep(userID ksuid.KSUID, step int64) error {
+	result := repo.db.Model(&entities.User{}).
+		Where("id = ? AND totp_last_used_step < ?", userID, step).
+		Update("totp_last_used_step", step)
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error saving user: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (repo *authRepository) UpdatePreferredLocale(userID ksuid.KSUID, locale string) error {
+	result := repo.db.Model(&entities.User{}).Where("id = ?", userID).Update("preferred_locale", locale)
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error saving user: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrUserNotFound
+	}
+	return nil
+}
+
+// SetUserVerified verifies the account without a token.
+func (repo *authRepository) SetUserVerified(email string) error {
+	return repo.updateUserByEmail(email, "is_verified", true)
+}
+
+// SetUserDisabled disables the account, nil enables it again.
+func (repo *authRepository) SetUserDisabled(email string, disabledAt *time.Time) error {
+	return repo.updateUserByEmail(email, "disabled_at", disabledAt)
+}
+
+func (repo *authRepository) updateUserByEmail(email string, column string, value interface{}) error {
+	result := repo.db.Model(&entities.User{}).Where("email = ?", email).Update(column, value)
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error saving user: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateSubscriptionStatus reports whether the status actually changed.
+func (repo *authRepository) UpdateSubscriptionStatus(userID ksuid.KSUID, active bool) (bool, error) {
+	result := repo.db.Model(&entities.User{}).
+		Where("id = ? AND subscription_status <> ?", userID, active).
+		Update("subscription_status", active)
+	if result.Error != nil {
+		return false, apperror.Internal(fmt.Errorf("error saving user: %w", result.Error))
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecordUserDevice saves a device or refreshes its last use. It reports
+// whether the device is new to an account that already had devices, the
+// first device of an account is trusted.
+func (repo *authRepository) RecordUserDevice(device *entities.UserDevice) (bool, error) {
+	newDevice := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.UserDevice{}).
+			Where("user_id = ? AND fingerprint = ?", device.UserID, device.Fingerprint).
+			Updates(map[string]interface{}{
+				"last_seen_at": device.LastSeenAt,
+				"ip_address":   device.IPAddress,
+			})
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+
+		var known int64
+		if err := tx.Model(&entities.UserDevice{}).Where("user_id = ?", device.UserID).Count(&known).Error; err != nil {
+			return err
+		}
+		// login bersamaan dari device yang sama cukup tercatat sekali
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(device)
+		if result.Error != nil {
+			return result.Error
+		}
+		newDevice = known > 0 && result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, apperror.Internal(fmt.Errorf("error saving user device: %w", err))
+	}
+	return newDevice, nil
+}
+
+// CountActiveDevices counts the devices that logged in since then.
+func (repo *authRepository) CountActiveDevices(since time.Time) (int64, error) {
+	var count int64
+	if err := repo.db.Model(&entities.UserDevice{}).Where("last_seen_at >= ?", since).Count(&count).Error; err != nil {
+		return 0, apperror.Internal(fmt.Errorf("error counting user devices: %w", err))
+	}
+	return count, nil
+}
+
+func (repo *authRepository) ReplaceRecoveryCodes(userID ksuid.KSUID, recoveryCodes []entities.RecoveryCode) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error deleting recovery codes: %w", err))
+		}
+		if len(recoveryCodes) == 0 {
+			return nil
+		}
+		if err := tx.Create(&recoveryCodes).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving recovery codes: %w", err))
+		}
+		return nil
+	})
+}
+
+func (repo *authRepository) UseRecoveryCode(userID ksuid.KSUID, codeHash string) error {
+	// the used_at filter makes concurrent use of the same code fail for all but one caller
+	result := repo.db.Model(&entities.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error using recovery code: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrRecoveryCodeNotFound
+	}
+	return nil
+}
+
+func (repo *authRepository) CreateOAuthState(state *entities.OAuthState) error {
+	// abandoned authorization requests are cleaned up as new ones start
+	if err := repo.db.Where("expires_at < ?", time.Now()).Delete(&entities.OAuthState{}).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error deleting oauth states: %w", err))
+	}
+	if err := repo.db.Create(state).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error saving oauth state: %w", err))
+	}
+	return nil
+}
+
+func (repo *authRepository) ConsumeOAuthState(stateHash string) (*entities.OAuthState, error) {
+	var state entities.OAuthState
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+			return err
+		}
+		// deleting makes the state single-use, a concurrent callback deletes nothing
+		result := tx.Where("state_hash = ?", stateHash).Delete(&entities.OAuthState{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, notFoundOr(err, apperror.ErrInvalidOAuthState)
+	}
+	if time.Now().After(state.ExpiresAt) {
+		return nil, apperror.ErrInvalidOAuthState
+	}
+	return &state, nil
+}
+
+func (repo *authRepository) RegisterUserWithIdentity(user *entities.User, identity *entities.UserIdentity) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return createUserError(err)
+		}
+		if err := tx.Create(identity).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving identity: %w", err))
+		}
+		return nil
+	})
+}
+
+func (repo *authRepository) FindUserIdentity(provider string, subject string) (*entities.UserIdentity, error) {
+	var identity entities.UserIdentity
+	err := repo.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, notFoundOr(err, apperror.ErrIdentityNotFound)
+	}
+	return &identity, nil
+}
+
+func (repo *authRepository) CreateUserIdentity(identity *entities.UserIdentity) error {
+	if err := repo.db.Create(identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.ErrIdentityAlreadyLinked
+		}
+		return apperror.Internal(fmt.Errorf("error saving identity: %w", err))
+	}
+	return nil
+}
+
+func (repo *authRepository) ListUserIdentities(userID ksuid.KSUID) ([]entities.UserIdentity, error) {
+	var identities []entities.UserIdentity
+	err := repo.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing identities: %w", err))
+	}
+	return identities, nil
+}
+
+func (repo *authRepository) DeleteUserIdentity(userID ksuid.KSUID, provider string) error {
+	result := repo.db.Where("user_id = ? AND provider = ?", userID, provider).Delete(&entities.UserIdentity{})
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error deleting identity: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrIdentityNotFound
+	}
+	return nil
+}
+
+func (repo *authRepository) RegisterFailedLogin(userID ksuid.KSUID) (int, error) {
+	// increment in SQL so concurrent attempts are all counted
+	var user entities.User
+	err := repo.db.Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", userID).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error
+	if err != nil {
+		return 0, apperror.Internal(fmt.Errorf("error saving user: %w", err))
+	}
+	return user.FailedLoginAttempts, nil
+}
+
+func (repo *authRepository) ResetFailedLogins(userID ksuid.KSUID) error {
+	err := repo.db.Model(&entities.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+	}
+	return nil
+}
+
+func (repo *authRepository) LockUser(userID ksuid.KSUID, until time.Time, token *entities.UserToken, email *entities.OutboxEmail) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.User{}).Where("id = ?", userID).Update("locked_until", until).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+		}
+		return createUserToken(tx, token, email)
+	})
+}
+
+func (repo *authRepository) UnlockUserByToken(tokenHash string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(tx, entities.TokenPurposeUnlockAccount, tokenHash)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entities.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+		}
+		return nil
+	})
+}
 
+func (repo *authRepository) ResetPasswordByToken(tokenHash string, hashedPassword string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(tx, entities.TokenPurposeResetPassword, tokenHash)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entities.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"password":    hashedPassword,
+			"no_password": false,
+		}).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error saving user: %w", err))
+		}
+		return nil
+	})
+}
+
+// CreateUserToken saves a token together with the email carrying its link,
+// the outbox worker only sends the email once the transaction commits.
+func (repo *authRepository) CreateUserToken(token *entities.UserToken, email *entities.OutboxEmail) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return createUserToken(tx, token, email)
+	})
+}
+
+func createUserToken(tx *gorm.DB, token *entities.UserToken, email *entities.OutboxEmail) error {
+	// expired tokens are cleaned up as new ones are issued
+	if err := tx.Where("expires_at < ?", time.Now()).Delete(&entities.UserToken{}).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error deleting user tokens: %w", err))
+	}
+	// only the latest link of a purpose stays valid
+	err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+		Delete(&entities.UserToken{}).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error deleting user tokens: %w", err))
+	}
+	if err := tx.Create(token).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error saving user token: %w", err))
+	}
+	if err := tx.Create(email).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error saving outbox email: %w", err))
+	}
+	return nil
+}
+
+func (repo *authRepository) FindLatestUserToken(userID ksuid.KSUID, purpose entities.TokenPurpose) (*entities.UserToken, error) {
+	var token entities.UserToken
+	err := repo.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at desc").First(&token).Error
+	if err != nil {
+		return nil, notFoundOr(err, apperror.ErrInvalidToken)
+	}
+	return &token, nil
+}
+
+// FindUserByToken looks up the owner of a valid token without consuming it.
+func (repo *authRepository) FindUserByToken(purpose entities.TokenPurpose, tokenHash string) (*entities.User, error) {
+	var token entities.UserToken
+	err := repo.db.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return nil, notFoundOr(err, apperror.ErrInvalidToken)
+	}
+	return repo.FindUserByID(token.UserID)
+}
+
+// consumeUserToken marks an unexpired token as used, the conditional update
+// lets only one of several concurrent requests succeed.
+func consumeUserToken(tx *gorm.DB, purpose entities.TokenPurpose, tokenHash string) (*entities.UserToken, error) {
+	now := time.Now()
+	result := tx.Model(&entities.UserToken{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, apperror.Internal(fmt.Errorf("error saving user token: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	var token entities.UserToken
+	if err := tx.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &token, nil
+}
+
+// notFoundOr maps a missing record to notFound and any other failure to an internal error.
+func notFoundOr(err error, notFound *apperror.Error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return apperror.Internal(err)
+}
+
+func createUserError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.ErrUserAlreadyExists
+	}
+	return apperror.Internal(fmt.Errorf("error saving user: %w", err))
 }
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/database/databasetest"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+func newTestUser(t *testing.T, repo AuthRepository, email string, role entities.Role) *entities.User {
+	t.Helper()
+	user, err := entities.NewUser("user-"+email, email, "hashed", role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RegisterUser(user); err != nil {
+		t.Fatalf("RegisterUser(%s): %v", email, err)
+	}
+	return user
+}
+
+func newOutboxEmail(to string) *entities.OutboxEmail {
+	return &entities.OutboxEmail{
+		ID:            ksuid.New(),
+		To:            to,
+		Subject:       "subject",
+		Status:        entities.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func newUserToken(userID ksuid.KSUID, purpose entities.TokenPurpose, hash string, ttl time.Duration) *entities.UserToken {
+	return &entities.UserToken{
+		ID:        ksuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+func wantError(t *testing.T, got error, want error) {
+	t.Helper()
+	if want == nil && got != nil {
+		t.Fatalf("unexpected error: %v", got)
+	}
+	if want != nil && !errors.Is(got, want) {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterFirstAdmin(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		tests := []struct {
+			email string
+			want  error
+		}{
+			{email: "first@example.com"},
+			{email: "second@example.com", want: apperror.ErrAdminBootstrapClosed},
+		}
+		for _, tt := range tests {
+			admin, _ := entities.NewUser("admin", tt.email, "hashed", entities.AdminRole)
+			wantError(t, repo.RegisterFirstAdmin(admin), tt.want)
+		}
+	})
+}
+
+func TestRegisterUserDuplicateEmail(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		newTestUser(t, repo, "taken@example.com", entities.UserRole)
+		duplicate, _ := entities.NewUser("other", "taken@example.com", "hashed", entities.UserRole)
+		wantError(t, repo.RegisterUser(duplicate), apperror.ErrUserAlreadyExists)
+	})
+}
+
+func TestFindUser(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "find@example.com", entities.UserRole)
+
+		found, err := repo.FindUserByEmail(user.Email)
+		wantError(t, err, nil)
+		if found.ID != user.ID {
+			t.Fatalf("FindUserByEmail returned %s, want %s", found.ID, user.ID)
+		}
+		found, err = repo.FindUserByID(user.ID)
+		wantError(t, err, nil)
+		if found.Email != user.Email {
+			t.Fatalf("FindUserByID returned %s, want %s", found.Email, user.Email)
+		}
+
+		_, err = repo.FindUserByEmail("missing@example.com")
+		wantError(t, err, apperror.ErrUserNotFound)
+		_, err = repo.FindUserByID(ksuid.New())
+		wantError(t, err, apperror.ErrUserNotFound)
+	})
+}
+
+// A found user must log in without an error, notFoundOr used to turn the
+// nil error of a successful lookup into an internal error.
+func TestLoginFindsRegisteredUser(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "login@example.com", entities.UserRole)
+		admin := newTestUser(t, repo, "admin@example.com", entities.AdminRole)
+		tests := []struct {
+			name  string
+			login func(*entities.User) error
+			email string
+			want  error
+			id    ksuid.KSUID
+		}{
+			{name: "user", login: repo.LoginUser, email: user.Email, id: user.ID},
+			{name: "admin", login: repo.LoginAdmin, email: admin.Email, id: admin.ID},
+			{name: "unknown user", login: repo.LoginUser, email: "missing@example.com", want: apperror.ErrUserNotFound},
+			{name: "unknown admin", login: repo.LoginAdmin, email: "missing@example.com", want: apperror.ErrUserNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				found := &entities.User{Email: tt.email}
+				wantError(t, tt.login(found), tt.want)
+				if found.ID != tt.id {
+					t.Fatalf("login loaded user %s, want %s", found.ID, tt.id)
+				}
+			})
+		}
+	})
+}
+
+func TestUserTokens(t *testing.T) {
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		consume      int
+		want         error
+		wantVerified bool
+	}{
+		{name: "valid token", ttl: time.Hour, consume: 1, wantVerified: true},
+		{name: "token is single use", ttl: time.Hour, consume: 2, want: apperror.ErrInvalidToken, wantVerified: true},
+		{name: "expired token", ttl: -time.Minute, consume: 1, want: apperror.ErrInvalidToken},
+	}
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		for i, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				user := newTestUser(t, repo, fmt.Sprintf("token%d@example.com", i), entities.UserRole)
+				hash := fmt.Sprintf("hash-%d", i)
+				token := newUserToken(user.ID, entities.TokenPurposeVerification, hash, tt.ttl)
+				wantError(t, repo.CreateUserToken(token, newOutboxEmail(user.Email)), nil)
+
+				var err error
+				for range tt.consume {
+					err = repo.VerifyUser(hash)
+				}
+				wantError(t, err, tt.want)
+
+				verified, _ := repo.FindUserByID(user.ID)
+				if verified.IsVerified != tt.wantVerified {
+					t.Fatalf("IsVerified = %t, want %t", verified.IsVerified, tt.wantVerified)
+				}
+			})
+		}
+	})
+}
+
+func TestCreateUserTokenReplacesUnusedTokens(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "reset@example.com", entities.UserRole)
+		first := newUserToken(user.ID, entities.TokenPurposeResetPassword, "first", time.Hour)
+		second := newUserToken(user.ID, entities.TokenPurposeResetPassword, "second", time.Hour)
+		wantError(t, repo.CreateUserToken(first, newOutboxEmail(user.Email)), nil)
+		wantError(t, repo.CreateUserToken(second, newOutboxEmail(user.Email)), nil)
+
+		latest, err := repo.FindLatestUserToken(user.ID, entities.TokenPurposeResetPassword)
+		wantError(t, err, nil)
+		if latest.TokenHash != "second" {
+			t.Fatalf("FindLatestUserToken returned %s, want second", latest.TokenHash)
+		}
+		wantError(t, repo.ResetPasswordByToken("first", "new-hash"), apperror.ErrInvalidToken)
+		wantError(t, repo.ResetPasswordByToken("second", "new-hash"), nil)
+
+		updated, _ := repo.FindUserByID(user.ID)
+		if updated.Password != "new-hash" {
+			t.Fatalf("Password = %q, want new-hash", updated.Password)
+		}
+		var emails int64
+		db.Model(&entities.OutboxEmail{}).Where("\"to\" = ?", user.Email).Count(&emails)
+		if emails != 2 {
+			t.Fatalf("outbox has %d emails, want 2", emails)
+		}
+	})
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "totp@example.com", entities.UserRole)
+		tests := []struct {
+			step int64
+			want error
+		}{
+			{step: 100},
+			{step: 100, want: apperror.ErrInvalidMFACode},
+			{step: 99, want: apperror.ErrInvalidMFACode},
+			{step: 101},
+		}
+		for _, tt := range tests {
+			wantError(t, repo.UseTOTPStep(user.ID, tt.step), tt.want)
+		}
+		updated, _ := repo.FindUserByID(user.ID)
+		if updated.TOTPLastUsedStep != 101 {
+			t.Fatalf("TOTPLastUsedStep = %d, want 101", updated.TOTPLastUsedStep)
+		}
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "recovery@example.com", entities.UserRole)
+		codes := []entities.RecoveryCode{
+			{ID: ksuid.New(), UserID: user.ID, CodeHash: "code-1"},
+			{ID: ksuid.New(), UserID: user.ID, CodeHash: "code-2"},
+		}
+		wantError(t, repo.ReplaceRecoveryCodes(user.ID, codes), nil)
+
+		wantError(t, repo.UseRecoveryCode(user.ID, "code-1"), nil)
+		wantError(t, repo.UseRecoveryCode(user.ID, "code-1"), apperror.ErrRecoveryCodeNotFound)
+		wantError(t, repo.UseRecoveryCode(ksuid.New(), "code-2"), apperror.ErrRecoveryCodeNotFound)
+
+		wantError(t, repo.ReplaceRecoveryCodes(user.ID, nil), nil)
+		wantError(t, repo.UseRecoveryCode(user.ID, "code-2"), apperror.ErrRecoveryCodeNotFound)
+	})
+}
+
+func TestFailedLoginsAndLock(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "lock@example.com", entities.UserRole)
+		for want := 1; want <= 3; want++ {
+			attempts, err := repo.RegisterFailedLogin(user.ID)
+			wantError(t, err, nil)
+			if attempts != want {
+				t.Fatalf("RegisterFailedLogin = %d, want %d", attempts, want)
+			}
+		}
+
+		until := time.Now().Add(time.Hour)
+		token := newUserToken(user.ID, entities.TokenPurposeUnlockAccount, "unlock", time.Hour)
+		wantError(t, repo.LockUser(user.ID, until, token, newOutboxEmail(user.Email)), nil)
+		locked, _ := repo.FindUserByID(user.ID)
+		if locked.LockedUntil == nil {
+			t.Fatal("LockedUntil is not set after LockUser")
+		}
+
+		wantError(t, repo.UnlockUserByToken("unlock"), nil)
+		unlocked, _ := repo.FindUserByID(user.ID)
+		if unlocked.LockedUntil != nil || unlocked.FailedLoginAttempts != 0 {
+			t.Fatalf("after unlock LockedUntil = %v, FailedLoginAttempts = %d", unlocked.LockedUntil, unlocked.FailedLoginAttempts)
+		}
+		wantError(t, repo.UnlockUserByToken("unlock"), apperror.ErrInvalidToken)
+	})
+}
+
+func TestRecordUserDevice(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "device@example.com", entities.UserRole)
+		tests := []struct {
+			fingerprint string
+			wantNew     bool
+		}{
+			// the first device of an account is trusted
+			{fingerprint: "laptop", wantNew: false},
+			{fingerprint: "laptop", wantNew: false},
+			{fingerprint: "phone", wantNew: true},
+		}
+		for _, tt := range tests {
+			device := &entities.UserDevice{ID: ksuid.New(), UserID: user.ID, Fingerprint: tt.fingerprint, LastSeenAt: time.Now()}
+			isNew, err := repo.RecordUserDevice(device)
+			wantError(t, err, nil)
+			if isNew != tt.wantNew {
+				t.Fatalf("RecordUserDevice(%s) = %t, want %t", tt.fingerprint, isNew, tt.wantNew)
+			}
+		}
+
+		active, err := repo.CountActiveDevices(time.Now().Add(-time.Minute))
+		wantError(t, err, nil)
+		if active != 2 {
+			t.Fatalf("CountActiveDevices = %d, want 2", active)
+		}
+	})
+}
+
+func TestOAuthStateIsSingleUse(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		states := []*entities.OAuthState{
+			{StateHash: "valid", Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)},
+			{StateHash: "expired", Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now().Add(-time.Second)},
+		}
+		for _, state := range states {
+			wantError(t, repo.CreateOAuthState(state), nil)
+		}
+
+		state, err := repo.ConsumeOAuthState("valid")
+		wantError(t, err, nil)
+		if state.CodeVerifier != "verifier" {
+			t.Fatalf("CodeVerifier = %q, want verifier", state.CodeVerifier)
+		}
+		_, err = repo.ConsumeOAuthState("valid")
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+		_, err = repo.ConsumeOAuthState("expired")
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+	})
+}
+
+func TestUserIdentities(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "identity@example.com", entities.UserRole)
+		other := newTestUser(t, repo, "other@example.com", entities.UserRole)
+
+		identity := &entities.UserIdentity{ID: ksuid.New(), UserID: user.ID, Provider: "github", Subject: "42"}
+		wantError(t, repo.CreateUserIdentity(identity), nil)
+		taken := &entities.UserIdentity{ID: ksuid.New(), UserID: other.ID, Provider: "github", Subject: "42"}
+		wantError(t, repo.CreateUserIdentity(taken), apperror.ErrIdentityAlreadyLinked)
+
+		found, err := repo.FindUserIdentity("github", "42")
+		wantError(t, err, nil)
+		if found.UserID != user.ID {
+			t.Fatalf("FindUserIdentity returned user %s, want %s", found.UserID, user.ID)
+		}
+		wantError(t, repo.DeleteUserIdentity(user.ID, "github"), nil)
+		wantError(t, repo.DeleteUserIdentity(user.ID, "github"), apperror.ErrIdentityNotFound)
+		_, err = repo.FindUserIdentity("github", "42")
+		wantError(t, err, apperror.ErrIdentityNotFound)
+	})
+}
+
+func TestUpdateSubscriptionStatus(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAuthRepository(db)
+		user := newTestUser(t, repo, "subscription@example.com", entities.UserRole)
+		tests := []struct {
+			active      bool
+			wantChanged bool
+		}{
+			{active: true, wantChanged: true},
+			{active: true, wantChanged: false},
+			{active: false, wantChanged: true},
+		}
+		for _, tt := range tests {
+			changed, err := repo.UpdateSubscriptionStatus(user.ID, tt.active)
+			wantError(t, err, nil)
+			if changed != tt.wantChanged {
+				t.Fatalf("UpdateSubscriptionStatus(%t) = %t, want %t", tt.active, changed, tt.wantChanged)
+			}
+		}
+	})
+}
@@ -0,0 +1,603 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+// MemoryAuthRepository keeps users, tokens and the outbox in memory with
+// the same rules as the gorm repository (unique emails, single-use tokens,
+// only the latest token of a purpose valid), for tests of the auth usecase
+// without Postgres.
+type MemoryAuthRepository struct {
+	mu            sync.Mutex
+	clock         utils.Clock
+	users         map[ksuid.KSUID]entities.User
+	tokens        map[string]entities.UserToken
+	invitations   map[ksuid.KSUID]entities.AdminInvitation
+	devices       []entities.UserDevice
+	recoveryCodes []entities.RecoveryCode
+	oauthStates   map[string]entities.OAuthState
+	identities    []entities.UserIdentity
+	outbox        []entities.OutboxEmail
+}
+
+// NewMemoryAuthRepository checks expiry and stamps CreatedAt with clock.
+func NewMemoryAuthRepository(clock utils.Clock) *MemoryAuthRepository {
+	return &MemoryAuthRepository{
+		clock:       clock,
+		users:       map[ksuid.KSUID]entities.User{},
+		tokens:      map[string]entities.UserToken{},
+		invitations: map[ksuid.KSUID]entities.AdminInvitation{},
+		oauthStates: map[string]entities.OAuthState{},
+	}
+}
+
+// Outbox returns the emails saved so far, oldest first.
+func (repo *MemoryAuthRepository) Outbox() []entities.OutboxEmail {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return append([]entities.OutboxEmail(nil), repo.outbox...)
+}
+
+func (repo *MemoryAuthRepository) RegisterAdmin(user *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.createUser(user)
+}
+
+func (repo *MemoryAuthRepository) RegisterFirstAdmin(user *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, existing := range repo.users {
+		if existing.Role == string(entities.AdminRole) {
+			return apperror.ErrAdminBootstrapClosed
+		}
+	}
+	return repo.createUser(user)
+}
+
+func (repo *MemoryAuthRepository) RegisterAdminWithInvitation(user *entities.User, tokenHash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	now := repo.clock.Now()
+	for id, invitation := range repo.invitations {
+		if invitation.TokenHash != tokenHash || invitation.Email != user.Email || invitation.AcceptedAt != nil || !invitation.ExpiresAt.After(now) {
+			continue
+		}
+		if err := repo.createUser(user); err != nil {
+			return err
+		}
+		invitation.AcceptedAt = &now
+		repo.invitations[id] = invitation
+		return nil
+	}
+	return apperror.ErrInvalidInvitation
+}
+
+func (repo *MemoryAuthRepository) CreateAdminInvitation(invitation *entities.AdminInvitation, email *entities.OutboxEmail) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for id, pending := range repo.invitations {
+		if pending.Email == invitation.Email && pending.AcceptedAt == nil {
+			delete(repo.invitations, id)
+		}
+	}
+	invitation.CreatedAt = repo.clock.Now()
+	repo.invitations[invitation.ID] = *invitation
+	repo.enqueue(email)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) FindAdminInvitations() ([]entities.AdminInvitation, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	now := repo.clock.Now()
+	var invitations []entities.AdminInvitation
+	for _, invitation := range repo.invitations {
+		if invitation.AcceptedAt == nil && invitation.ExpiresAt.After(now) {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool { return invitations[i].CreatedAt.Before(invitations[j].CreatedAt) })
+	return invitations, nil
+}
+
+func (repo *MemoryAuthRepository) DeleteAdminInvitation(id ksuid.KSUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	invitation, ok := repo.invitations[id]
+	if !ok || invitation.AcceptedAt != nil {
+		return apperror.ErrInvitationNotFound
+	}
+	delete(repo.invitations, id)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) LoginAdmin(user *entities.User) error {
+	return repo.loadByEmail(user)
+}
+
+func (repo *MemoryAuthRepository) RegisterUser(user *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.createUser(user)
+}
+
+func (repo *MemoryAuthRepository) RegisterUserWithToken(user *entities.User, token *entities.UserToken, email *entities.OutboxEmail) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.createUser(user); err != nil {
+		return err
+	}
+	repo.createUserToken(token, email)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) LoginUser(user *entities.User) error {
+	return repo.loadByEmail(user)
+}
+
+func (repo *MemoryAuthRepository) VerifyUser(tokenHash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	token, err := repo.consumeUserToken(entities.TokenPurposeVerification, tokenHash)
+	if err != nil {
+		return err
+	}
+	repo.updateUser(token.UserID, func(user *entities.User) { user.IsVerified = true })
+	return nil
+}
+
+func (repo *MemoryAuthRepository) FindUserByEmail(email string) (*entities.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.userByEmail(email)
+	if !ok {
+		return nil, apperror.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (repo *MemoryAuthRepository) UpdateUserByEmail(email string, dataUpdated *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.userByEmail(email)
+	if !ok {
+		return apperror.ErrUserNotFound
+	}
+	user.Username = dataUpdated.Username
+	user.Password = dataUpdated.Password
+	user.ProfilePicture = dataUpdated.ProfilePicture
+	user.ProfilePictureUrl = dataUpdated.ProfilePictureUrl
+	user.IsVerified = dataUpdated.IsVerified
+	user.NoPassword = dataUpdated.NoPassword
+	repo.users[user.ID] = user
+	return nil
+}
+
+// FindOneUserByKey supports the columns the usecases look users up by.
+func (repo *MemoryAuthRepository) FindOneUserByKey(key string, val string) (*entities.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, user := range repo.users {
+		var column string
+		switch key {
+		case "id":
+			column = user.ID.String()
+		case "email":
+			column = user.Email
+		case "username":
+			column = user.Username
+		case "subscription_token":
+			column = user.SubscriptionToken
+		default:
+			return nil, apperror.Internal(fmt.Errorf("unsupported user column %q", key))
+		}
+		if column == val {
+			return &user, nil
+		}
+	}
+	return nil, apperror.ErrUserNotFound
+}
+
+func (repo *MemoryAuthRepository) FindUserByID(id ksuid.KSUID) (*entities.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.users[id]
+	if !ok {
+		return nil, apperror.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (repo *MemoryAuthRepository) UpdateUserMFA(updated *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.updateUser(updated.ID, func(user *entities.User) {
+		user.TOTPSecret = updated.TOTPSecret
+		user.TOTPEnabled = updated.TOTPEnabled
+		user.TOTPLastUsedStep = updated.TOTPLastUsedStep
+	})
+	return nil
+}
+
+func (repo *MemoryAuthRepository) UseTOTPStep(userID ksuid.KSUID, step int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.users[userID]
+	if !ok || user.TOTPLastUsedStep >= step {
+		return apperror.ErrInvalidMFACode
+	}
+	user.TOTPLastUsedStep = step
+	repo.users[userID] = user
+	return nil
+}
+
+func (repo *MemoryAuthRepository) UpdatePreferredLocale(userID ksuid.KSUID, locale string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if !repo.updateUser(userID, func(user *entities.User) { user.PreferredLocale = locale }) {
+		return apperror.ErrUserNotFound
+	}
+	return nil
+}
+
+func (repo *MemoryAuthRepository) SetUserVerified(email string) error {
+	return repo.updateUserByEmail(email, func(user *entities.User) { user.IsVerified = true })
+}
+
+func (repo *MemoryAuthRepository) SetUserDisabled(email string, disabledAt *time.Time) error {
+	return repo.updateUserByEmail(email, func(user *entities.User) { user.DisabledAt = disabledAt })
+}
+
+func (repo *MemoryAuthRepository) UpdateSubscriptionStatus(userID ksuid.KSUID, active bool) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.users[userID]
+	if !ok || user.SubscriptionStatus == active {
+		return false, nil
+	}
+	user.SubscriptionStatus = active
+	repo.users[userID] = user
+	return true, nil
+}
+
+func (repo *MemoryAuthRepository) RecordUserDevice(device *entities.UserDevice) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	known := 0
+	for i, saved := range repo.devices {
+		if saved.UserID != device.UserID {
+			continue
+		}
+		if saved.Fingerprint == device.Fingerprint {
+			repo.devices[i].LastSeenAt = device.LastSeenAt
+			repo.devices[i].IPAddress = device.IPAddress
+			return false, nil
+		}
+		known++
+	}
+	device.CreatedAt = repo.clock.Now()
+	repo.devices = append(repo.devices, *device)
+	return known > 0, nil
+}
+
+func (repo *MemoryAuthRepository) CountActiveDevices(since time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var count int64
+	for _, device := range repo.devices {
+		if !device.LastSeenAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *MemoryAuthRepository) ReplaceRecoveryCodes(userID ksuid.KSUID, codes []entities.RecoveryCode) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	kept := repo.recoveryCodes[:0]
+	for _, code := range repo.recoveryCodes {
+		if code.UserID != userID {
+			kept = append(kept, code)
+		}
+	}
+	repo.recoveryCodes = append(kept, codes...)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) UseRecoveryCode(userID ksuid.KSUID, codeHash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, code := range repo.recoveryCodes {
+		if code.UserID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+			now := repo.clock.Now()
+			repo.recoveryCodes[i].UsedAt = &now
+			return nil
+		}
+	}
+	return apperror.ErrRecoveryCodeNotFound
+}
+
+func (repo *MemoryAuthRepository) CreateOAuthState(state *entities.OAuthState) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	now := repo.clock.Now()
+	for hash, saved := range repo.oauthStates {
+		if saved.ExpiresAt.Before(now) {
+			delete(repo.oauthStates, hash)
+		}
+	}
+	state.CreatedAt = now
+	repo.oauthStates[state.StateHash] = *state
+	return nil
+}
+
+func (repo *MemoryAuthRepository) ConsumeOAuthState(stateHash string) (*entities.OAuthState, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	state, ok := repo.oauthStates[stateHash]
+	if !ok {
+		return nil, apperror.ErrInvalidOAuthState
+	}
+	delete(repo.oauthStates, stateHash)
+	if repo.clock.Now().After(state.ExpiresAt) {
+		return nil, apperror.ErrInvalidOAuthState
+	}
+	return &state, nil
+}
+
+func (repo *MemoryAuthRepository) RegisterUserWithIdentity(user *entities.User, identity *entities.UserIdentity) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if err := repo.createUser(user); err != nil {
+		return err
+	}
+	return repo.createIdentity(identity)
+}
+
+func (repo *MemoryAuthRepository) FindUserIdentity(provider string, subject string) (*entities.UserIdentity, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, identity := range repo.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, apperror.ErrIdentityNotFound
+}
+
+func (repo *MemoryAuthRepository) CreateUserIdentity(identity *entities.UserIdentity) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	return repo.createIdentity(identity)
+}
+
+func (repo *MemoryAuthRepository) ListUserIdentities(userID ksuid.KSUID) ([]entities.UserIdentity, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var identities []entities.UserIdentity
+	for _, identity := range repo.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func (repo *MemoryAuthRepository) DeleteUserIdentity(userID ksuid.KSUID, provider string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, identity := range repo.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			repo.identities = append(repo.identities[:i], repo.identities[i+1:]...)
+			return nil
+		}
+	}
+	return apperror.ErrIdentityNotFound
+}
+
+func (repo *MemoryAuthRepository) RegisterFailedLogin(userID ksuid.KSUID) (int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	attempts := 0
+	repo.updateUser(userID, func(user *entities.User) {
+		user.FailedLoginAttempts++
+		attempts = user.FailedLoginAttempts
+	})
+	return attempts, nil
+}
+
+func (repo *MemoryAuthRepository) ResetFailedLogins(userID ksuid.KSUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.updateUser(userID, func(user *entities.User) {
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+	})
+	return nil
+}
+
+func (repo *MemoryAuthRepository) LockUser(userID ksuid.KSUID, until time.Time, token *entities.UserToken, email *entities.OutboxEmail) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.updateUser(userID, func(user *entities.User) { user.LockedUntil = &until })
+	repo.createUserToken(token, email)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) UnlockUserByToken(tokenHash string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	token, err := repo.consumeUserToken(entities.TokenPurposeUnlockAccount, tokenHash)
+	if err != nil {
+		return err
+	}
+	repo.updateUser(token.UserID, func(user *entities.User) {
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
+	})
+	return nil
+}
+
+func (repo *MemoryAuthRepository) CreateUserToken(token *entities.UserToken, email *entities.OutboxEmail) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.createUserToken(token, email)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) FindLatestUserToken(userID ksuid.KSUID, purpose entities.TokenPurpose) (*entities.UserToken, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var latest *entities.UserToken
+	for _, token := range repo.tokens {
+		if token.UserID == userID && token.Purpose == purpose && (latest == nil || token.CreatedAt.After(latest.CreatedAt)) {
+			token := token
+			latest = &token
+		}
+	}
+	if latest == nil {
+		return nil, apperror.ErrInvalidToken
+	}
+	return latest, nil
+}
+
+func (repo *MemoryAuthRepository) ResetPasswordByToken(tokenHash string, hashedPassword string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	token, err := repo.consumeUserToken(entities.TokenPurposeResetPassword, tokenHash)
+	if err != nil {
+		return err
+	}
+	repo.updateUser(token.UserID, func(user *entities.User) {
+		user.Password = hashedPassword
+		user.NoPassword = false
+	})
+	return nil
+}
+
+func (repo *MemoryAuthRepository) FindUserByToken(purpose entities.TokenPurpose, tokenHash string) (*entities.User, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	token, ok := repo.tokens[tokenHash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !token.ExpiresAt.After(repo.clock.Now()) {
+		return nil, apperror.ErrInvalidToken
+	}
+	user, ok := repo.users[token.UserID]
+	if !ok {
+		return nil, apperror.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// the helpers below expect repo.mu to be held
+
+func (repo *MemoryAuthRepository) createUser(user *entities.User) error {
+	if _, ok := repo.users[user.ID]; ok {
+		return apperror.ErrUserAlreadyExists
+	}
+	if _, ok := repo.userByEmail(user.Email); ok {
+		return apperror.ErrUserAlreadyExists
+	}
+	if user.PreferredLocale == "" {
+		user.PreferredLocale = "en"
+	}
+	repo.users[user.ID] = *user
+	return nil
+}
+
+func (repo *MemoryAuthRepository) userByEmail(email string) (entities.User, bool) {
+	for _, user := range repo.users {
+		if user.Email == email {
+			return user, true
+		}
+	}
+	return entities.User{}, false
+}
+
+func (repo *MemoryAuthRepository) loadByEmail(user *entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	saved, ok := repo.userByEmail(user.Email)
+	if !ok {
+		return apperror.ErrUserNotFound
+	}
+	*user = saved
+	return nil
+}
+
+// updateUser reports whether the user exists.
+func (repo *MemoryAuthRepository) updateUser(id ksuid.KSUID, update func(user *entities.User)) bool {
+	user, ok := repo.users[id]
+	if !ok {
+		return false
+	}
+	update(&user)
+	repo.users[id] = user
+	return true
+}
+
+func (repo *MemoryAuthRepository) updateUserByEmail(email string, update func(user *entities.User)) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	user, ok := repo.userByEmail(email)
+	if !ok {
+		return apperror.ErrUserNotFound
+	}
+	update(&user)
+	repo.users[user.ID] = user
+	return nil
+}
+
+func (repo *MemoryAuthRepository) createUserToken(token *entities.UserToken, email *entities.OutboxEmail) {
+	now := repo.clock.Now()
+	for hash, saved := range repo.tokens {
+		expired := saved.ExpiresAt.Before(now)
+		replaced := saved.UserID == token.UserID && saved.Purpose == token.Purpose && saved.UsedAt == nil
+		if expired || replaced {
+			delete(repo.tokens, hash)
+		}
+	}
+	token.CreatedAt = now
+	repo.tokens[token.TokenHash] = *token
+	repo.enqueue(email)
+}
+
+func (repo *MemoryAuthRepository) consumeUserToken(purpose entities.TokenPurpose, tokenHash string) (*entities.UserToken, error) {
+	now := repo.clock.Now()
+	token, ok := repo.tokens[tokenHash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, apperror.ErrInvalidToken
+	}
+	token.UsedAt = &now
+	repo.tokens[tokenHash] = token
+	return &token, nil
+}
+
+func (repo *MemoryAuthRepository) createIdentity(identity *entities.UserIdentity) error {
+	for _, saved := range repo.identities {
+		if saved.Provider == identity.Provider && saved.Subject == identity.Subject {
+			return apperror.ErrIdentityAlreadyLinked
+		}
+	}
+	identity.CreatedAt = repo.clock.Now()
+	repo.identities = append(repo.identities, *identity)
+	return nil
+}
+
+func (repo *MemoryAuthRepository) enqueue(email *entities.OutboxEmail) {
+	email.CreatedAt = repo.clock.Now()
+	repo.outbox = append(repo.outbox, *email)
+}
+
+var _ AuthRepository = (*MemoryAuthRepository)(nil)
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImageRepository interface {
+	ReserveJob(job *entities.ImageJob, quota int, since time.Time) (int64, error)
+	UpdateJob(job *entities.ImageJob) error
+	FindJob(userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error)
+	ListJobs(userID ksuid.KSUID, organizationID ksuid.KSUID, limit int, before ksuid.KSUID) ([]entities.ImageJob, error)
+	FindOrganization(id ksuid.KSUID) (*entities.Organization, error)
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{
+		db: db,
+	}
+}
+
+// ReserveJob saves the job if the user has quota left for jobs created
+// after since and returns the usage including it. A quota of 0 is
+// unlimited. Jobs of an organization count towards the pooled quota of the
+// organization instead. The user or organization row is locked so
+// concurrent requests cannot both take the last slot.
+func (repo *imageRepository) ReserveJob(job *entities.ImageJob, quota int, since time.Time) (int64, error) {
+	var used int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		owner := tx.Model(&entities.ImageJob{})
+		if job.OrganizationID != nil {
+			var organization entities.Organization
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", *job.OrganizationID).First(&organization).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrOrganizationNotFound
+			}
+			if err != nil {
+				return apperror.Internal(fmt.Errorf("error locking organization: %w", err))
+			}
+			owner = owner.Where("organization_id = ?", *job.OrganizationID)
+		} else {
+			var user entities.User
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", job.UserID).First(&user).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.ErrUserNotFound
+			}
+			if err != nil {
+				return apperror.Internal(fmt.Errorf("error locking user: %w", err))
+			}
+			owner = owner.Where("user_id = ? AND organization_id IS NULL", job.UserID)
+		}
+
+		err := owner.Where("created_at >= ? AND status <> ?", since, entities.ImageJobFailed).Count(&used).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error counting image jobs: %w", err))
+		}
+		if quota > 0 && used >= int64(quota) {
+			return apperror.ErrQuotaExceeded.WithMetadata("quota", fmt.Sprint(quota))
+		}
+
+		if err := tx.Create(job).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving image job: %w", err))
+		}
+		used++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return used, nil
+}
+
+func (repo *imageRepository) UpdateJob(job *entities.ImageJob) error {
+	err := repo.db.Model(job).Select("status", "image_url", "filename", "error", "finished_at").Updates(job).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error saving image job: %w", err))
+	}
+	return nil
+}
+
+// FindJob looks in the library of the organization when organizationID is
+// set, where every member can see the jobs, otherwise in the personal
+// library of the user.
+func (repo *imageRepository) FindJob(userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error) {
+	var job entities.ImageJob
+	err := library(repo.db, userID, organizationID).Where("id = ?", jobID).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrImageJobNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &job, nil
+}
+
+// ListJobs returns the newest jobs of the library created before the job
+// before, ksuid.Nil starts from the newest.
+func (repo *imageRepository) ListJobs(userID ksuid.KSUID, organizationID ksuid.KSUID, limit int, before ksuid.KSUID) ([]entities.ImageJob, error) {
+	query := library(repo.db, userID, organizationID)
+	if before != ksuid.Nil {
+		// KSUID diurutkan berdasarkan waktu pembuatan
+		query = query.Where("id < ?", before)
+	}
+	var jobs []entities.ImageJob
+	if err := query.Order("id DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing image jobs: %w", err))
+	}
+	return jobs, nil
+}
+
+func (repo *imageRepository) FindOrganization(id ksuid.KSUID) (*entities.Organization, error) {
+	var organization entities.Organization
+	err := repo.db.Where("id = ?", id).First(&organization).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &organization, nil
+}
+
+func library(db *gorm.DB, userID ksuid.KSUID, organizationID ksuid.KSUID) *gorm.DB {
+	if organizationID != ksuid.Nil {
+		return db.Where("organization_id = ?", organizationID)
+	}
+	return db.Where("user_id = ? AND organization_id IS NULL", userID)
+}
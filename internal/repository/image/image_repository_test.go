@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/database/databasetest"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+func newTestJob(userID ksuid.KSUID, organizationID *ksuid.KSUID) *entities.ImageJob {
+	return &entities.ImageJob{
+		ID:             ksuid.New(),
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Status:         entities.ImageJobPending,
+		Prompt:         "a lighthouse at dusk",
+	}
+}
+
+func TestReserveJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		quota    int
+		reserve  int
+		failOne  bool
+		wantUsed int64
+		want     error
+	}{
+		{name: "within quota", quota: 3, reserve: 3, wantUsed: 3},
+		{name: "over quota", quota: 2, reserve: 3, wantUsed: 2, want: apperror.ErrQuotaExceeded},
+		{name: "unlimited", quota: 0, reserve: 5, wantUsed: 5},
+		{name: "failed jobs do not count", quota: 2, reserve: 3, failOne: true, wantUsed: 2},
+	}
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewImageRepository(db)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				user, _ := entities.NewUser("user", ksuid.New().String()+"@example.com", "hashed", entities.UserRole)
+				if err := db.Create(user).Error; err != nil {
+					t.Fatal(err)
+				}
+
+				var used int64
+				var err error
+				for i := range tt.reserve {
+					job := newTestJob(user.ID, nil)
+					used, err = repo.ReserveJob(job, tt.quota, time.Now().Add(-time.Hour))
+					if err != nil {
+						break
+					}
+					if tt.failOne && i == 0 {
+						job.Status = entities.ImageJobFailed
+						if err := repo.UpdateJob(job); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("ReserveJob error = %v, want %v", err, tt.want)
+				}
+				if tt.want == nil && used != tt.wantUsed {
+					t.Fatalf("ReserveJob used = %d, want %d", used, tt.wantUsed)
+				}
+
+				jobs, err := repo.ListJobs(user.ID, ksuid.Nil, 10, ksuid.Nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				saved := tt.wantUsed
+				if tt.failOne {
+					saved++
+				}
+				if int64(len(jobs)) != saved {
+					t.Fatalf("library has %d jobs, want %d", len(jobs), saved)
+				}
+			})
+		}
+	})
+}
+
+func TestReserveJobOrganizationPool(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewImageRepository(db)
+		var members []*entities.User
+		for range 2 {
+			user, _ := entities.NewUser("member", ksuid.New().String()+"@example.com", "hashed", entities.UserRole)
+			if err := db.Create(user).Error; err != nil {
+				t.Fatal(err)
+			}
+			members = append(members, user)
+		}
+		organization := &entities.Organization{ID: ksuid.New(), Name: "studio", GenerationQuota: 2, CreatedBy: members[0].ID}
+		if err := db.Create(organization).Error; err != nil {
+			t.Fatal(err)
+		}
+		since := time.Now().Add(-time.Hour)
+
+		steps := []struct {
+			user     *entities.User
+			wantUsed int64
+			want     error
+		}{
+			{user: members[0], wantUsed: 1},
+			{user: members[1], wantUsed: 2},
+			{user: members[1], want: apperror.ErrQuotaExceeded},
+		}
+		for i, step := range steps {
+			used, err := repo.ReserveJob(newTestJob(step.user.ID, &organization.ID), organization.GenerationQuota, since)
+			if !errors.Is(err, step.want) {
+				t.Fatalf("step %d: ReserveJob error = %v, want %v", i, err, step.want)
+			}
+			if used != step.wantUsed {
+				t.Fatalf("step %d: ReserveJob used = %d, want %d", i, used, step.wantUsed)
+			}
+		}
+
+		// the personal quota of a member is separate from the pool
+		if used, err := repo.ReserveJob(newTestJob(members[1].ID, nil), 1, since); err != nil || used != 1 {
+			t.Fatalf("personal ReserveJob = %d, %v, want 1, nil", used, err)
+		}
+
+		missing := ksuid.New()
+		if _, err := repo.ReserveJob(newTestJob(members[0].ID, &missing), 2, since); !errors.Is(err, apperror.ErrOrganizationNotFound) {
+			t.Fatalf("ReserveJob on a missing organization = %v, want ErrOrganizationNotFound", err)
+		}
+		if _, err := repo.ReserveJob(newTestJob(ksuid.New(), nil), 2, since); !errors.Is(err, apperror.ErrUserNotFound) {
+			t.Fatalf("ReserveJob for a missing user = %v, want ErrUserNotFound", err)
+		}
+	})
+}
+
+func TestFindJobLibraries(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewImageRepository(db)
+		user, _ := entities.NewUser("owner", "owner@example.com", "hashed", entities.UserRole)
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+		organization := &entities.Organization{ID: ksuid.New(), Name: "studio", GenerationQuota: 5, CreatedBy: user.ID}
+		if err := db.Create(organization).Error; err != nil {
+			t.Fatal(err)
+		}
+		personal := newTestJob(user.ID, nil)
+		shared := newTestJob(user.ID, &organization.ID)
+		for _, job := range []*entities.ImageJob{personal, shared} {
+			if _, err := repo.ReserveJob(job, 0, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			name           string
+			userID         ksuid.KSUID
+			organizationID ksuid.KSUID
+			jobID          ksuid.KSUID
+			want           error
+		}{
+			{name: "personal job", userID: user.ID, jobID: personal.ID},
+			{name: "organization job", userID: ksuid.New(), organizationID: organization.ID, jobID: shared.ID},
+			{name: "organization job outside the organization", userID: user.ID, jobID: shared.ID, want: apperror.ErrImageJobNotFound},
+			{name: "personal job of another user", userID: ksuid.New(), jobID: personal.ID, want: apperror.ErrImageJobNotFound},
+		}
+		for _, tt := range tests {
+			_, err := repo.FindJob(tt.userID, tt.organizationID, tt.jobID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("%s: FindJob error = %v, want %v", tt.name, err, tt.want)
+			}
+		}
+	})
+}
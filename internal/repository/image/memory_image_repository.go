@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+// MemoryImageRepository keeps jobs in memory with the same quota and
+// library rules as the gorm repository, for tests of the image usecase.
+// Users and organizations must be added before jobs are reserved for them.
+type MemoryImageRepository struct {
+	mu            sync.Mutex
+	clock         utils.Clock
+	users         map[ksuid.KSUID]bool
+	organizations map[ksuid.KSUID]entities.Organization
+	jobs          map[ksuid.KSUID]entities.ImageJob
+}
+
+// NewMemoryImageRepository stamps CreatedAt and UpdatedAt with clock.
+func NewMemoryImageRepository(clock utils.Clock) *MemoryImageRepository {
+	return &MemoryImageRepository{
+		clock:         clock,
+		users:         map[ksuid.KSUID]bool{},
+		organizations: map[ksuid.KSUID]entities.Organization{},
+		jobs:          map[ksuid.KSUID]entities.ImageJob{},
+	}
+}
+
+func (repo *MemoryImageRepository) AddUser(userID ksuid.KSUID) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.users[userID] = true
+}
+
+func (repo *MemoryImageRepository) AddOrganization(organization entities.Organization) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.organizations[organization.ID] = organization
+}
+
+// Jobs returns every saved job, oldest first.
+func (repo *MemoryImageRepository) Jobs() []entities.ImageJob {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	jobs := make([]entities.ImageJob, 0, len(repo.jobs))
+	for _, job := range repo.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return ksuid.Compare(jobs[i].ID, jobs[j].ID) < 0 })
+	return jobs
+}
+
+func (repo *MemoryImageRepository) ReserveJob(job *entities.ImageJob, quota int, since time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if job.OrganizationID != nil {
+		if _, ok := repo.organizations[*job.OrganizationID]; !ok {
+			return 0, apperror.ErrOrganizationNotFound
+		}
+	} else if !repo.users[job.UserID] {
+		return 0, apperror.ErrUserNotFound
+	}
+
+	organizationID := ksuid.Nil
+	if job.OrganizationID != nil {
+		organizationID = *job.OrganizationID
+	}
+	var used int64
+	for _, saved := range repo.jobs {
+		if inLibrary(saved, job.UserID, organizationID) && !saved.CreatedAt.Before(since) && saved.Status != entities.ImageJobFailed {
+			used++
+		}
+	}
+	if quota > 0 && used >= int64(quota) {
+		return 0, apperror.ErrQuotaExceeded.WithMetadata("quota", fmt.Sprint(quota))
+	}
+
+	now := repo.clock.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	repo.jobs[job.ID] = *job
+	return used + 1, nil
+}
+
+func (repo *MemoryImageRepository) UpdateJob(job *entities.ImageJob) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	saved, ok := repo.jobs[job.ID]
+	if !ok {
+		// seperti UPDATE tanpa baris yang cocok
+		return nil
+	}
+	saved.Status = job.Status
+	saved.ImageURL = job.ImageURL
+	saved.Filename = job.Filename
+	saved.Error = job.Error
+	saved.FinishedAt = job.FinishedAt
+	saved.UpdatedAt = repo.clock.Now()
+	repo.jobs[job.ID] = saved
+	return nil
+}
+
+func (repo *MemoryImageRepository) FindJob(userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	job, ok := repo.jobs[jobID]
+	if !ok || !inLibrary(job, userID, organizationID) {
+		return nil, apperror.ErrImageJobNotFound
+	}
+	return &job, nil
+}
+
+func (repo *MemoryImageRepository) ListJobs(userID ksuid.KSUID, organizationID ksuid.KSUID, limit int, before ksuid.KSUID) ([]entities.ImageJob, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var jobs []entities.ImageJob
+	for _, job := range repo.jobs {
+		if !inLibrary(job, userID, organizationID) {
+			continue
+		}
+		if before != ksuid.Nil && ksuid.Compare(job.ID, before) >= 0 {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return ksuid.Compare(jobs[i].ID, jobs[j].ID) > 0 })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (repo *MemoryImageRepository) FindOrganization(id ksuid.KSUID) (*entities.Organization, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	organization, ok := repo.organizations[id]
+	if !ok {
+		return nil, apperror.ErrOrganizationNotFound
+	}
+	return &organization, nil
+}
+
+// inLibrary mirrors library for a job already loaded.
+func inLibrary(job entities.ImageJob, userID ksuid.KSUID, organizationID ksuid.KSUID) bool {
+	if organizationID != ksuid.Nil {
+		return job.OrganizationID != nil && *job.OrganizationID == organizationID
+	}
+	return job.UserID == userID && job.OrganizationID == nil
+}
+
+var _ ImageRepository = (*MemoryImageRepository)(nil)
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	FindUserByID(id ksuid.KSUID) (*entities.User, error)
+	FindPreferences(userID ksuid.KSUID) ([]entities.NotificationPreference, error)
+	FindPreference(userID ksuid.KSUID, event entities.NotificationEvent) (*entities.NotificationPreference, error)
+	SavePreferences(preferences []entities.NotificationPreference) error
+	EnqueueEmail(email *entities.OutboxEmail) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (repo *notificationRepository) FindUserByID(id ksuid.KSUID) (*entities.User, error) {
+	var user entities.User
+	err := repo.db.Where("id = ?", id).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &user, nil
+}
+
+func (repo *notificationRepository) FindPreferences(userID ksuid.KSUID) ([]entities.NotificationPreference, error) {
+	var preferences []entities.NotificationPreference
+	err := repo.db.Where("user_id = ?", userID).Find(&preferences).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing notification preferences: %w", err))
+	}
+	return preferences, nil
+}
+
+// FindPreference returns nil without error when the user kept the default.
+func (repo *notificationRepository) FindPreference(userID ksuid.KSUID, event entities.NotificationEvent) (*entities.NotificationPreference, error) {
+	var preference entities.NotificationPreference
+	err := repo.db.Where("user_id = ? AND event = ?", userID, event).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error finding notification preference: %w", err))
+	}
+	return &preference, nil
+}
+
+func (repo *notificationRepository) SavePreferences(preferences []entities.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	err := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email_enabled", "updated_at"}),
+	}).Create(&preferences).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error saving notification preferences: %w", err))
+	}
+	return nil
+}
+
+func (repo *notificationRepository) EnqueueEmail(email *entities.OutboxEmail) error {
+	if err := repo.db.Create(email).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error saving outbox email: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository interface {
+	CreateOrganization(organization *entities.Organization, owner *entities.Membership, limit int) error
+	FindOrganization(id ksuid.KSUID) (*entities.Organization, error)
+	UpdateGenerationQuota(id ksuid.KSUID, quota int) error
+	FindOrganizations(ids []ksuid.KSUID) ([]entities.Organization, error)
+	DeleteOrganization(id ksuid.KSUID) error
+	FindMembership(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error)
+	FindMemberships(userID ksuid.KSUID) ([]entities.Membership, error)
+	FindMembers(organizationID ksuid.KSUID) ([]entities.Membership, error)
+	UpdateMemberRole(organizationID ksuid.KSUID, userID ksuid.KSUID, role entities.OrganizationRole) error
+	RemoveMember(organizationID ksuid.KSUID, userID ksuid.KSUID) error
+	FindUserByID(id ksuid.KSUID) (*entities.User, error)
+	FindUsers(ids []ksuid.KSUID) ([]entities.User, error)
+	CreateInvitation(invitation *entities.OrganizationInvitation, email *entities.OutboxEmail) error
+	FindInvitations(organizationID ksuid.KSUID) ([]entities.OrganizationInvitation, error)
+	FindInvitationByTokenHash(tokenHash string) (*entities.OrganizationInvitation, error)
+	DeleteInvitation(organizationID ksuid.KSUID, invitationID ksuid.KSUID) error
+	AcceptInvitation(invitation *entities.OrganizationInvitation, membership *entities.Membership) error
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+// CreateOrganization saves the organization unless its creator already
+// created limit of them, 0 is unlimited. The user row is locked so
+// concurrent requests cannot go over the limit.
+func (repo *organizationRepository) CreateOrganization(organization *entities.Organization, owner *entities.Membership, limit int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var user entities.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", organization.CreatedBy).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.ErrUserNotFound
+		}
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error locking user: %w", err))
+		}
+
+		var count int64
+		if err := tx.Model(&entities.Organization{}).Where("created_by = ?", organization.CreatedBy).Count(&count).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error counting organizations: %w", err))
+		}
+		if limit > 0 && count >= int64(limit) {
+			return apperror.ErrOrganizationLimitReached.WithMetadata("limit", fmt.Sprint(limit))
+		}
+
+		if err := tx.Create(organization).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving organization: %w", err))
+		}
+		if err := tx.Create(owner).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving membership: %w", err))
+		}
+		return nil
+	})
+}
+
+func (repo *organizationRepository) FindOrganization(id ksuid.KSUID) (*entities.Organization, error) {
+	var organization entities.Organization
+	err := repo.db.Where("id = ?", id).First(&organization).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &organization, nil
+}
+
+func (repo *organizationRepository) UpdateGenerationQuota(id ksuid.KSUID, quota int) error {
+	result := repo.db.Model(&entities.Organization{}).Where("id = ?", id).Update("generation_quota", quota)
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error saving organization: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+func (repo *organizationRepository) FindOrganizations(ids []ksuid.KSUID) ([]entities.Organization, error) {
+	var organizations []entities.Organization
+	if len(ids) == 0 {
+		return organizations, nil
+	}
+	if err := repo.db.Where("id IN ?", ids).Order("name").Find(&organizations).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing organizations: %w", err))
+	}
+	return organizations, nil
+}
+
+// DeleteOrganization moves the images of the organization back to the
+// personal libraries of the members that generated them.
+func (repo *organizationRepository) DeleteOrganization(id ksuid.KSUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.ImageJob{}).Where("organization_id = ?", id).Update("organization_id", nil).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error moving organization images: %w", err))
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&entities.OrganizationInvitation{}).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error deleting invitations: %w", err))
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&entities.Membership{}).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error deleting memberships: %w", err))
+		}
+		result := tx.Where("id = ?", id).Delete(&entities.Organization{})
+		if result.Error != nil {
+			return apperror.Internal(fmt.Errorf("error deleting organization: %w", result.Error))
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrOrganizationNotFound
+		}
+		return nil
+	})
+}
+
+// FindMembership returns ErrNotOrganizationMember when the user is not a
+// member, whether or not the organization exists.
+func (repo *organizationRepository) FindMembership(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error) {
+	var membership entities.Membership
+	err := repo.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrNotOrganizationMember
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &membership, nil
+}
+
+func (repo *organizationRepository) FindMemberships(userID ksuid.KSUID) ([]entities.Membership, error) {
+	var memberships []entities.Membership
+	if err := repo.db.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing memberships: %w", err))
+	}
+	return memberships, nil
+}
+
+func (repo *organizationRepository) FindMembers(organizationID ksuid.KSUID) ([]entities.Membership, error) {
+	var memberships []entities.Membership
+	if err := repo.db.Where("organization_id = ?", organizationID).Order("created_at").Find(&memberships).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing members: %w", err))
+	}
+	return memberships, nil
+}
+
+// UpdateMemberRole refuses to demote the last owner.
+func (repo *organizationRepository) UpdateMemberRole(organizationID ksuid.KSUID, userID ksuid.KSUID, role entities.OrganizationRole) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := keepOwner(tx, organizationID, userID, role != entities.OrganizationOwner); err != nil {
+			return err
+		}
+		err := tx.Model(&entities.Membership{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Update("role", role).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error updating member role: %w", err))
+		}
+		return nil
+	})
+}
+
+// RemoveMember refuses to remove the last owner.
+func (repo *organizationRepository) RemoveMember(organizationID ksuid.KSUID, userID ksuid.KSUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := keepOwner(tx, organizationID, userID, true); err != nil {
+			return err
+		}
+		err := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).Delete(&entities.Membership{}).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error removing member: %w", err))
+		}
+		return nil
+	})
+}
+
+// keepOwner locks the organization and, when the owner userID is leaving
+// the role, checks another owner remains. Locking the organization row
+// keeps two owners from demoting each other at the same time.
+func keepOwner(tx *gorm.DB, organizationID ksuid.KSUID, userID ksuid.KSUID, leaving bool) error {
+	var organization entities.Organization
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", organizationID).First(&organization).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error locking organization: %w", err))
+	}
+
+	var membership entities.Membership
+	err = tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.ErrMemberNotFound
+	}
+	if err != nil {
+		return apperror.Internal(err)
+	}
+	if !leaving || membership.Role != entities.OrganizationOwner {
+		return nil
+	}
+
+	var owners int64
+	err = tx.Model(&entities.Membership{}).
+		Where("organization_id = ? AND role = ?", organizationID, entities.OrganizationOwner).
+		Count(&owners).Error
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error counting owners: %w", err))
+	}
+	if owners <= 1 {
+		return apperror.ErrLastOwner
+	}
+	return nil
+}
+
+func (repo *organizationRepository) FindUserByID(id ksuid.KSUID) (*entities.User, error) {
+	var user entities.User
+	err := repo.db.Where("id = ?", id).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &user, nil
+}
+
+func (repo *organizationRepository) FindUsers(ids []ksuid.KSUID) ([]entities.User, error) {
+	var users []entities.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := repo.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing users: %w", err))
+	}
+	return users, nil
+}
+
+func (repo *organizationRepository) CreateInvitation(invitation *entities.OrganizationInvitation, email *entities.OutboxEmail) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(invitation).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving invitation: %w", err))
+		}
+		if err := tx.Create(email).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving outbox email: %w", err))
+		}
+		return nil
+	})
+}
+
+// FindInvitations returns the invitations that can still be accepted.
+func (repo *organizationRepository) FindInvitations(organizationID ksuid.KSUID) ([]entities.OrganizationInvitation, error) {
+	var invitations []entities.OrganizationInvitation
+	err := repo.db.
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", organizationID, time.Now()).
+		Order("created_at").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing invitations: %w", err))
+	}
+	return invitations, nil
+}
+
+func (repo *organizationRepository) FindInvitationByTokenHash(tokenHash string) (*entities.OrganizationInvitation, error) {
+	var invitation entities.OrganizationInvitation
+	err := repo.db.Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &invitation, nil
+}
+
+func (repo *organizationRepository) DeleteInvitation(organizationID ksuid.KSUID, invitationID ksuid.KSUID) error {
+	result := repo.db.Where("id = ? AND organization_id = ? AND accepted_at IS NULL", invitationID, organizationID).
+		Delete(&entities.OrganizationInvitation{})
+	if result.Error != nil {
+		return apperror.Internal(fmt.Errorf("error deleting invitation: %w", result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return apperror.ErrInvitationNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation marks the invitation used and adds the membership, an
+// invitation can only be accepted once.
+func (repo *organizationRepository) AcceptInvitation(invitation *entities.OrganizationInvitation, membership *entities.Membership) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", time.Now())
+		if result.Error != nil {
+			return apperror.Internal(fmt.Errorf("error accepting invitation: %w", result.Error))
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrInvalidInvitation
+		}
+
+		var count int64
+		err := tx.Model(&entities.Membership{}).
+			Where("organization_id = ? AND user_id = ?", membership.OrganizationID, membership.UserID).
+			Count(&count).Error
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error finding membership: %w", err))
+		}
+		if count > 0 {
+			return apperror.ErrAlreadyMember
+		}
+		if err := tx.Create(membership).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving membership: %w", err))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/database/databasetest"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+func newTestOrganization(userID ksuid.KSUID) (*entities.Organization, *entities.Membership) {
+	organization := &entities.Organization{ID: ksuid.New(), Name: "studio", GenerationQuota: 1000, CreatedBy: userID}
+	owner := &entities.Membership{OrganizationID: organization.ID, UserID: userID, Role: entities.OrganizationOwner}
+	return organization, owner
+}
+
+func TestCreateOrganizationLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		create int
+		want   error
+	}{
+		{name: "within limit", limit: 2, create: 2},
+		{name: "over limit", limit: 2, create: 3, want: apperror.ErrOrganizationLimitReached},
+		{name: "unlimited", limit: 0, create: 4},
+	}
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewOrganizationRepository(db)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				user, _ := entities.NewUser("user", ksuid.New().String()+"@example.com", "hashed", entities.UserRole)
+				if err := db.Create(user).Error; err != nil {
+					t.Fatal(err)
+				}
+
+				var err error
+				for range tt.create {
+					organization, owner := newTestOrganization(user.ID)
+					if err = repo.CreateOrganization(organization, owner, tt.limit); err != nil {
+						break
+					}
+				}
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("CreateOrganization error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+
+		// deleting an organization frees its slot
+		user, _ := entities.NewUser("user", "delete@example.com", "hashed", entities.UserRole)
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+		organization, owner := newTestOrganization(user.ID)
+		if err := repo.CreateOrganization(organization, owner, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.DeleteOrganization(organization.ID); err != nil {
+			t.Fatal(err)
+		}
+		organization, owner = newTestOrganization(user.ID)
+		if err := repo.CreateOrganization(organization, owner, 1); err != nil {
+			t.Fatalf("CreateOrganization after deleting the first one: %v", err)
+		}
+	})
+}
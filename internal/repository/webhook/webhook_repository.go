@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(webhook *entities.Webhook, limit int) error
+	FindWebhooks(userID ksuid.KSUID) ([]entities.Webhook, error)
+	DeleteWebhook(userID ksuid.KSUID, webhookID ksuid.KSUID) error
+	FindDeliveries(userID ksuid.KSUID, webhookID ksuid.KSUID, limit int) ([]entities.WebhookDelivery, error)
+	FindDelivery(userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error)
+	CreateDeliveries(deliveries []entities.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// CreateWebhook saves the webhook unless the user already has limit of
+// them, 0 is unlimited. The user row is locked so concurrent requests
+// cannot go over the limit.
+func (repo *webhookRepository) CreateWebhook(webhook *entities.Webhook, limit int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var user entities.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", webhook.UserID).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.ErrUserNotFound
+		}
+		if err != nil {
+			return apperror.Internal(fmt.Errorf("error locking user: %w", err))
+		}
+
+		var count int64
+		if err := tx.Model(&entities.Webhook{}).Where("user_id = ?", webhook.UserID).Count(&count).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error counting webhooks: %w", err))
+		}
+		if limit > 0 && count >= int64(limit) {
+			return apperror.ErrWebhookLimitReached.WithMetadata("limit", fmt.Sprint(limit))
+		}
+
+		if err := tx.Create(webhook).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error saving webhook: %w", err))
+		}
+		return nil
+	})
+}
+
+func (repo *webhookRepository) FindWebhooks(userID ksuid.KSUID) ([]entities.Webhook, error) {
+	var webhooks []entities.Webhook
+	err := repo.db.Where("user_id = ?", userID).Order("created_at").Find(&webhooks).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing webhooks: %w", err))
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook also deletes its delivery log, pending deliveries are
+// never sent.
+func (repo *webhookRepository) DeleteWebhook(userID ksuid.KSUID, webhookID ksuid.KSUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", webhookID, userID).Delete(&entities.Webhook{})
+		if result.Error != nil {
+			return apperror.Internal(fmt.Errorf("error deleting webhook: %w", result.Error))
+		}
+		if result.RowsAffected == 0 {
+			return apperror.ErrWebhookNotFound
+		}
+		if err := tx.Where("webhook_id = ?", webhookID).Delete(&entities.WebhookDelivery{}).Error; err != nil {
+			return apperror.Internal(fmt.Errorf("error deleting webhook deliveries: %w", err))
+		}
+		return nil
+	})
+}
+
+// FindDeliveries returns the newest deliveries of one of the user's webhooks.
+func (repo *webhookRepository) FindDeliveries(userID ksuid.KSUID, webhookID ksuid.KSUID, limit int) ([]entities.WebhookDelivery, error) {
+	var count int64
+	if err := repo.db.Model(&entities.Webhook{}).Where("id = ? AND user_id = ?", webhookID, userID).Count(&count).Error; err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error finding webhook: %w", err))
+	}
+	if count == 0 {
+		return nil, apperror.ErrWebhookNotFound
+	}
+
+	var deliveries []entities.WebhookDelivery
+	err := repo.db.Where("webhook_id = ?", webhookID).Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error listing webhook deliveries: %w", err))
+	}
+	return deliveries, nil
+}
+
+func (repo *webhookRepository) FindDelivery(userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	err := repo.db.
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.id = ? AND webhooks.user_id = ?", deliveryID, userID).
+		First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	return &delivery, nil
+}
+
+func (repo *webhookRepository) CreateDeliveries(deliveries []entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := repo.db.Create(&deliveries).Error; err != nil {
+		return apperror.Internal(fmt.Errorf("error saving webhook deliveries: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	usecase "github.com/oriastanjung/stellar/internal/usecase/apikey"
+	"github.com/segmentio/ksuid"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID ksuid.KSUID, name string, scopes []entities.APIKeyScope, expiresInDays int) (*entities.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID ksuid.KSUID) ([]entities.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID ksuid.KSUID, keyID ksuid.KSUID) error
+}
+
+type apiKeyService struct {
+	apiKeyUseCase usecase.APIKeyUseCase
+}
+
+func NewAPIKeyService(apiKeyUseCase usecase.APIKeyUseCase) APIKeyService {
+	return &apiKeyService{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (service *apiKeyService) CreateAPIKey(ctx context.Context, userID ksuid.KSUID, name string, scopes []entities.APIKeyScope, expiresInDays int) (*entities.APIKey, string, error) {
+	return service.apiKeyUseCase.CreateAPIKey(userID, name, scopes, expiresInDays)
+}
+
+func (service *apiKeyService) ListAPIKeys(ctx context.Context, userID ksuid.KSUID) ([]entities.APIKey, error) {
+	return service.apiKeyUseCase.ListAPIKeys(userID)
+}
+
+func (service *apiKeyService) RevokeAPIKey(ctx context.Context, userID ksuid.KSUID, keyID ksuid.KSUID) error {
+	return service.apiKeyUseCase.RevokeAPIKey(userID, keyID)
+}
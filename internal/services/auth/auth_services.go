@@ -2,21 +2,40 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/oriastanjung/stellar/internal/entities"
 	usecase "github.com/oriastanjung/stellar/internal/usecase/auth"
+	"github.com/segmentio/ksuid"
 )
 
 type AuthService interface {
-	RegisterAdmin(ctx context.Context, user *entities.User, salt int) error
-	LoginAdmin(ctx context.Context, user *entities.User) (string, error)
-	RegisterUser(ctx context.Context, user *entities.User, salt int) error
-	LoginUser(ctx context.Context, user *entities.User) (string, error)
+	RegisterAdmin(ctx context.Context, user *entities.User, token string) error
+	LoginAdmin(ctx context.Context, user *entities.User) (*usecase.LoginResult, error)
+	RegisterUser(ctx context.Context, user *entities.User) error
+	LoginUser(ctx context.Context, user *entities.User) (*usecase.LoginResult, error)
 	VerifyUser(ctx context.Context, token string) error
 	RequestForgetPassword(ctx context.Context, email string) error
 	ResetPasswordByToken(ctx context.Context, token string, password string) error
+	UnlockAccount(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	UpdatePreferredLocale(ctx context.Context, userID ksuid.KSUID, locale string) error
+	UpdateSubscription(ctx context.Context, userID ksuid.KSUID, active bool) error
 	LoginUserViaGoogle(ctx context.Context) (string, error)
-	LoginUserViaGoogleCallback(ctx context.Context, email string, username string, pictureUrl string) (string, error)
+	LoginUserViaGoogleCallback(ctx context.Context, code string, state string) (*usecase.LoginResult, error)
+	EnrollTOTP(ctx context.Context, userID ksuid.KSUID) (*usecase.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID ksuid.KSUID, code string, issueToken bool) ([]string, string, error)
+	VerifyMFA(ctx context.Context, mfaToken string, code string) (string, error)
+	DisableTOTP(ctx context.Context, userID ksuid.KSUID, code string) error
+	LoginViaProvider(ctx context.Context, providerName string) (string, error)
+	LoginViaProviderCallback(ctx context.Context, providerName string, code string, state string) (*usecase.LoginResult, error)
+	StartLinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) (string, error)
+	LinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string, code string, state string) error
+	UnlinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) error
+	ListIdentities(ctx context.Context, userID ksuid.KSUID) ([]entities.UserIdentity, error)
+	CreateAdminInvitation(ctx context.Context, inviterID ksuid.KSUID, email string, ttl time.Duration) (*entities.AdminInvitation, error)
+	ListAdminInvitations(ctx context.Context) ([]entities.AdminInvitation, error)
+	RevokeAdminInvitation(ctx context.Context, id ksuid.KSUID) error
 }
 
 type authService struct {
@@ -29,20 +48,20 @@ func NewAuthService(authUseCase usecase.AuthUseCase) AuthService {
 	}
 }
 
-func (service *authService) RegisterAdmin(ctx context.Context, user *entities.User, salt int) error {
-	return service.authUseCase.RegisterAdmin(user, salt)
+func (service *authService) RegisterAdmin(ctx context.Context, user *entities.User, token string) error {
+	return service.authUseCase.RegisterAdminWithToken(user, token)
 }
 
-func (service *authService) LoginAdmin(ctx context.Context, user *entities.User) (string, error) {
-	return service.authUseCase.LoginAdmin(user)
+func (service *authService) LoginAdmin(ctx context.Context, user *entities.User) (*usecase.LoginResult, error) {
+	return service.authUseCase.LoginAdmin(ctx, user)
 }
 
-func (service *authService) RegisterUser(ctx context.Context, user *entities.User, salt int) error {
-	return service.authUseCase.RegisterUser(user, salt)
+func (service *authService) RegisterUser(ctx context.Context, user *entities.User) error {
+	return service.authUseCase.RegisterUser(user)
 }
 
-func (service *authService) LoginUser(ctx context.Context, user *entities.User) (string, error) {
-	return service.authUseCase.LoginUser(user)
+func (service *authService) LoginUser(ctx context.Context, user *entities.User) (*usecase.LoginResult, error) {
+	return service.authUseCase.LoginUser(ctx, user)
 }
 
 func (service *authService) VerifyUser(ctx context.Context, token string) error {
@@ -54,11 +73,76 @@ func (service *authService) RequestForgetPassword(ctx context.Context, email str
 func (service *authService) ResetPasswordByToken(ctx context.Context, token string, password string) error {
 	return service.authUseCase.ResetPasswordByToken(token, password)
 }
+func (service *authService) UnlockAccount(ctx context.Context, token string) error {
+	return service.authUseCase.UnlockAccount(token)
+}
+func (service *authService) ResendVerificationEmail(ctx context.Context, email string) error {
+	return service.authUseCase.ResendVerificationEmail(email)
+}
+func (service *authService) UpdatePreferredLocale(ctx context.Context, userID ksuid.KSUID, locale string) error {
+	return service.authUseCase.UpdatePreferredLocale(userID, locale)
+}
+
+func (service *authService) UpdateSubscription(ctx context.Context, userID ksuid.KSUID, active bool) error {
+	return service.authUseCase.UpdateSubscription(userID, active)
+}
 
 func (service *authService) LoginUserViaGoogle(ctx context.Context) (string, error) {
 	return service.authUseCase.LoginUserViaGoogle(ctx)
 }
 
-func (service *authService) LoginUserViaGoogleCallback(ctx context.Context, email string, username string, pictureUrl string) (string, error) {
-	return service.authUseCase.LoginUserViaGoogleCallback(ctx, email, username, pictureUrl)
+func (service *authService) LoginUserViaGoogleCallback(ctx context.Context, code string, state string) (*usecase.LoginResult, error) {
+	return service.authUseCase.LoginUserViaGoogleCallback(ctx, code, state)
+}
+
+func (service *authService) EnrollTOTP(ctx context.Context, userID ksuid.KSUID) (*usecase.TOTPEnrollment, error) {
+	return service.authUseCase.EnrollTOTP(userID)
+}
+
+func (service *authService) ConfirmTOTP(ctx context.Context, userID ksuid.KSUID, code string, issueToken bool) ([]string, string, error) {
+	return service.authUseCase.ConfirmTOTP(ctx, userID, code, issueToken)
+}
+
+func (service *authService) VerifyMFA(ctx context.Context, mfaToken string, code string) (string, error) {
+	return service.authUseCase.VerifyMFA(ctx, mfaToken, code)
+}
+
+func (service *authService) DisableTOTP(ctx context.Context, userID ksuid.KSUID, code string) error {
+	return service.authUseCase.DisableTOTP(userID, code)
+}
+
+func (service *authService) LoginViaProvider(ctx context.Context, providerName string) (string, error) {
+	return service.authUseCase.LoginViaProvider(ctx, providerName)
+}
+
+func (service *authService) LoginViaProviderCallback(ctx context.Context, providerName string, code string, state string) (*usecase.LoginResult, error) {
+	return service.authUseCase.LoginViaProviderCallback(ctx, providerName, code, state)
+}
+
+func (service *authService) StartLinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) (string, error) {
+	return service.authUseCase.StartLinkIdentity(ctx, userID, providerName)
+}
+
+func (service *authService) LinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string, code string, state string) error {
+	return service.authUseCase.LinkIdentity(ctx, userID, providerName, code, state)
+}
+
+func (service *authService) UnlinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) error {
+	return service.authUseCase.UnlinkIdentity(ctx, userID, providerName)
+}
+
+func (service *authService) ListIdentities(ctx context.Context, userID ksuid.KSUID) ([]entities.UserIdentity, error) {
+	return service.authUseCase.ListIdentities(ctx, userID)
+}
+
+func (service *authService) CreateAdminInvitation(ctx context.Context, inviterID ksuid.KSUID, email string, ttl time.Duration) (*entities.AdminInvitation, error) {
+	return service.authUseCase.CreateAdminInvitation(inviterID, email, ttl)
+}
+
+func (service *authService) ListAdminInvitations(ctx context.Context) ([]entities.AdminInvitation, error) {
+	return service.authUseCase.ListAdminInvitations()
+}
+
+func (service *authService) RevokeAdminInvitation(ctx context.Context, id ksuid.KSUID) error {
+	return service.authUseCase.RevokeAdminInvitation(id)
 }
@@ -3,13 +3,18 @@ package services
 import (
 	"context"
 
+	"github.com/oriastanjung/stellar/internal/entities"
 	usecase "github.com/oriastanjung/stellar/internal/usecase/image"
+	"github.com/segmentio/ksuid"
 )
 
 // ImageService defines the contract for image-related operations.
 type ImageService interface {
-	GenerateImage(ctx context.Context, prompt string) (string, string, error)
-	DownloadAndSaveImages(ctx context.Context, imageURL, filename string) error
+	GenerateImage(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (string, string, error)
+	GenerateImageAsync(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (*entities.ImageJob, error)
+	GetImageJob(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error)
+	ListImageJobs(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, pageSize int, pageToken string) ([]entities.ImageJob, string, error)
+	DownloadAndSaveImages(ctx context.Context, userID ksuid.KSUID, imageURL, filename string) error
 }
 
 // imageService is the concrete implementation of ImageService.
@@ -25,11 +30,26 @@ func NewImageService(imageUseCase usecase.ImageUseCase) ImageService {
 }
 
 // GenerateImage delegates the call to the usecase layer.
-func (s *imageService) GenerateImage(ctx context.Context, prompt string) (string, string, error) {
-	return s.imageUseCase.GenerateImage(prompt)
+func (s *imageService) GenerateImage(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (string, string, error) {
+	return s.imageUseCase.GenerateImage(userID, organizationID, prompt)
+}
+
+// GenerateImageAsync delegates the call to the usecase layer.
+func (s *imageService) GenerateImageAsync(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (*entities.ImageJob, error) {
+	return s.imageUseCase.GenerateImageAsync(userID, organizationID, prompt)
+}
+
+// GetImageJob delegates the call to the usecase layer.
+func (s *imageService) GetImageJob(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error) {
+	return s.imageUseCase.GetImageJob(userID, organizationID, jobID)
+}
+
+// ListImageJobs delegates the call to the usecase layer.
+func (s *imageService) ListImageJobs(ctx context.Context, userID ksuid.KSUID, organizationID ksuid.KSUID, pageSize int, pageToken string) ([]entities.ImageJob, string, error) {
+	return s.imageUseCase.ListImageJobs(userID, organizationID, pageSize, pageToken)
 }
 
 // DownloadAndSaveImages delegates the call to the usecase layer.
-func (s *imageService) DownloadAndSaveImages(ctx context.Context, imageURL, filename string) error {
-	return s.imageUseCase.DownloadAndSaveImages(imageURL, filename)
+func (s *imageService) DownloadAndSaveImages(ctx context.Context, userID ksuid.KSUID, imageURL, filename string) error {
+	return s.imageUseCase.DownloadAndSaveImages(userID, imageURL, filename)
 }
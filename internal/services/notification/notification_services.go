@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	usecase "github.com/oriastanjung/stellar/internal/usecase/notification"
+	"github.com/segmentio/ksuid"
+)
+
+type NotificationService interface {
+	GetPreferences(ctx context.Context, userID ksuid.KSUID) ([]entities.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID ksuid.KSUID, preferences []entities.NotificationPreference) ([]entities.NotificationPreference, error)
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type notificationService struct {
+	notificationUseCase usecase.NotificationUseCase
+}
+
+func NewNotificationService(notificationUseCase usecase.NotificationUseCase) NotificationService {
+	return &notificationService{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+func (service *notificationService) GetPreferences(ctx context.Context, userID ksuid.KSUID) ([]entities.NotificationPreference, error) {
+	return service.notificationUseCase.GetPreferences(userID)
+}
+
+func (service *notificationService) UpdatePreferences(ctx context.Context, userID ksuid.KSUID, preferences []entities.NotificationPreference) ([]entities.NotificationPreference, error) {
+	return service.notificationUseCase.UpdatePreferences(userID, preferences)
+}
+
+func (service *notificationService) Unsubscribe(ctx context.Context, token string) error {
+	return service.notificationUseCase.Unsubscribe(token)
+}
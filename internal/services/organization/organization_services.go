@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	usecase "github.com/oriastanjung/stellar/internal/usecase/organization"
+	"github.com/segmentio/ksuid"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, userID ksuid.KSUID, name string) (*entities.Organization, error)
+	SetGenerationQuota(ctx context.Context, adminID ksuid.KSUID, organizationID ksuid.KSUID, quota int) (*entities.Organization, error)
+	ListOrganizations(ctx context.Context, userID ksuid.KSUID) ([]usecase.UserOrganization, error)
+	DeleteOrganization(ctx context.Context, actor *entities.Membership) error
+	InviteMember(ctx context.Context, actor *entities.Membership, email string, role entities.OrganizationRole) (*entities.OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, actor *entities.Membership) ([]entities.OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, actor *entities.Membership, invitationID ksuid.KSUID) error
+	AcceptInvitation(ctx context.Context, userID ksuid.KSUID, token string) (*entities.Membership, error)
+	ListMembers(ctx context.Context, actor *entities.Membership) ([]usecase.Member, error)
+	UpdateMemberRole(ctx context.Context, actor *entities.Membership, userID ksuid.KSUID, role entities.OrganizationRole) error
+	RemoveMember(ctx context.Context, actor *entities.Membership, userID ksuid.KSUID) error
+}
+
+type organizationService struct {
+	organizationUseCase usecase.OrganizationUseCase
+}
+
+func NewOrganizationService(organizationUseCase usecase.OrganizationUseCase) OrganizationService {
+	return &organizationService{
+		organizationUseCase: organizationUseCase,
+	}
+}
+
+func (service *organizationService) CreateOrganization(ctx context.Context, userID ksuid.KSUID, name string) (*entities.Organization, error) {
+	return service.organizationUseCase.CreateOrganization(userID, name)
+}
+
+func (service *organizationService) SetGenerationQuota(ctx context.Context, adminID ksuid.KSUID, organizationID ksuid.KSUID, quota int) (*entities.Organization, error) {
+	return service.organizationUseCase.SetGenerationQuota(adminID, organizationID, quota)
+}
+
+func (service *organizationService) ListOrganizations(ctx context.Context, userID ksuid.KSUID) ([]usecase.UserOrganization, error) {
+	return service.organizationUseCase.ListOrganizations(userID)
+}
+
+func (service *organizationService) DeleteOrganization(ctx context.Context, actor *entities.Membership) error {
+	return service.organizationUseCase.DeleteOrganization(actor)
+}
+
+func (service *organizationService) InviteMember(ctx context.Context, actor *entities.Membership, email string, role entities.OrganizationRole) (*entities.OrganizationInvitation, error) {
+	return service.organizationUseCase.InviteMember(actor, email, role)
+}
+
+func (service *organizationService) ListInvitations(ctx context.Context, actor *entities.Membership) ([]entities.OrganizationInvitation, error) {
+	return service.organizationUseCase.ListInvitations(actor)
+}
+
+func (service *organizationService) RevokeInvitation(ctx context.Context, actor *entities.Membership, invitationID ksuid.KSUID) error {
+	return service.organizationUseCase.RevokeInvitation(actor, invitationID)
+}
+
+func (service *organizationService) AcceptInvitation(ctx context.Context, userID ksuid.KSUID, token string) (*entities.Membership, error) {
+	return service.organizationUseCase.AcceptInvitation(userID, token)
+}
+
+func (service *organizationService) ListMembers(ctx context.Context, actor *entities.Membership) ([]usecase.Member, error) {
+	return service.organizationUseCase.ListMembers(actor)
+}
+
+func (service *organizationService) UpdateMemberRole(ctx context.Context, actor *entities.Membership, userID ksuid.KSUID, role entities.OrganizationRole) error {
+	return service.organizationUseCase.UpdateMemberRole(actor, userID, role)
+}
+
+func (service *organizationService) RemoveMember(ctx context.Context, actor *entities.Membership, userID ksuid.KSUID) error {
+	return service.organizationUseCase.RemoveMember(actor, userID)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	usecase "github.com/oriastanjung/stellar/internal/usecase/webhook"
+	"github.com/segmentio/ksuid"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, userID ksuid.KSUID, url string, events []entities.WebhookEvent) (*entities.Webhook, string, error)
+	ListWebhooks(ctx context.Context, userID ksuid.KSUID) ([]entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID ksuid.KSUID, webhookID ksuid.KSUID) error
+	ListDeliveries(ctx context.Context, userID ksuid.KSUID, webhookID ksuid.KSUID) ([]entities.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookUseCase usecase.WebhookUseCase
+}
+
+func NewWebhookService(webhookUseCase usecase.WebhookUseCase) WebhookService {
+	return &webhookService{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (service *webhookService) CreateWebhook(ctx context.Context, userID ksuid.KSUID, url string, events []entities.WebhookEvent) (*entities.Webhook, string, error) {
+	return service.webhookUseCase.CreateWebhook(userID, url, events)
+}
+
+func (service *webhookService) ListWebhooks(ctx context.Context, userID ksuid.KSUID) ([]entities.Webhook, error) {
+	return service.webhookUseCase.ListWebhooks(userID)
+}
+
+func (service *webhookService) DeleteWebhook(ctx context.Context, userID ksuid.KSUID, webhookID ksuid.KSUID) error {
+	return service.webhookUseCase.DeleteWebhook(userID, webhookID)
+}
+
+func (service *webhookService) ListDeliveries(ctx context.Context, userID ksuid.KSUID, webhookID ksuid.KSUID) ([]entities.WebhookDelivery, error) {
+	return service.webhookUseCase.ListDeliveries(userID, webhookID)
+}
+
+func (service *webhookService) RedeliverWebhook(ctx context.Context, userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error) {
+	return service.webhookUseCase.RedeliverWebhook(userID, deliveryID)
+}
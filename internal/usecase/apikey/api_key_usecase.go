@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repository "github.com/oriastanjung/stellar/internal/repository/apikey"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	keyPrefix = "stl_"
+	// prefixLength is the hex length of the lookup prefix
+	prefixLength = 8
+	// maxExpiryDays keeps keys from being created with absurd lifetimes,
+	// 0 days means the key never expires
+	maxExpiryDays = 3650
+)
+
+// Authenticator resolves the value of the x-api-key metadata to the key
+// and its owner, used by the token validation interceptor.
+type Authenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, *entities.User, error)
+}
+
+type APIKeyUseCase interface {
+	Authenticator
+	CreateAPIKey(userID ksuid.KSUID, name string, scopes []entities.APIKeyScope, expiresInDays int) (*entities.APIKey, string, error)
+	ListAPIKeys(userID ksuid.KSUID) ([]entities.APIKey, error)
+	RevokeAPIKey(userID ksuid.KSUID, keyID ksuid.KSUID) error
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	clock      utils.Clock
+	ids        utils.IDGenerator
+	cfg        *config.Config
+}
+
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, clock utils.Clock, ids utils.IDGenerator, cfg *config.Config) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		clock:      clock,
+		ids:        ids,
+		cfg:        cfg,
+	}
+}
+
+// CreateAPIKey returns the key in plain text, only its hash is stored so it
+// cannot be shown again.
+func (usecase *apiKeyUseCase) CreateAPIKey(userID ksuid.KSUID, name string, scopes []entities.APIKeyScope, expiresInDays int) (*entities.APIKey, string, error) {
+	cfg := usecase.cfg
+	if expiresInDays < 0 || expiresInDays > maxExpiryDays {
+		return nil, "", apperror.ErrInvalidAPIKeyExpiry.WithMetadata("max_days", fmt.Sprint(maxExpiryDays))
+	}
+
+	granted := []entities.APIKeyScope{}
+	seen := map[entities.APIKeyScope]bool{}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", apperror.ErrUnknownAPIKeyScope.WithMetadata("scope", string(scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, scope)
+		}
+	}
+
+	prefix := make([]byte, prefixLength/2)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, "", apperror.Internal(fmt.Errorf("error generating api key: %w", err))
+	}
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", apperror.Internal(fmt.Errorf("error generating api key: %w", err))
+	}
+	rawKey := keyPrefix + hex.EncodeToString(prefix) + "_" + secret
+
+	key := &entities.APIKey{
+		ID:      usecase.ids.NewID(),
+		UserID:  userID,
+		Name:    strings.TrimSpace(name),
+		Prefix:  hex.EncodeToString(prefix),
+		KeyHash: utils.HashToken(rawKey),
+		Scopes:  granted,
+	}
+	if expiresInDays > 0 {
+		expiresAt := usecase.clock.Now().AddDate(0, 0, expiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := usecase.apiKeyRepo.CreateAPIKey(key, cfg.APIKeyMaxPerUser); err != nil {
+		return nil, "", err
+	}
+	return key, rawKey, nil
+}
+
+func (usecase *apiKeyUseCase) ListAPIKeys(userID ksuid.KSUID) ([]entities.APIKey, error) {
+	return usecase.apiKeyRepo.FindAPIKeys(userID)
+}
+
+func (usecase *apiKeyUseCase) RevokeAPIKey(userID ksuid.KSUID, keyID ksuid.KSUID) error {
+	if err := usecase.apiKeyRepo.DeleteAPIKey(userID, keyID); err != nil {
+		return err
+	}
+	log.Printf("User %s revoked api key %s", userID, keyID)
+	return nil
+}
+
+// Authenticate rejects unknown, expired and mismatching keys with the same
+// error so callers cannot probe which prefixes exist. Last use is saved at
+// most once per API_KEY_LAST_USED_INTERVAL to keep requests read only.
+func (usecase *apiKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, *entities.User, error) {
+	cfg := usecase.cfg
+	rest, ok := strings.CutPrefix(rawKey, keyPrefix)
+	if !ok || len(rest) <= prefixLength || rest[prefixLength] != '_' {
+		return nil, nil, apperror.ErrInvalidAPIKey
+	}
+
+	key, err := usecase.apiKeyRepo.FindAPIKeyByPrefix(rest[:prefixLength])
+	if err != nil {
+		return nil, nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(utils.HashToken(rawKey))) != 1 {
+		return nil, nil, apperror.ErrInvalidAPIKey
+	}
+	now := usecase.clock.Now()
+	if key.Expired(now) {
+		return nil, nil, apperror.ErrInvalidAPIKey.WithMetadata("expired", "true")
+	}
+
+	user, err := usecase.apiKeyRepo.FindUserByID(key.UserID)
+	if err != nil {
+		return nil, nil, apperror.ErrInvalidAPIKey.Wrap(err)
+	}
+	if !user.IsVerified {
+		return nil, nil, apperror.ErrUserNotVerified
+	}
+	if user.DisabledAt != nil {
+		return nil, nil, apperror.ErrAccountDisabled
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= cfg.APIKeyLastUsedInterval {
+		ip := utils.GetClientInfo(ctx).IP
+		if err := usecase.apiKeyRepo.TouchAPIKey(key.ID, now, ip); err != nil {
+			// tracking must not fail the call
+			log.Printf("Error saving last use of api key %s: %v", key.ID, err)
+		} else {
+			key.LastUsedAt, key.LastUsedIP = &now, ip
+		}
+	}
+	return key, user, nil
+}
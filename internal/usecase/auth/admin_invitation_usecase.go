@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	"github.com/segmentio/ksuid"
+)
+
+// RegisterAdminWithToken accepts either the bootstrap token, which only
+// works while no admin exists, or an invitation sent to the user's email.
+func (usecase *authUseCase) RegisterAdminWithToken(user *entities.User, token string) error {
+	cfg := usecase.cfg
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if err := usecase.prepareAdmin(user); err != nil {
+		return err
+	}
+
+	if cfg.AdminBootstrapToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cfg.AdminBootstrapToken)) == 1 {
+		if err := usecase.authRepo.RegisterFirstAdmin(user); err != nil {
+			return err
+		}
+		log.Printf("First admin %s registered with the bootstrap token", user.Email)
+		return nil
+	}
+
+	if err := usecase.authRepo.RegisterAdminWithInvitation(user, utils.HashToken(token)); err != nil {
+		return err
+	}
+	log.Printf("Admin %s registered with an invitation", user.Email)
+	return nil
+}
+
+// CreateAdminInvitation emails a single-use link to sign up as admin, ttl 0
+// uses ADMIN_INVITATION_TTL and ttl above ADMIN_INVITATION_MAX_TTL is refused.
+func (usecase *authUseCase) CreateAdminInvitation(inviterID ksuid.KSUID, email string, ttl time.Duration) (*entities.AdminInvitation, error) {
+	cfg := usecase.cfg
+	if ttl == 0 {
+		ttl = cfg.AdminInvitationTTL
+	}
+	if ttl < 0 || ttl > cfg.AdminInvitationMaxTTL {
+		return nil, apperror.ErrInvalidRequest.WithMetadata("max_ttl", cfg.AdminInvitationMaxTTL.String())
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	// akun yang sudah ada tidak bisa dijadikan admin lewat undangan
+	if _, err := usecase.authRepo.FindUserByEmail(email); err == nil {
+		return nil, apperror.ErrUserAlreadyExists
+	} else if !errors.Is(err, apperror.ErrUserNotFound) {
+		return nil, err
+	}
+	inviter, err := usecase.authRepo.FindUserByID(inviterID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error generating invitation token: %w", err))
+	}
+	msg, err := mailer.Render("admin_invitation", inviter.PreferredLocale, email, map[string]interface{}{
+		"InviterName": inviter.Username,
+		"Link":        cfg.AdminInvitationLink + "/" + token,
+		"ExpiresIn":   i18n.FormatDuration(inviter.PreferredLocale, ttl),
+	})
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	invitation := &entities.AdminInvitation{
+		ID:        usecase.ids.NewID(),
+		Email:     email,
+		TokenHash: utils.HashToken(token),
+		InvitedBy: inviterID,
+		ExpiresAt: usecase.clock.Now().Add(ttl),
+	}
+	if err := usecase.authRepo.CreateAdminInvitation(invitation, mailer.NewOutboxEmail(msg)); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %s invited %s as admin", inviterID, email)
+	return invitation, nil
+}
+
+func (usecase *authUseCase) ListAdminInvitations() ([]entities.AdminInvitation, error) {
+	return usecase.authRepo.FindAdminInvitations()
+}
+
+func (usecase *authUseCase) RevokeAdminInvitation(id ksuid.KSUID) error {
+	return usecase.authRepo.DeleteAdminInvitation(id)
+}
@@ -2,234 +2,213 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
+	"sync"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/oriastanjung/stellar/internal/apperror"
 	"github.com/oriastanjung/stellar/internal/config"
 	"github.com/oriastanjung/stellar/internal/entities"
 	repository "github.com/oriastanjung/stellar/internal/repository/auth"
+	notification "github.com/oriastanjung/stellar/internal/usecase/notification"
 	"github.com/oriastanjung/stellar/internal/utils"
-	"github.com/oriastanjung/stellar/internal/utils/smtp"
-	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"gorm.io/gorm"
+	"github.com/oriastanjung/stellar/internal/utils/oauth"
+	passwordPolicy "github.com/oriastanjung/stellar/internal/utils/password"
+	"github.com/segmentio/ksuid"
 )
 
 type AuthUseCase interface {
-	RegisterAdmin(user *entities.User, passwordSalt int) error
-	LoginAdmin(user *entities.User) (string, error)
-	RegisterUser(user *entities.User, passwordSalt int) error
-	LoginUser(user *entities.User) (string, error)
+	RegisterAdmin(user *entities.User) error
+	RegisterAdminWithToken(user *entities.User, token string) error
+	CreateAdminInvitation(inviterID ksuid.KSUID, email string, ttl time.Duration) (*entities.AdminInvitation, error)
+	ListAdminInvitations() ([]entities.AdminInvitation, error)
+	RevokeAdminInvitation(id ksuid.KSUID) error
+	LoginAdmin(ctx context.Context, user *entities.User) (*LoginResult, error)
+	RegisterUser(user *entities.User) error
+	LoginUser(ctx context.Context, user *entities.User) (*LoginResult, error)
 	VerifyUser(token string) error
 	RequestForgetPassword(token string) error
 	ResetPasswordByToken(token string, password string) error
 	LoginUserViaGoogle(ctx context.Context) (string, error)
-	LoginUserViaGoogleCallback(ctx context.Context, email string, username string, pictureUrl string) (string, error)
+	LoginUserViaGoogleCallback(ctx context.Context, code string, state string) (*LoginResult, error)
+	EnrollTOTP(userID ksuid.KSUID) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID ksuid.KSUID, code string, issueToken bool) ([]string, string, error)
+	VerifyMFA(ctx context.Context, mfaToken string, code string) (string, error)
+	DisableTOTP(userID ksuid.KSUID, code string) error
+	LoginViaProvider(ctx context.Context, providerName string) (string, error)
+	LoginViaProviderCallback(ctx context.Context, providerName string, code string, state string) (*LoginResult, error)
+	StartLinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) (string, error)
+	LinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string, code string, state string) error
+	UnlinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) error
+	ListIdentities(ctx context.Context, userID ksuid.KSUID) ([]entities.UserIdentity, error)
+	UnlockAccount(token string) error
+	ResendVerificationEmail(email string) error
+	UpdatePreferredLocale(userID ksuid.KSUID, locale string) error
+	UpdateSubscription(userID ksuid.KSUID, active bool) error
+	VerifyUserByEmail(email string) error
+	SetUserDisabled(email string, disabled bool) error
+}
+
+// LoginResult carries either an access token or, when the account uses
+// 2FA, the MFA token to exchange through VerifyMFA.
+type LoginResult struct {
+	Token                 string
+	MFARequired           bool
+	MFAEnrollmentRequired bool
+	MFAToken              string
+}
+
+// Dependencies are the utilities the auth usecase calls instead of package
+// globals, cmd builds them from config and tests pass fakes.
+type Dependencies struct {
+	Tokens utils.TokenIssuer
+	Hasher utils.Hasher
+	// Cipher seals the TOTP secrets
+	Cipher    *utils.AESCipher
+	Clock     utils.Clock
+	IDs       utils.IDGenerator
+	Providers *oauth.Registry
 }
 
 type authUseCase struct {
-	authRepo repository.AuthRepository
+	authRepo  repository.AuthRepository
+	notifier  notification.Notifier
+	tokens    utils.TokenIssuer
+	hasher    utils.Hasher
+	cipher    *utils.AESCipher
+	clock     utils.Clock
+	ids       utils.IDGenerator
+	providers *oauth.Registry
+	cfg       *config.Config
+
+	// dummyHash is compared against when the email is unknown
+	dummyHash     string
+	dummyHashOnce sync.Once
 }
 
-func NewAuthUseCase(authRepo repository.AuthRepository) AuthUseCase {
+func NewAuthUseCase(authRepo repository.AuthRepository, notifier notification.Notifier, deps Dependencies, cfg *config.Config) AuthUseCase {
 	return &authUseCase{
-		authRepo: authRepo,
+		authRepo:  authRepo,
+		notifier:  notifier,
+		tokens:    deps.Tokens,
+		hasher:    deps.Hasher,
+		cipher:    deps.Cipher,
+		clock:     deps.Clock,
+		ids:       deps.IDs,
+		providers: deps.Providers,
+		cfg:       cfg,
 	}
 }
 
-func (usecase *authUseCase) RegisterAdmin(user *entities.User, passwordSalt int) error {
-	// 1. Generate ID unik untuk pengguna
-	user.ID = utils.GenerateIDbyKSUID()
-
-	// 2. Set peran pengguna sebagai admin
-	user.Role = string(entities.AdminRole)
-	user.IsVerified = true
-
-	// 3. Hash password untuk keamanan
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), passwordSalt)
-	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error hashing password: %v", err))
+// RegisterAdmin saves an admin without any check of who asks, it is only
+// reachable from the CLI. SignUpAdmin goes through RegisterAdminWithToken.
+func (usecase *authUseCase) RegisterAdmin(user *entities.User) error {
+	if err := usecase.prepareAdmin(user); err != nil {
+		return err
 	}
-	user.Password = string(hashedPassword)
-
-	// 4. Simpan data pengguna ke database melalui repository
 	return usecase.authRepo.RegisterAdmin(user)
 }
 
-func (usecase *authUseCase) LoginAdmin(user *entities.User) (string, error) {
-	dbUser := &entities.User{}
-	dbUser.Email = user.Email
-	err := usecase.authRepo.LoginAdmin(dbUser)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", status.Errorf(codes.NotFound, "User Not Found")
-		}
-		return "", err
-	}
+func (usecase *authUseCase) prepareAdmin(user *entities.User) error {
+	// 1. Generate ID unik untuk pengguna
+	user.ID = usecase.ids.NewID()
 
-	if dbUser.IsVerified == false {
-		return "", status.Errorf(codes.Unauthenticated, "User Not Verified")
-	}
+	// 2. Set peran pengguna sebagai admin
+	user.Role = string(entities.AdminRole)
+	user.IsVerified = true
 
-	if dbUser.Role != string(entities.AdminRole) {
-		return "", errors.New("User Not Admin")
+	// 3. Cek password sesuai policy lalu hash untuk keamanan
+	if err := usecase.validatePassword(user.Password, user.Email, user.Username); err != nil {
+		return err
 	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(user.Password))
+	hashedPassword, err := usecase.hasher.Hash(user.Password)
 	if err != nil {
-		return "", status.Errorf(codes.Unauthenticated, "Invalid Password")
+		return apperror.Internal(fmt.Errorf("error hashing password: %w", err))
 	}
+	user.Password = hashedPassword
+	return nil
+}
 
-	token, err := utils.GenerateTokenJWT(*dbUser)
+func (usecase *authUseCase) LoginAdmin(ctx context.Context, user *entities.User) (*LoginResult, error) {
+	dbUser, err := usecase.checkPassword(user.Email, user.Password, entities.AdminRole)
 	if err != nil {
-		return "", status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
-	return token, nil
+	return usecase.completeLogin(ctx, dbUser)
 }
-func (usecase *authUseCase) RegisterUser(user *entities.User, passwordSalt int) error {
-	cfg := config.LoadEnv()
+func (usecase *authUseCase) RegisterUser(user *entities.User) error {
 	// 1. Generate ID unik untuk pengguna
-	user.ID = utils.GenerateIDbyKSUID()
+	user.ID = usecase.ids.NewID()
 
 	// 2. Set peran pengguna sebagai User
 	user.Role = string(entities.UserRole)
 	user.IsVerified = false
-	verificationToken := uuid.New().String()
-	user.VerificationToken = verificationToken
 
-	// 3. Hash password untuk keamanan
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), passwordSalt)
-	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error hashing password: %v", err))
+	// 3. Cek password sesuai policy lalu hash untuk keamanan
+	if err := usecase.validatePassword(user.Password, user.Email, user.Username); err != nil {
+		return err
 	}
-	user.Password = string(hashedPassword)
-
-	// 3. Kirim email verifikasi// Send verification email asynchronously
-	go func() {
-		err := smtp.SendEmailVerification(user.Email, verificationToken, cfg.EmailVerificationLink)
-		if err != nil {
-			log.Printf("Error sending verification email to %s: %v", user.Email, err)
-		}
-	}()
-
-	// 4. Simpan data pengguna ke database melalui repository
-	return usecase.authRepo.RegisterUser(user)
-}
-
-func (usecase *authUseCase) LoginUser(user *entities.User) (string, error) {
-	dbUser := &entities.User{}
-	dbUser.Email = user.Email
-	err := usecase.authRepo.LoginUser(dbUser)
+	hashedPassword, err := usecase.hasher.Hash(user.Password)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", status.Errorf(codes.NotFound, "User Not Found")
-		}
-		return "", err
+		return apperror.Internal(fmt.Errorf("error hashing password: %w", err))
 	}
+	user.Password = hashedPassword
 
-	if dbUser.IsVerified == false {
-		return "", status.Errorf(codes.Unauthenticated, "User Not Verified")
-	}
-
-	if dbUser.Role != string(entities.UserRole) {
-		return "", status.Errorf(codes.Unauthenticated, ("Account Not User Role"))
-	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(user.Password))
+	// 4. Simpan pengguna bersama token dan email verifikasi dalam satu transaksi
+	token, outboxEmail, err := usecase.newVerificationToken(user)
 	if err != nil {
-		return "", status.Errorf(codes.Unauthenticated, "Invalid Password")
+		return err
 	}
+	return usecase.authRepo.RegisterUserWithToken(user, token, outboxEmail)
+}
 
-	token, err := utils.GenerateTokenJWT(*dbUser)
+func (usecase *authUseCase) LoginUser(ctx context.Context, user *entities.User) (*LoginResult, error) {
+	dbUser, err := usecase.checkPassword(user.Email, user.Password, entities.UserRole)
 	if err != nil {
-		return "", status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
+		return nil, err
 	}
-	return token, nil
+	return usecase.completeLogin(ctx, dbUser)
 }
+
 func (usecase *authUseCase) VerifyUser(token string) error {
-	return usecase.authRepo.VerifyUser(token)
+	return usecase.authRepo.VerifyUser(utils.HashToken(token))
 }
 func (usecase *authUseCase) RequestForgetPassword(email string) error {
-	cfg := config.LoadEnv()
+	cfg := usecase.cfg
 	user, err := usecase.authRepo.FindUserByEmail(email)
 	if err != nil {
-		return status.Errorf(codes.NotFound, "User Not Found")
+		// respons sama seperti email terdaftar agar email tidak bisa ditebak
+		return nil
 	}
-	forgetPasswordToken := uuid.New().String()
-	user.ForgetPasswordToken = forgetPasswordToken
-	err = usecase.authRepo.UpdateUserByEmail(email, user)
+	token, outboxEmail, err := usecase.newUserToken(user, entities.TokenPurposeResetPassword, cfg.ResetPasswordTokenTTL, "forget_password", cfg.EmailForgetPasswordFrontendLink)
 	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error saving user: %v", err))
+		return err
 	}
-	go func() {
-		err := smtp.SendEmailForgetPassword(email, forgetPasswordToken, cfg.EmailForgetPasswordFrontendLink)
-		if err != nil {
-			log.Printf("Error sending forget password email to %s: %v", email, err)
-		}
-	}()
-
-	return nil
+	return usecase.authRepo.CreateUserToken(token, outboxEmail)
 }
 
 func (usecase *authUseCase) ResetPasswordByToken(token string, password string) error {
-	cfg := config.LoadEnv()
-	user, err := usecase.authRepo.FindOneUserByKey("forget_password_token", token)
+	user, err := usecase.authRepo.FindUserByToken(entities.TokenPurposeResetPassword, utils.HashToken(token))
 	if err != nil {
-		return status.Errorf(codes.NotFound, "User Not Found")
+		return err
 	}
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), cfg.BcryptSalt)
-	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error hashing password: %v", err))
+	if err := usecase.validatePassword(password, user.Email, user.Username); err != nil {
+		return err
 	}
-	user.Password = string(hashedPassword)
-	err = usecase.authRepo.UpdateUserByEmail(user.Email, user)
+	hashedPassword, err := usecase.hasher.Hash(password)
 	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Error saving user: %v", err))
+		return apperror.Internal(fmt.Errorf("error hashing password: %w", err))
+	}
+	if err := usecase.authRepo.ResetPasswordByToken(utils.HashToken(token), hashedPassword); err != nil {
+		return err
 	}
-	return nil
-}
 
-func (usecase *authUseCase) LoginUserViaGoogle(ctx context.Context) (string, error) {
-	cfg := config.LoadEnv()
-	googleOauthConfig := utils.GetGoogleOAuthConfig()
-	url := googleOauthConfig.AuthCodeURL(cfg.GoogleOAuthStateString)
-	return string(url), nil
+	usecase.notify(user, entities.NotificationPasswordChanged, map[string]interface{}{
+		"Time": formatTime(usecase.clock.Now()),
+	})
+	return nil
 }
 
-func (usecase *authUseCase) LoginUserViaGoogleCallback(ctx context.Context, email string, username string, pictureUrl string) (string, error) {
-	cfg := config.LoadEnv()
-	user, err := usecase.authRepo.FindUserByEmail(email)
-	if err != nil {
-		var newUser entities.User
-		newUser.ID = utils.GenerateIDbyKSUID()
-		newUser.Email = email
-		newUser.Username = username
-		newUser.ProfilePictureUrl = pictureUrl
-		newUser.IsVerified = true
-		newUser.Role = string(entities.UserRole)
-		hashedPassword, errPassword := bcrypt.GenerateFromPassword([]byte("email:"+email), cfg.BcryptSalt)
-		if errPassword != nil {
-			return "", status.Errorf(codes.Internal, fmt.Sprintf("Error hashing password: %v", errPassword))
-		}
-		newUser.Password = string(hashedPassword)
-
-		errRegister := usecase.authRepo.RegisterUser(&newUser)
-		if errRegister != nil {
-			return "", status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", errRegister))
-		}
-
-		token, err := utils.GenerateTokenJWT(newUser)
-		if err != nil {
-			return "", status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
-		}
-		return token, nil
-	}
-	token, err := utils.GenerateTokenJWT(*user)
-	if err != nil {
-		return "", status.Errorf(codes.Internal, fmt.Sprintf("Error : %v", err))
-	}
-	return token, nil
+func (usecase *authUseCase) validatePassword(password string, email string, username string) error {
+	return passwordPolicy.PolicyFromConfig(usecase.cfg).Validate(password, email, username)
 }
@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repository "github.com/oriastanjung/stellar/internal/repository/auth"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/fake"
+	"github.com/oriastanjung/stellar/internal/utils/oauth"
+)
+
+const testPassword = "correct-horse-battery"
+
+type testAuth struct {
+	usecase  AuthUseCase
+	repo     *repository.MemoryAuthRepository
+	clock    *fake.Clock
+	tokens   *fake.TokenIssuer
+	notifier *fake.Notifier
+	// providers is empty until a test reloads it
+	providers *oauth.Registry
+	cfg       *config.Config
+}
+
+func newTestAuth(t *testing.T) *testAuth {
+	t.Helper()
+	cfg := &config.Config{
+		JWTAccessTokenTTL:               time.Hour,
+		MFAIssuer:                       "Stellar",
+		MFAChallengeTTL:                 5 * time.Minute,
+		AdminMFARequired:                false,
+		EmailVerificationLink:           "https://stellar.test/verify-email",
+		EmailForgetPasswordFrontendLink: "https://stellar.test/reset-password",
+		EmailUnlockAccountLink:          "https://stellar.test/unlock-account",
+		OAuthStateTTL:                   10 * time.Minute,
+		VerificationTokenTTL:            24 * time.Hour,
+		ResetPasswordTokenTTL:           time.Hour,
+		UnlockAccountTokenTTL:           24 * time.Hour,
+		VerificationResendCooldown:      time.Minute,
+		PasswordMinLength:               8,
+		PasswordMaxLength:               72,
+		LoginLockoutThreshold:           3,
+		LoginLockoutBase:                time.Minute,
+		LoginLockoutMax:                 10 * time.Minute,
+	}
+	cipher, err := utils.NewAESCipher("test-key-16bytes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := fake.NewClock(time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+	tokens := fake.NewTokenIssuer(clock, cfg.JWTAccessTokenTTL)
+	notifier := &fake.Notifier{}
+	providers, _ := oauth.NewRegistry(nil)
+	repo := repository.NewMemoryAuthRepository(clock)
+	usecase := NewAuthUseCase(repo, notifier, Dependencies{
+		Tokens:    tokens,
+		Hasher:    fake.Hasher{},
+		Cipher:    cipher,
+		Clock:     clock,
+		IDs:       fake.NewIDs(),
+		Providers: providers,
+	}, cfg)
+	return &testAuth{usecase: usecase, repo: repo, clock: clock, tokens: tokens, notifier: notifier, providers: providers, cfg: cfg}
+}
+
+// register signs up a user and, when verified is set, follows the link of
+// the verification email.
+func (auth *testAuth) register(t *testing.T, email string, verified bool) *entities.User {
+	t.Helper()
+	user := &entities.User{Username: "user", Email: email, Password: testPassword}
+	if err := auth.usecase.RegisterUser(user); err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	if verified {
+		if err := auth.usecase.VerifyUser(auth.emailedToken(t, auth.cfg.EmailVerificationLink)); err != nil {
+			t.Fatalf("VerifyUser: %v", err)
+		}
+	}
+	return user
+}
+
+// emailedToken returns the token of the newest email carrying link.
+func (auth *testAuth) emailedToken(t *testing.T, link string) string {
+	t.Helper()
+	pattern := regexp.MustCompile(regexp.QuoteMeta(link) + `/([A-Za-z0-9_-]+)`)
+	outbox := auth.repo.Outbox()
+	for i := len(outbox) - 1; i >= 0; i-- {
+		if match := pattern.FindStringSubmatch(outbox[i].TextBody); match != nil {
+			return match[1]
+		}
+	}
+	t.Fatalf("no email links to %s", link)
+	return ""
+}
+
+func (auth *testAuth) login(email string, password string) (*LoginResult, error) {
+	return auth.usecase.LoginUser(context.Background(), &entities.User{Email: email, Password: password})
+}
+
+func (auth *testAuth) totpCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := utils.TOTPCode(secret, auth.clock.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func wantError(t *testing.T, got error, want error) {
+	t.Helper()
+	if want == nil && got != nil {
+		t.Fatalf("unexpected error: %v", got)
+	}
+	if want != nil && !errors.Is(got, want) {
+		t.Fatalf("error = %v, want %v", got, want)
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	auth := newTestAuth(t)
+	auth.register(t, "verified@example.com", true)
+	auth.register(t, "unverified@example.com", false)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     error
+	}{
+		{name: "verified user", email: "verified@example.com", password: testPassword},
+		{name: "wrong password", email: "verified@example.com", password: "wrong-password", want: apperror.ErrInvalidCredentials},
+		{name: "unknown email", email: "missing@example.com", password: testPassword, want: apperror.ErrInvalidCredentials},
+		{name: "unverified user", email: "unverified@example.com", password: testPassword, want: apperror.ErrUserNotVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := auth.login(tt.email, tt.password)
+			wantError(t, err, tt.want)
+			if tt.want != nil {
+				return
+			}
+			claims, err := auth.tokens.Verify(result.Token, utils.TokenUseAccess)
+			if err != nil {
+				t.Fatalf("access token does not verify: %v", err)
+			}
+			if claims.Email != tt.email {
+				t.Fatalf("access token is for %s, want %s", claims.Email, tt.email)
+			}
+		})
+	}
+
+	// the first device of an account is trusted without a notification
+	if sent := auth.notifier.Sent(entities.NotificationNewLogin); len(sent) != 0 {
+		t.Fatalf("%d new login notifications after the first login, want 0", len(sent))
+	}
+}
+
+func TestVerificationTokenExpiry(t *testing.T) {
+	auth := newTestAuth(t)
+	user := auth.register(t, "late@example.com", false)
+	expired := auth.emailedToken(t, auth.cfg.EmailVerificationLink)
+
+	auth.clock.Advance(auth.cfg.VerificationTokenTTL + time.Second)
+	wantError(t, auth.usecase.VerifyUser(expired), apperror.ErrInvalidToken)
+
+	wantError(t, auth.usecase.ResendVerificationEmail(user.Email), nil)
+	fresh := auth.emailedToken(t, auth.cfg.EmailVerificationLink)
+	if fresh == expired {
+		t.Fatal("ResendVerificationEmail sent the expired token again")
+	}
+	wantError(t, auth.usecase.VerifyUser(fresh), nil)
+	_, err := auth.login(user.Email, testPassword)
+	wantError(t, err, nil)
+}
+
+func TestResendVerificationEmailCooldown(t *testing.T) {
+	auth := newTestAuth(t)
+	user := auth.register(t, "resend@example.com", false)
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		email     string
+		wantSaved int
+	}{
+		{name: "within the cooldown", advance: 10 * time.Second, email: user.Email, wantSaved: 1},
+		{name: "unknown email", email: "missing@example.com", wantSaved: 1},
+		{name: "after the cooldown", advance: auth.cfg.VerificationResendCooldown, email: user.Email, wantSaved: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth.clock.Advance(tt.advance)
+			// every case answers alike so registered emails cannot be told apart
+			wantError(t, auth.usecase.ResendVerificationEmail(tt.email), nil)
+			if saved := len(auth.repo.Outbox()); saved != tt.wantSaved {
+				t.Fatalf("outbox has %d emails, want %d", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	auth := newTestAuth(t)
+	user := auth.register(t, "locked@example.com", true)
+
+	for range auth.cfg.LoginLockoutThreshold {
+		_, err := auth.login(user.Email, "wrong-password")
+		wantError(t, err, apperror.ErrInvalidCredentials)
+	}
+	// the right password is refused while the account is locked
+	_, err := auth.login(user.Email, testPassword)
+	wantError(t, err, apperror.ErrInvalidCredentials)
+
+	auth.clock.Advance(auth.cfg.LoginLockoutBase + time.Second)
+	_, err = auth.login(user.Email, testPassword)
+	wantError(t, err, nil)
+
+	// the counter was reset, so one more failure does not lock again
+	_, err = auth.login(user.Email, "wrong-password")
+	wantError(t, err, apperror.ErrInvalidCredentials)
+	_, err = auth.login(user.Email, testPassword)
+	wantError(t, err, nil)
+}
+
+func TestUnlockAccount(t *testing.T) {
+	auth := newTestAuth(t)
+	user := auth.register(t, "unlock@example.com", true)
+	for range auth.cfg.LoginLockoutThreshold {
+		auth.login(user.Email, "wrong-password")
+	}
+	token := auth.emailedToken(t, auth.cfg.EmailUnlockAccountLink)
+
+	wantError(t, auth.usecase.UnlockAccount(token), nil)
+	_, err := auth.login(user.Email, testPassword)
+	wantError(t, err, nil)
+	wantError(t, auth.usecase.UnlockAccount(token), apperror.ErrInvalidToken)
+}
+
+func TestMFA(t *testing.T) {
+	auth := newTestAuth(t)
+	ctx := context.Background()
+	user := auth.register(t, "mfa@example.com", true)
+
+	enrollment, err := auth.usecase.EnrollTOTP(user.ID)
+	wantError(t, err, nil)
+	_, _, err = auth.usecase.ConfirmTOTP(ctx, user.ID, "000000", false)
+	wantError(t, err, apperror.ErrInvalidMFACode)
+	recoveryCodes, _, err := auth.usecase.ConfirmTOTP(ctx, user.ID, auth.totpCode(t, enrollment.Secret), false)
+	wantError(t, err, nil)
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("ConfirmTOTP returned %d recovery codes, want %d", len(recoveryCodes), recoveryCodeCount)
+	}
+
+	result, err := auth.login(user.Email, testPassword)
+	wantError(t, err, nil)
+	if !result.MFARequired || result.Token != "" {
+		t.Fatalf("login with 2FA returned %+v, want only an MFA token", result)
+	}
+
+	t.Run("confirmed code cannot be replayed", func(t *testing.T) {
+		_, err := auth.usecase.VerifyMFA(ctx, result.MFAToken, auth.totpCode(t, enrollment.Secret))
+		wantError(t, err, apperror.ErrInvalidMFACode)
+	})
+	t.Run("code of the next step", func(t *testing.T) {
+		auth.clock.Advance(30 * time.Second)
+		code := auth.totpCode(t, enrollment.Secret)
+		token, err := auth.usecase.VerifyMFA(ctx, result.MFAToken, code)
+		wantError(t, err, nil)
+		if _, err := auth.tokens.Verify(token, utils.TokenUseAccess); err != nil {
+			t.Fatalf("VerifyMFA returned an invalid access token: %v", err)
+		}
+		_, err = auth.usecase.VerifyMFA(ctx, result.MFAToken, code)
+		wantError(t, err, apperror.ErrInvalidMFACode)
+	})
+	t.Run("recovery code is single use", func(t *testing.T) {
+		_, err := auth.usecase.VerifyMFA(ctx, result.MFAToken, recoveryCodes[0])
+		wantError(t, err, nil)
+		_, err = auth.usecase.VerifyMFA(ctx, result.MFAToken, recoveryCodes[0])
+		wantError(t, err, apperror.ErrInvalidMFACode)
+	})
+	t.Run("access token is not an MFA token", func(t *testing.T) {
+		access, _ := auth.tokens.IssueAccessToken(*user)
+		_, err := auth.usecase.VerifyMFA(ctx, access, recoveryCodes[1])
+		wantError(t, err, apperror.ErrInvalidMFAToken)
+	})
+	t.Run("expired MFA token", func(t *testing.T) {
+		auth.clock.Advance(auth.cfg.MFAChallengeTTL)
+		_, err := auth.usecase.VerifyMFA(ctx, result.MFAToken, recoveryCodes[1])
+		wantError(t, err, apperror.ErrInvalidMFAToken)
+	})
+	t.Run("disable", func(t *testing.T) {
+		wantError(t, auth.usecase.DisableTOTP(user.ID, recoveryCodes[1]), nil)
+		result, err := auth.login(user.Email, testPassword)
+		wantError(t, err, nil)
+		if result.MFARequired || result.Token == "" {
+			t.Fatalf("login after disabling 2FA returned %+v, want an access token", result)
+		}
+	})
+}
+
+func TestAdminMFAEnrollmentRequired(t *testing.T) {
+	auth := newTestAuth(t)
+	auth.cfg.AdminMFARequired = true
+	admin := &entities.User{Username: "admin", Email: "admin@example.com", Password: testPassword}
+	wantError(t, auth.usecase.RegisterAdmin(admin), nil)
+
+	result, err := auth.usecase.LoginAdmin(context.Background(), &entities.User{Email: admin.Email, Password: testPassword})
+	wantError(t, err, nil)
+	if !result.MFAEnrollmentRequired || result.Token != "" {
+		t.Fatalf("admin login returned %+v, want an enrollment token", result)
+	}
+	if _, err := auth.tokens.Verify(result.MFAToken, utils.TokenUseMFAChallenge); err == nil {
+		t.Fatal("enrollment token is accepted as an MFA challenge token")
+	}
+	_, err = auth.usecase.VerifyMFA(context.Background(), result.MFAToken, "000000")
+	wantError(t, err, apperror.ErrInvalidMFAToken)
+}
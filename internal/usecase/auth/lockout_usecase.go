@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+)
+
+// checkPassword verifies email and password for the given role, counting
+// failures towards the progressive lockout. Every failure returns the same
+// error so responses do not reveal which emails are registered or locked.
+func (usecase *authUseCase) checkPassword(email string, password string, role entities.Role) (*entities.User, error) {
+	dbUser := &entities.User{}
+	dbUser.Email = email
+	var err error
+	if role == entities.AdminRole {
+		err = usecase.authRepo.LoginAdmin(dbUser)
+	} else {
+		err = usecase.authRepo.LoginUser(dbUser)
+	}
+	if err != nil {
+		// samakan waktu respons dengan pengecekan password sungguhan
+		usecase.hasher.Compare(usecase.getDummyHash(), password)
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			return nil, apperror.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// akun terkunci tidak dicek passwordnya sampai waktu kunci habis
+	if dbUser.LockedUntil != nil && usecase.clock.Now().Before(*dbUser.LockedUntil) {
+		return nil, apperror.ErrInvalidCredentials
+	}
+
+	// akun sosial tanpa password hanya bisa login lewat provider
+	if dbUser.NoPassword ||
+		usecase.hasher.Compare(dbUser.Password, password) != nil ||
+		dbUser.Role != string(role) {
+		usecase.recordFailedLogin(dbUser)
+		return nil, apperror.ErrInvalidCredentials
+	}
+
+	if dbUser.FailedLoginAttempts > 0 {
+		if err := usecase.authRepo.ResetFailedLogins(dbUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if dbUser.IsVerified == false {
+		return nil, apperror.ErrUserNotVerified
+	}
+	return dbUser, nil
+}
+
+// recordFailedLogin locks the account once the threshold is reached, the
+// lock doubles with every further failure up to LOGIN_LOCKOUT_MAX.
+func (usecase *authUseCase) recordFailedLogin(user *entities.User) {
+	cfg := usecase.cfg
+	attempts, err := usecase.authRepo.RegisterFailedLogin(user.ID)
+	if err != nil {
+		log.Printf("Error recording failed login for %s: %v", user.Email, err)
+		return
+	}
+	if cfg.LoginLockoutThreshold <= 0 || attempts < cfg.LoginLockoutThreshold {
+		return
+	}
+
+	lockFor := cfg.LoginLockoutMax
+	if shift := attempts - cfg.LoginLockoutThreshold; shift < 32 {
+		lockFor = min(cfg.LoginLockoutBase<<shift, cfg.LoginLockoutMax)
+	}
+	token, outboxEmail, err := usecase.newUserToken(user, entities.TokenPurposeUnlockAccount, cfg.UnlockAccountTokenTTL, "unlock_account", cfg.EmailUnlockAccountLink)
+	if err != nil {
+		log.Printf("Error issuing unlock token for %s: %v", user.Email, err)
+		return
+	}
+	if err := usecase.authRepo.LockUser(user.ID, usecase.clock.Now().Add(lockFor), token, outboxEmail); err != nil {
+		log.Printf("Error locking %s: %v", user.Email, err)
+	}
+}
+
+func (usecase *authUseCase) UnlockAccount(token string) error {
+	return usecase.authRepo.UnlockUserByToken(utils.HashToken(token))
+}
+
+func (usecase *authUseCase) getDummyHash() string {
+	usecase.dummyHashOnce.Do(func() {
+		usecase.dummyHash, _ = usecase.hasher.Hash("stellar-dummy-password")
+	})
+	return usecase.dummyHash
+}
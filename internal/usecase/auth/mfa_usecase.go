@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+	"github.com/skip2/go-qrcode"
+)
+
+const recoveryCodeCount = 10
+
+// TOTPEnrollment is what an authenticator app needs to add the account.
+type TOTPEnrollment struct {
+	OTPAuthURL string
+	Secret     string
+	QRPNG      []byte
+}
+
+// completeLogin issues the access token once the password (or social
+// login) step succeeded, or an MFA token when a second factor is needed.
+func (usecase *authUseCase) completeLogin(ctx context.Context, user *entities.User) (*LoginResult, error) {
+	cfg := usecase.cfg
+	if err := checkEnabled(user); err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := usecase.tokens.Issue(*user, utils.TokenUseMFAChallenge, cfg.MFAChallengeTTL)
+		if err != nil {
+			return nil, apperror.Internal(err)
+		}
+		return &LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	if user.Role == string(entities.AdminRole) && cfg.AdminMFARequired {
+		enrollToken, err := usecase.tokens.Issue(*user, utils.TokenUseMFAEnrollment, cfg.MFAChallengeTTL)
+		if err != nil {
+			return nil, apperror.Internal(err)
+		}
+		return &LoginResult{MFAEnrollmentRequired: true, MFAToken: enrollToken}, nil
+	}
+
+	token, err := usecase.issueAccessToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: token}, nil
+}
+
+func (usecase *authUseCase) EnrollTOTP(userID ksuid.KSUID) (*TOTPEnrollment, error) {
+	cfg := usecase.cfg
+	user, err := usecase.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, apperror.ErrMFAAlreadyEnabled
+	}
+
+	// 1. Generate secret baru, disimpan terenkripsi sampai dikonfirmasi
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error generating secret: %w", err))
+	}
+	sealed, err := usecase.cipher.Seal(secret)
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error sealing secret: %w", err))
+	}
+	user.TOTPSecret = sealed
+	user.TOTPLastUsedStep = 0
+	if err := usecase.authRepo.UpdateUserMFA(user); err != nil {
+		return nil, err
+	}
+
+	// 2. Buat URI dan QR code untuk authenticator app
+	uri := utils.TOTPURI(cfg.MFAIssuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error generating QR code: %w", err))
+	}
+
+	return &TOTPEnrollment{
+		OTPAuthURL: uri,
+		Secret:     secret,
+		QRPNG:      png,
+	}, nil
+}
+
+func (usecase *authUseCase) ConfirmTOTP(ctx context.Context, userID ksuid.KSUID, code string, issueToken bool) ([]string, string, error) {
+	user, err := usecase.authRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user.TOTPEnabled {
+		return nil, "", apperror.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, "", apperror.ErrMFAEnrollmentNotStarted
+	}
+
+	if !usecase.checkTOTP(user, code) {
+		return nil, "", apperror.ErrInvalidMFACode
+	}
+	user.TOTPEnabled = true
+	if err := usecase.authRepo.UpdateUserMFA(user); err != nil {
+		return nil, "", err
+	}
+
+	recoveryCodes, err := usecase.regenerateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// enrollment token dari login admin ditukar dengan access token
+	var token string
+	if issueToken {
+		token, err = usecase.issueAccessToken(ctx, user)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return recoveryCodes, token, nil
+}
+
+func (usecase *authUseCase) VerifyMFA(ctx context.Context, mfaToken string, code string) (string, error) {
+	claims, err := usecase.tokens.Verify(mfaToken, utils.TokenUseMFAChallenge)
+	if err != nil {
+		return "", apperror.ErrInvalidMFAToken
+	}
+
+	user, err := usecase.authRepo.FindUserByID(claims.UserId)
+	if err != nil {
+		return "", apperror.ErrInvalidMFAToken
+	}
+	if !user.TOTPEnabled {
+		return "", apperror.ErrMFANotEnabled
+	}
+
+	if !usecase.checkSecondFactor(user, code) {
+		return "", apperror.ErrInvalidMFACode
+	}
+
+	return usecase.issueAccessToken(ctx, user)
+}
+
+func (usecase *authUseCase) DisableTOTP(userID ksuid.KSUID, code string) error {
+	cfg := usecase.cfg
+	user, err := usecase.authRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return apperror.ErrMFANotEnabled
+	}
+	if user.Role == string(entities.AdminRole) && cfg.AdminMFARequired {
+		return apperror.ErrMFAMandatory
+	}
+	if !usecase.checkSecondFactor(user, code) {
+		return apperror.ErrInvalidMFACode
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastUsedStep = 0
+	if err := usecase.authRepo.UpdateUserMFA(user); err != nil {
+		return err
+	}
+	return usecase.authRepo.ReplaceRecoveryCodes(user.ID, nil)
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code.
+func (usecase *authUseCase) checkSecondFactor(user *entities.User, code string) bool {
+	if usecase.checkTOTP(user, code) {
+		return true
+	}
+	return usecase.authRepo.UseRecoveryCode(user.ID, utils.HashToken(code)) == nil
+}
+
+// checkTOTP validates code and records the matched step so it cannot be
+// replayed, the conditional update rejects a code accepted concurrently.
+func (usecase *authUseCase) checkTOTP(user *entities.User, code string) bool {
+	secret, err := usecase.cipher.Open(user.TOTPSecret)
+	if err != nil {
+		return false
+	}
+	step, ok := utils.ValidateTOTP(secret, code, user.TOTPLastUsedStep, usecase.clock.Now())
+	if !ok {
+		return false
+	}
+	if err := usecase.authRepo.UseTOTPStep(user.ID, step); err != nil {
+		return false
+	}
+	user.TOTPLastUsedStep = step
+	return true
+}
+
+func (usecase *authUseCase) regenerateRecoveryCodes(userID ksuid.KSUID) ([]string, error) {
+	codesPlain := make([]string, 0, recoveryCodeCount)
+	records := make([]entities.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, apperror.Internal(fmt.Errorf("error generating recovery code: %w", err))
+		}
+		codesPlain = append(codesPlain, code)
+		records = append(records, entities.RecoveryCode{
+			ID:       usecase.ids.NewID(),
+			UserID:   userID,
+			CodeHash: utils.HashToken(code),
+		})
+	}
+
+	if err := usecase.authRepo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codesPlain, nil
+}
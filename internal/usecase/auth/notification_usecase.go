@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+// issueAccessToken issues the access token at the end of every login and
+// emails the user when the device has not been seen on the account before.
+func (usecase *authUseCase) issueAccessToken(ctx context.Context, user *entities.User) (string, error) {
+	if err := checkEnabled(user); err != nil {
+		return "", err
+	}
+	token, err := usecase.tokens.IssueAccessToken(*user)
+	if err != nil {
+		return "", apperror.Internal(err)
+	}
+
+	client := utils.GetClientInfo(ctx)
+	now := usecase.clock.Now()
+	newDevice, err := usecase.authRepo.RecordUserDevice(&entities.UserDevice{
+		ID:          usecase.ids.NewID(),
+		UserID:      user.ID,
+		Fingerprint: utils.HashToken(client.UserAgent),
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IP,
+		LastSeenAt:  now,
+	})
+	if err != nil {
+		// login tetap berhasil walaupun device gagal dicatat
+		log.Printf("Error recording device of %s: %v", user.Email, err)
+		return token, nil
+	}
+	if newDevice {
+		usecase.notify(user, entities.NotificationNewLogin, map[string]interface{}{
+			"Time":      formatTime(now),
+			"UserAgent": client.UserAgent,
+			"IPAddress": client.IP,
+		})
+	}
+	return token, nil
+}
+
+func (usecase *authUseCase) UpdateSubscription(userID ksuid.KSUID, active bool) error {
+	user, err := usecase.authRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	changed, err := usecase.authRepo.UpdateSubscriptionStatus(userID, active)
+	if err != nil {
+		return err
+	}
+	if changed {
+		usecase.notify(user, entities.NotificationSubscriptionChanged, map[string]interface{}{
+			"Active": active,
+		})
+	}
+	return nil
+}
+
+// notify never fails the action that triggered the notification.
+func (usecase *authUseCase) notify(user *entities.User, event entities.NotificationEvent, data map[string]interface{}) {
+	if err := usecase.notifier.Notify(user.ID, event, data); err != nil {
+		log.Printf("Error sending %s notification to %s: %v", event, user.Email, err)
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/oauth"
+	"github.com/segmentio/ksuid"
+	"golang.org/x/oauth2"
+)
+
+const googleProvider = "google"
+
+func (usecase *authUseCase) LoginUserViaGoogle(ctx context.Context) (string, error) {
+	return usecase.LoginViaProvider(ctx, googleProvider)
+}
+
+func (usecase *authUseCase) LoginUserViaGoogleCallback(ctx context.Context, code string, state string) (*LoginResult, error) {
+	return usecase.LoginViaProviderCallback(ctx, googleProvider, code, state)
+}
+
+func (usecase *authUseCase) LoginViaProvider(ctx context.Context, providerName string) (string, error) {
+	return usecase.startOAuth(providerName, ksuid.Nil)
+}
+
+func (usecase *authUseCase) LoginViaProviderCallback(ctx context.Context, providerName string, code string, state string) (*LoginResult, error) {
+	identity, savedState, err := usecase.finishOAuth(ctx, providerName, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if savedState.LinkUserID != ksuid.Nil {
+		return nil, apperror.ErrInvalidOAuthState
+	}
+
+	// 1. Identity yang sudah tertaut langsung login
+	linked, err := usecase.authRepo.FindUserIdentity(identity.Provider, identity.Subject)
+	if err != nil && !errors.Is(err, apperror.ErrIdentityNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		user, err := usecase.authRepo.FindUserByID(linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := usecase.clearSyntheticPassword(user); err != nil {
+			return nil, err
+		}
+		return usecase.completeLogin(ctx, user)
+	}
+
+	// 2. Selain itu email harus terverifikasi oleh provider
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, apperror.ErrProviderEmailNotVerified
+	}
+
+	newIdentity := &entities.UserIdentity{
+		ID:       usecase.ids.NewID(),
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	// 3. Tautkan ke akun dengan email yang sama
+	user, err := usecase.authRepo.FindUserByEmail(identity.Email)
+	if err != nil && !errors.Is(err, apperror.ErrUserNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if err := usecase.linkVerifiedEmail(user, identity); err != nil {
+			return nil, err
+		}
+		newIdentity.UserID = user.ID
+		if err := usecase.authRepo.CreateUserIdentity(newIdentity); err != nil {
+			return nil, err
+		}
+		return usecase.completeLogin(ctx, user)
+	}
+
+	// 4. Buat user baru tanpa password
+	var newUser entities.User
+	newUser.ID = usecase.ids.NewID()
+	newUser.Email = identity.Email
+	newUser.Username = identity.Name
+	if newUser.Username == "" {
+		newUser.Username = strings.Split(identity.Email, "@")[0]
+	}
+	newUser.ProfilePictureUrl = identity.Picture
+	newUser.IsVerified = true
+	newUser.Role = string(entities.UserRole)
+	newUser.NoPassword = true
+	newUser.PreferredLocale = i18n.FromContext(ctx)
+	newIdentity.UserID = newUser.ID
+
+	if err := usecase.authRepo.RegisterUserWithIdentity(&newUser, newIdentity); err != nil {
+		return nil, err
+	}
+	return usecase.completeLogin(ctx, &newUser)
+}
+
+func (usecase *authUseCase) StartLinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) (string, error) {
+	return usecase.startOAuth(providerName, userID)
+}
+
+func (usecase *authUseCase) LinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string, code string, state string) error {
+	identity, savedState, err := usecase.finishOAuth(ctx, providerName, code, state)
+	if err != nil {
+		return err
+	}
+	// state harus dibuat oleh user yang sama lewat StartLinkIdentity
+	if savedState.LinkUserID != userID {
+		return apperror.ErrInvalidOAuthState
+	}
+
+	linked, err := usecase.authRepo.FindUserIdentity(identity.Provider, identity.Subject)
+	if err != nil && !errors.Is(err, apperror.ErrIdentityNotFound) {
+		return err
+	}
+	if err == nil {
+		if linked.UserID == userID {
+			return nil
+		}
+		return apperror.ErrIdentityLinkedToAnotherAccount
+	}
+
+	return usecase.authRepo.CreateUserIdentity(&entities.UserIdentity{
+		ID:       usecase.ids.NewID(),
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+}
+
+func (usecase *authUseCase) UnlinkIdentity(ctx context.Context, userID ksuid.KSUID, providerName string) error {
+	user, err := usecase.authRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	identities, err := usecase.authRepo.ListUserIdentities(userID)
+	if err != nil {
+		return err
+	}
+
+	// akun tanpa password harus tetap punya minimal satu cara login
+	if user.NoPassword && len(identities) <= 1 {
+		return apperror.ErrLastLoginMethod
+	}
+	return usecase.authRepo.DeleteUserIdentity(userID, providerName)
+}
+
+func (usecase *authUseCase) ListIdentities(ctx context.Context, userID ksuid.KSUID) ([]entities.UserIdentity, error) {
+	return usecase.authRepo.ListUserIdentities(userID)
+}
+
+// startOAuth stores a per-request state, PKCE verifier and nonce and
+// returns the provider redirect URL.
+func (usecase *authUseCase) startOAuth(providerName string, linkUserID ksuid.KSUID) (string, error) {
+	cfg := usecase.cfg
+	provider, err := usecase.getProvider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", apperror.Internal(fmt.Errorf("error generating state: %w", err))
+	}
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", apperror.Internal(fmt.Errorf("error generating nonce: %w", err))
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	url, err := provider.AuthCodeURL(state, verifier, nonce)
+	if err != nil {
+		return "", apperror.ErrProviderUnavailable.Wrap(err)
+	}
+
+	err = usecase.authRepo.CreateOAuthState(&entities.OAuthState{
+		StateHash:    utils.HashToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    usecase.clock.Now().Add(cfg.OAuthStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// finishOAuth consumes the single-use state and exchanges the code for a verified identity.
+func (usecase *authUseCase) finishOAuth(ctx context.Context, providerName string, code string, state string) (*oauth.Identity, *entities.OAuthState, error) {
+	provider, err := usecase.getProvider(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	savedState, err := usecase.authRepo.ConsumeOAuthState(utils.HashToken(state))
+	if err != nil || savedState.Provider != providerName {
+		return nil, nil, apperror.ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, code, savedState.CodeVerifier, savedState.Nonce)
+	if err != nil {
+		return nil, nil, apperror.ErrProviderLoginFailed.Wrap(err)
+	}
+	if identity.Subject == "" {
+		return nil, nil, apperror.ErrProviderLoginFailed
+	}
+	return identity, savedState, nil
+}
+
+func (usecase *authUseCase) getProvider(providerName string) (oauth.Provider, error) {
+	provider, err := usecase.providers.Get(providerName)
+	if errors.Is(err, oauth.ErrUnknownProvider) {
+		return nil, apperror.ErrUnknownProvider
+	}
+	return provider, err
+}
+
+// linkVerifiedEmail prepares an existing account for a social login with
+// the same verified email. An unverified account may have been registered
+// by someone else with the victim's email, so its password is dropped.
+func (usecase *authUseCase) linkVerifiedEmail(user *entities.User, identity *oauth.Identity) error {
+	changed := false
+
+	if !user.IsVerified {
+		user.Password = ""
+		user.NoPassword = true
+		user.IsVerified = true
+		changed = true
+	}
+	if user.ProfilePictureUrl == "" && identity.Picture != "" {
+		user.ProfilePictureUrl = identity.Picture
+		changed = true
+	}
+
+	if !changed {
+		return usecase.clearSyntheticPassword(user)
+	}
+	if err := usecase.authRepo.UpdateUserByEmail(user.Email, user); err != nil {
+		return err
+	}
+	return usecase.clearSyntheticPassword(user)
+}
+
+// clearSyntheticPassword removes the guessable "email:<email>" password
+// older Google sign-ups were created with.
+func (usecase *authUseCase) clearSyntheticPassword(user *entities.User) error {
+	if user.NoPassword {
+		return nil
+	}
+	if usecase.hasher.Compare(user.Password, "email:"+user.Email) != nil {
+		return nil
+	}
+	user.Password = ""
+	user.NoPassword = true
+	return usecase.authRepo.UpdateUserByEmail(user.Email, user)
+}
@@ -0,0 +1,317 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	oidcProvider = "acme"
+	oidcClientID = "stellar"
+)
+
+// oidcProfile is what the fake issuer puts in the ID token.
+type oidcProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// oidcGrant is an authorization code handed out by the fake issuer.
+type oidcGrant struct {
+	challenge string
+	nonce     string
+	profile   oidcProfile
+}
+
+// fakeOIDC is an OpenID Connect issuer with discovery, JWKS and a token
+// endpoint that checks the PKCE verifier, codes are granted by approve
+// instead of a login page.
+type fakeOIDC struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]oidcGrant
+}
+
+func newFakeOIDC(t *testing.T) *fakeOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &fakeOIDC{key: key, grants: map[string]oidcGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/keys", issuer.keys)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func (issuer *fakeOIDC) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                issuer.URL,
+		"authorization_endpoint":                issuer.URL + "/authorize",
+		"token_endpoint":                        issuer.URL + "/token",
+		"jwks_uri":                              issuer.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (issuer *fakeOIDC) keys(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &issuer.key.PublicKey,
+		KeyID:     "test",
+		Algorithm: "RS256",
+		Use:       "sig",
+	}}})
+}
+
+func (issuer *fakeOIDC) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	issuer.mu.Lock()
+	grant, ok := issuer.grants[r.PostForm.Get("code")]
+	delete(issuer.grants, r.PostForm.Get("code"))
+	issuer.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            issuer.URL,
+		"aud":            oidcClientID,
+		"sub":            grant.profile.Subject,
+		"email":          grant.profile.Email,
+		"email_verified": grant.profile.EmailVerified,
+		"nonce":          grant.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	})
+	idToken.Header["kid"] = "test"
+	signed, _ := idToken.SignedString(issuer.key)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// approve plays the user signing in at the authorization URL, it returns
+// the code and the state the provider redirects back with.
+func (issuer *fakeOIDC) approve(t *testing.T, authURL string, profile oidcProfile) (string, string) {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization URL %s does not use PKCE", authURL)
+	}
+	if query.Get("nonce") == "" || query.Get("state") == "" {
+		t.Fatalf("authorization URL %s has no state or nonce", authURL)
+	}
+
+	code := ksuid.New().String()
+	issuer.mu.Lock()
+	issuer.grants[code] = oidcGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), profile: profile}
+	issuer.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func newOIDCTestAuth(t *testing.T) (*testAuth, *fakeOIDC) {
+	t.Helper()
+	auth := newTestAuth(t)
+	issuer := newFakeOIDC(t)
+	err := auth.providers.Reload([]config.OAuthProviderConfig{{
+		Name:         oidcProvider,
+		Type:         "oidc",
+		Issuer:       issuer.URL,
+		ClientID:     oidcClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "https://stellar.test/auth/acme/callback",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth, issuer
+}
+
+func (auth *testAuth) startOIDCLogin(t *testing.T) string {
+	t.Helper()
+	authURL, err := auth.usecase.LoginViaProvider(context.Background(), oidcProvider)
+	if err != nil {
+		t.Fatalf("LoginViaProvider: %v", err)
+	}
+	return authURL
+}
+
+func TestLoginViaOIDC(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile oidcProfile
+		want    error
+	}{
+		{name: "verified email", profile: oidcProfile{Subject: "1001", Email: "sari@example.com", EmailVerified: true}},
+		{name: "unverified email", profile: oidcProfile{Subject: "1002", Email: "sari@example.com"}, want: apperror.ErrProviderEmailNotVerified},
+		{name: "no email", profile: oidcProfile{Subject: "1003", EmailVerified: true}, want: apperror.ErrProviderEmailNotVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, issuer := newOIDCTestAuth(t)
+			code, state := issuer.approve(t, auth.startOIDCLogin(t), tt.profile)
+
+			result, err := auth.usecase.LoginViaProviderCallback(context.Background(), oidcProvider, code, state)
+			wantError(t, err, tt.want)
+			if tt.want != nil {
+				if _, err := auth.repo.FindUserByEmail(tt.profile.Email); !errors.Is(err, apperror.ErrUserNotFound) {
+					t.Fatalf("a failed social login created an account: %v", err)
+				}
+				return
+			}
+			if result.Token == "" {
+				t.Fatalf("LoginViaProviderCallback returned %+v, want an access token", result)
+			}
+			user, err := auth.repo.FindUserByEmail(tt.profile.Email)
+			wantError(t, err, nil)
+			if !user.IsVerified || !user.NoPassword {
+				t.Fatalf("new social user IsVerified = %t, NoPassword = %t", user.IsVerified, user.NoPassword)
+			}
+			identity, err := auth.repo.FindUserIdentity(oidcProvider, tt.profile.Subject)
+			wantError(t, err, nil)
+			if identity.UserID != user.ID {
+				t.Fatalf("identity belongs to %s, want %s", identity.UserID, user.ID)
+			}
+
+			// the linked identity logs in again even without email_verified
+			code, state = issuer.approve(t, auth.startOIDCLogin(t), oidcProfile{Subject: tt.profile.Subject})
+			_, err = auth.usecase.LoginViaProviderCallback(context.Background(), oidcProvider, code, state)
+			wantError(t, err, nil)
+		})
+	}
+}
+
+func TestOIDCState(t *testing.T) {
+	profile := oidcProfile{Subject: "2001", Email: "dewi@example.com", EmailVerified: true}
+	ctx := context.Background()
+
+	t.Run("state is single use", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		code, state := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, nil)
+		_, err = auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+	})
+	t.Run("unknown state", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		code, _ := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, "forged-state")
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+	})
+	t.Run("expired state", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		code, state := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		auth.clock.Advance(auth.cfg.OAuthStateTTL + time.Second)
+		_, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+	})
+	t.Run("state of a link is not a login", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		user := auth.register(t, "linker@example.com", true)
+		authURL, err := auth.usecase.StartLinkIdentity(ctx, user.ID, oidcProvider)
+		wantError(t, err, nil)
+		code, state := issuer.approve(t, authURL, profile)
+		_, err = auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, apperror.ErrInvalidOAuthState)
+	})
+}
+
+func TestOIDCCodeExchange(t *testing.T) {
+	profile := oidcProfile{Subject: "3001", Email: "rina@example.com", EmailVerified: true}
+	ctx := context.Background()
+
+	t.Run("code of another login fails the PKCE check", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		stolenCode, _ := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, ownState := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, stolenCode, ownState)
+		wantError(t, err, apperror.ErrProviderLoginFailed)
+	})
+	t.Run("code is single use", func(t *testing.T) {
+		auth, issuer := newOIDCTestAuth(t)
+		code, state := issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, nil)
+		_, state = issuer.approve(t, auth.startOIDCLogin(t), profile)
+		_, err = auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+		wantError(t, err, apperror.ErrProviderLoginFailed)
+	})
+	t.Run("unknown provider", func(t *testing.T) {
+		auth, _ := newOIDCTestAuth(t)
+		_, err := auth.usecase.LoginViaProvider(ctx, "gitlab")
+		wantError(t, err, apperror.ErrUnknownProvider)
+	})
+}
+
+func TestOIDCLinksVerifiedEmailToExistingAccount(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		verified     bool
+		wantPassword bool
+	}{
+		{name: "verified account keeps its password", verified: true, wantPassword: true},
+		// someone else may have registered the email, the provider proved ownership
+		{name: "unverified account loses its password", verified: false, wantPassword: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, issuer := newOIDCTestAuth(t)
+			user := auth.register(t, "existing@example.com", tt.verified)
+			code, state := issuer.approve(t, auth.startOIDCLogin(t), oidcProfile{Subject: "4001", Email: user.Email, EmailVerified: true})
+
+			result, err := auth.usecase.LoginViaProviderCallback(ctx, oidcProvider, code, state)
+			wantError(t, err, nil)
+			claims, err := auth.tokens.Verify(result.Token, utils.TokenUseAccess)
+			wantError(t, err, nil)
+			if claims.UserId != user.ID {
+				t.Fatalf("social login signed in %s, want the existing account %s", claims.UserId, user.ID)
+			}
+
+			_, err = auth.login(user.Email, testPassword)
+			if (err == nil) != tt.wantPassword {
+				t.Fatalf("password login after linking = %v, want password kept %t", err, tt.wantPassword)
+			}
+		})
+	}
+}
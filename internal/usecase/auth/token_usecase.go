@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	"github.com/segmentio/ksuid"
+)
+
+// newUserToken creates a single-use token and the outbox email carrying its
+// link, the caller saves both in one transaction. Only the hash is stored.
+func (usecase *authUseCase) newUserToken(user *entities.User, purpose entities.TokenPurpose, ttl time.Duration, templateName string, baseLink string) (*entities.UserToken, *entities.OutboxEmail, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, nil, apperror.Internal(fmt.Errorf("error generating token: %w", err))
+	}
+	msg, err := mailer.Render(templateName, user.PreferredLocale, user.Email, map[string]interface{}{
+		"Link":      baseLink + "/" + token,
+		"ExpiresIn": i18n.FormatDuration(user.PreferredLocale, ttl),
+	})
+	if err != nil {
+		return nil, nil, apperror.Internal(err)
+	}
+
+	userToken := &entities.UserToken{
+		ID:        usecase.ids.NewID(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: usecase.clock.Now().Add(ttl),
+	}
+	return userToken, mailer.NewOutboxEmail(msg), nil
+}
+
+func (usecase *authUseCase) newVerificationToken(user *entities.User) (*entities.UserToken, *entities.OutboxEmail, error) {
+	cfg := usecase.cfg
+	return usecase.newUserToken(user, entities.TokenPurposeVerification, cfg.VerificationTokenTTL, "verification", cfg.EmailVerificationLink)
+}
+
+func (usecase *authUseCase) ResendVerificationEmail(email string) error {
+	cfg := usecase.cfg
+	user, err := usecase.authRepo.FindUserByEmail(email)
+	if err != nil || user.IsVerified {
+		// respons sama seperti email terdaftar agar email tidak bisa ditebak
+		return nil
+	}
+
+	// selama cooldown juga tidak ada error, kalau tidak email yang belum
+	// diverifikasi bisa dibedakan dari yang tidak terdaftar
+	latest, err := usecase.authRepo.FindLatestUserToken(user.ID, entities.TokenPurposeVerification)
+	if err == nil && usecase.clock.Now().Sub(latest.CreatedAt) < cfg.VerificationResendCooldown {
+		log.Printf("Verification email for user %s not resent, still within the cooldown", user.ID)
+		return nil
+	}
+
+	token, outboxEmail, err := usecase.newVerificationToken(user)
+	if err != nil {
+		return err
+	}
+	return usecase.authRepo.CreateUserToken(token, outboxEmail)
+}
+
+func (usecase *authUseCase) UpdatePreferredLocale(userID ksuid.KSUID, locale string) error {
+	if i18n.Normalize(locale) != locale {
+		return apperror.ErrInvalidRequest.WithMetadata("locale", locale)
+	}
+	return usecase.authRepo.UpdatePreferredLocale(userID, locale)
+}
@@ -2,32 +2,82 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/chai2010/webp"
+	"github.com/oriastanjung/stellar/internal/apperror"
 	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/metrics"
+	repository "github.com/oriastanjung/stellar/internal/repository/image"
+	notification "github.com/oriastanjung/stellar/internal/usecase/notification"
+	webhook "github.com/oriastanjung/stellar/internal/usecase/webhook"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/circuitbreaker"
+	"github.com/segmentio/ksuid"
 )
 
 // ImageUseCase defines the contract for your core logic.
 type ImageUseCase interface {
-	GenerateImage(prompt string) (string, string, error)
-	DownloadAndSaveImages(imageURL, filename string) error
+	// organizationID is ksuid.Nil for the personal library of the user.
+	GenerateImage(userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (string, string, error)
+	GenerateImageAsync(userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (*entities.ImageJob, error)
+	GetImageJob(userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error)
+	ListImageJobs(userID ksuid.KSUID, organizationID ksuid.KSUID, pageSize int, pageToken string) ([]entities.ImageJob, string, error)
+	DownloadAndSaveImages(userID ksuid.KSUID, imageURL, filename string) error
+	// Drain waits for the async jobs already started, or until ctx is done.
+	Drain(ctx context.Context) error
 }
 
 // imageUseCase is the concrete struct implementing the ImageUseCase interface.
-type imageUseCase struct{}
+type imageUseCase struct {
+	imageRepo repository.ImageRepository
+	notifier  notification.Notifier
+	webhooks  webhook.Dispatcher
+	// upstream stops calling the generation API while it keeps failing
+	upstream *circuitbreaker.Breaker
+	// both clients time out so a stalled upstream cannot hold a worker, or
+	// Drain during shutdown, forever
+	client    *http.Client
+	downloads *http.Client
+	clock     utils.Clock
+	ids       utils.IDGenerator
+	cfg       *config.Config
+	// workers limits how many async jobs call the upstream API at once
+	workers chan struct{}
+	// running counts the async jobs still in flight for Drain
+	running sync.WaitGroup
+}
 
 // NewImageUseCase creates a new instance of imageUseCase.
-func NewImageUseCase() ImageUseCase {
-	return &imageUseCase{}
+func NewImageUseCase(imageRepo repository.ImageRepository, notifier notification.Notifier, webhooks webhook.Dispatcher, upstream *circuitbreaker.Breaker, clock utils.Clock, ids utils.IDGenerator, cfg *config.Config) ImageUseCase {
+	return &imageUseCase{
+		imageRepo: imageRepo,
+		notifier:  notifier,
+		webhooks:  webhooks,
+		upstream:  upstream,
+		client:    &http.Client{Timeout: cfg.ImageAPITimeout},
+		downloads: &http.Client{Timeout: cfg.ImageDownloadTimeout},
+		clock:     clock,
+		ids:       ids,
+		cfg:       cfg,
+		workers:   make(chan struct{}, max(cfg.ImageGenerationWorkers, 1)),
+	}
 }
 
 // Message represents a single message in the request body.
@@ -73,9 +123,15 @@ type RequestBody struct {
 	Domains               interface{}            `json:"domains"`
 }
 
-// GenerateImage calls an external API to produce an image URL/filename based on the prompt.
-func (uc *imageUseCase) GenerateImage(prompt string) (string, string, error) {
-	cfg := config.LoadEnv()
+// NewUpstreamBreaker guards the image generation API, its state is part of
+// the readiness of the image service.
+func NewUpstreamBreaker(cfg *config.Config, clock utils.Clock) *circuitbreaker.Breaker {
+	return circuitbreaker.New("image generation API", cfg.ImageAPIBreakerThreshold, cfg.ImageAPIBreakerCooldown, clock)
+}
+
+// generate calls an external API to produce an image URL/filename based on the prompt.
+func (uc *imageUseCase) generate(prompt string) (string, string, error) {
+	cfg := uc.cfg
 
 	// Build the request body
 	requestPayload := RequestBody{
@@ -99,13 +155,13 @@ func (uc *imageUseCase) GenerateImage(prompt string) (string, string, error) {
 	// Serialize the body to JSON
 	jsonData, err := json.Marshal(requestPayload)
 	if err != nil {
-		return "", "", fmt.Errorf("error marshaling JSON: %w", err)
+		return "", "", apperror.Internal(fmt.Errorf("error marshaling JSON: %w", err))
 	}
 
 	// Create a new HTTP request
 	req, err := http.NewRequest("POST", cfg.IMAGE_API_GENERATION_URL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", "", fmt.Errorf("error creating request: %w", err)
+		return "", "", apperror.Internal(fmt.Errorf("error creating request: %w", err))
 	}
 
 	// Set up headers
@@ -115,35 +171,82 @@ func (uc *imageUseCase) GenerateImage(prompt string) (string, string, error) {
 	referrer := fmt.Sprintf("%s/agent/%s", cfg.IMAGE_API_GENERATION_ORIGIN, cfg.IMAGE_GENERATION_MODEL)
 	req.Header.Set("referer", referrer)
 
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Send the request, unless the upstream has been failing
+	provider := uc.provider()
+	if err := uc.upstream.Allow(); err != nil {
+		metrics.UpstreamErrors.WithLabelValues(provider, "circuit_open").Inc()
+		return "", "", apperror.ErrImageGenerationUnavailable.Wrap(err)
+	}
+	started := time.Now()
+	observe := func(outcome string) {
+		metrics.GenerationDuration.WithLabelValues(provider, outcome).Observe(time.Since(started).Seconds())
+	}
+	resp, err := uc.client.Do(req)
 	if err != nil {
-		return "", "", fmt.Errorf("error sending request: %w", err)
+		uc.upstream.Record(err)
+		observe("error")
+		metrics.UpstreamErrors.WithLabelValues(provider, transportErrorClass(err)).Inc()
+		return "", "", apperror.ErrImageGenerationFailed.Wrap(fmt.Errorf("error sending request: %w", err))
 	}
 	defer resp.Body.Close()
+	// hanya error server yang dihitung, respons yang tidak bisa diparse bisa karena prompt
+	if resp.StatusCode >= http.StatusInternalServerError {
+		err := fmt.Errorf("upstream responded %d", resp.StatusCode)
+		uc.upstream.Record(err)
+		observe("error")
+		metrics.UpstreamErrors.WithLabelValues(provider, "status_5xx").Inc()
+		return "", "", apperror.ErrImageGenerationFailed.Wrap(err)
+	}
+	uc.upstream.Record(nil)
+	if resp.StatusCode >= http.StatusBadRequest {
+		metrics.UpstreamErrors.WithLabelValues(provider, "status_4xx").Inc()
+	}
 
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", "", fmt.Errorf("error reading response: %w", err)
+		observe("error")
+		metrics.UpstreamErrors.WithLabelValues(provider, "read").Inc()
+		return "", "", apperror.ErrImageGenerationFailed.Wrap(fmt.Errorf("error reading response: %w", err))
 	}
 
 	// Extract the image URL and filename
 	imageURL, filename, err := uc.extractImageURLAndFilename(string(respBody))
 	if err != nil {
-		return "", "", err
+		observe("parse_failure")
+		return "", "", apperror.ErrImageGenerationFailed.Wrap(err)
 	}
 
+	observe("success")
 	return imageURL, filename, nil
 }
 
+// provider labels the generation metrics, the model or else the API host.
+func (uc *imageUseCase) provider() string {
+	if uc.cfg.IMAGE_GENERATION_MODEL != "" {
+		return uc.cfg.IMAGE_GENERATION_MODEL
+	}
+	if parsed, err := url.Parse(uc.cfg.IMAGE_API_GENERATION_URL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return "unknown"
+}
+
+func transportErrorClass(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	return "transport"
+}
+
 // extractImageURLAndFilename is a private helper function to parse the
 // response body and retrieve the image URL + filename from markdown syntax (![](...)).
 func (uc *imageUseCase) extractImageURLAndFilename(respBody string) (string, string, error) {
 	// Step 1: Find the start of the image URL using "![]("
 	startIndex := strings.Index(respBody, "![](")
 	if startIndex == -1 {
+		metrics.ParseFailures.WithLabelValues("no_image").Inc()
 		return "", "", fmt.Errorf("image URL not found in response body")
 	}
 	startIndex += len("![](")
@@ -151,6 +254,7 @@ func (uc *imageUseCase) extractImageURLAndFilename(respBody string) (string, str
 	// Step 2: Find the closing parenthesis ')'
 	endIndex := strings.Index(respBody[startIndex:], ")")
 	if endIndex == -1 {
+		metrics.ParseFailures.WithLabelValues("unclosed").Inc()
 		return "", "", fmt.Errorf("closing parenthesis for image URL not found")
 	}
 	endIndex += startIndex
@@ -161,6 +265,7 @@ func (uc *imageUseCase) extractImageURLAndFilename(respBody string) (string, str
 	// Step 4: Extract the filename
 	lastSlashIndex := strings.LastIndex(imageURL, "/")
 	if lastSlashIndex == -1 || !strings.HasSuffix(imageURL, ".jpeg") {
+		metrics.ParseFailures.WithLabelValues("invalid_url").Inc()
 		return "", "", fmt.Errorf("invalid image URL format: %s", imageURL)
 	}
 	filenameWithExt := imageURL[lastSlashIndex+1:] // e.g. "example123.jpeg"
@@ -169,57 +274,104 @@ func (uc *imageUseCase) extractImageURLAndFilename(respBody string) (string, str
 	return imageURL, filename, nil
 }
 
-func (uc *imageUseCase) DownloadAndSaveImages(imageURL, baseFileName string) error {
+func (uc *imageUseCase) DownloadAndSaveImages(userID ksuid.KSUID, imageURL, baseFileName string) error {
+	if err := uc.saveImages(imageURL, uc.cfg.ImageStorageDir, baseFileName); err != nil {
+		return err
+	}
+	uc.dispatch(userID, entities.WebhookImageDownloaded, map[string]interface{}{
+		"image_url": imageURL,
+		"filename":  baseFileName,
+		"formats":   []string{"jpeg", "webp"},
+	})
+	return nil
+}
+
+// GenerateToDir generates the image and saves it in dir without recording
+// a job, used by `stellar generate` to run the pipeline without a server
+// or database.
+func GenerateToDir(cfg *config.Config, prompt string, dir string) (string, string, error) {
+	uc := &imageUseCase{
+		cfg:       cfg,
+		upstream:  NewUpstreamBreaker(cfg, utils.SystemClock{}),
+		client:    &http.Client{Timeout: cfg.ImageAPITimeout},
+		downloads: &http.Client{Timeout: cfg.ImageDownloadTimeout},
+	}
+	imageURL, filename, err := uc.generate(prompt)
+	if err != nil {
+		return "", "", err
+	}
+	if err := uc.saveImages(imageURL, dir, filename); err != nil {
+		return "", "", err
+	}
+	return imageURL, filename, nil
+}
+
+// saveImages downloads the image and saves it in dir as JPEG and WebP.
+func (uc *imageUseCase) saveImages(imageURL string, dir string, baseFileName string) error {
 	// 1. Get the file from the URL
-	resp, err := http.Get(imageURL)
+	resp, err := uc.downloads.Get(imageURL)
 	if err != nil {
-		return fmt.Errorf("failed to download image: %w", err)
+		return apperror.ErrImageDownloadFailed.Wrap(fmt.Errorf("failed to download image: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return apperror.ErrImageDownloadFailed.Wrap(fmt.Errorf("unexpected status code: %d", resp.StatusCode))
 	}
 
 	// 2. Decode the image (any format supported by Go’s image package)
-	img, _, err := image.Decode(resp.Body)
+	img, _, err := image.Decode(&countingReader{reader: resp.Body})
 	if err != nil {
-		return fmt.Errorf("failed to decode image: %w", err)
+		return apperror.ErrImageDownloadFailed.Wrap(fmt.Errorf("failed to decode image: %w", err))
 	}
 
-	// 3. Create public folder if it doesn’t exist
-	if err := os.MkdirAll("../public", 0755); err != nil {
-		return fmt.Errorf("failed to create public folder: %w", err)
+	// 3. Create the folder if it doesn’t exist
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return apperror.Internal(fmt.Errorf("failed to create %s folder: %w", dir, err))
 	}
 
 	// 4. Save as JPEG
-	jpegPath := fmt.Sprintf("../public/%s.jpeg", baseFileName)
+	jpegPath := filepath.Join(dir, baseFileName+".jpeg")
 	outJpeg, err := os.Create(jpegPath)
 	if err != nil {
-		return fmt.Errorf("failed to create JPEG file: %w", err)
+		return apperror.Internal(fmt.Errorf("failed to create JPEG file: %w", err))
 	}
 	defer outJpeg.Close()
 
 	// Encode image to JPEG
+	started := time.Now()
 	if err = jpeg.Encode(outJpeg, img, nil); err != nil {
-		return fmt.Errorf("failed to encode JPEG: %w", err)
+		return apperror.Internal(fmt.Errorf("failed to encode JPEG: %w", err))
 	}
+	metrics.EncodeDuration.WithLabelValues("jpeg").Observe(time.Since(started).Seconds())
 	log.Printf("Saved JPEG to %s\n", jpegPath)
 
 	// 5. Save as WebP
-	webpPath := fmt.Sprintf("../public/%s.webp", baseFileName)
+	webpPath := filepath.Join(dir, baseFileName+".webp")
 	outWebp, err := os.Create(webpPath)
 	if err != nil {
-		return fmt.Errorf("failed to create WebP file: %w", err)
+		return apperror.Internal(fmt.Errorf("failed to create WebP file: %w", err))
 	}
 	defer outWebp.Close()
 
 	// Encode image to WebP (adjust Options for quality, lossless, etc.)
 	// Quality can be 0-100, with 75-90 typically decent.
+	started = time.Now()
 	if err = webp.Encode(outWebp, img, &webp.Options{Lossless: false, Quality: 80}); err != nil {
-		return fmt.Errorf("failed to encode WebP: %w", err)
+		return apperror.Internal(fmt.Errorf("failed to encode WebP: %w", err))
 	}
+	metrics.EncodeDuration.WithLabelValues("webp").Observe(time.Since(started).Seconds())
 	log.Printf("Saved WebP to %s\n", webpPath)
-
 	return nil
 }
+
+// countingReader adds what is read to the downloaded bytes metric.
+type countingReader struct {
+	reader io.Reader
+}
+
+func (counter *countingReader) Read(p []byte) (int, error) {
+	n, err := counter.reader.Read(p)
+	metrics.DownloadBytes.Add(float64(n))
+	return n, err
+}
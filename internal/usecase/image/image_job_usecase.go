@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/lifecycle"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// GenerateImage generates an image while the caller waits, the generation
+// is recorded as a job so it counts towards the quota.
+func (uc *imageUseCase) GenerateImage(userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (string, string, error) {
+	job, err := uc.reserveJob(userID, organizationID, prompt, false)
+	if err != nil {
+		return "", "", err
+	}
+	imageURL, filename, err := uc.generate(prompt)
+	uc.finishJob(job, imageURL, filename, err)
+	return imageURL, filename, err
+}
+
+// GenerateImageAsync queues the generation and returns the pending job, the
+// user is emailed when it finishes or fails.
+func (uc *imageUseCase) GenerateImageAsync(userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string) (*entities.ImageJob, error) {
+	job, err := uc.reserveJob(userID, organizationID, prompt, true)
+	if err != nil {
+		return nil, err
+	}
+	queued := *job
+	uc.running.Add(1)
+	go func() {
+		defer uc.running.Done()
+		uc.runJob(job)
+	}()
+	return &queued, nil
+}
+
+// Drain is called on shutdown after the gRPC server stopped, so no new
+// jobs are queued while it waits. Jobs still running at the deadline are
+// left as running.
+func (uc *imageUseCase) Drain(ctx context.Context) error {
+	return lifecycle.Wait(ctx, &uc.running)
+}
+
+func (uc *imageUseCase) GetImageJob(userID ksuid.KSUID, organizationID ksuid.KSUID, jobID ksuid.KSUID) (*entities.ImageJob, error) {
+	return uc.imageRepo.FindJob(userID, organizationID, jobID)
+}
+
+// ListImageJobs pages through the library newest first, the returned token
+// is empty on the last page.
+func (uc *imageUseCase) ListImageJobs(userID ksuid.KSUID, organizationID ksuid.KSUID, pageSize int, pageToken string) ([]entities.ImageJob, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+	before := ksuid.Nil
+	if pageToken != "" {
+		id, err := ksuid.Parse(pageToken)
+		if err != nil {
+			return nil, "", apperror.ErrInvalidRequest.WithMetadata("page_token", pageToken)
+		}
+		before = id
+	}
+
+	// satu baris tambahan untuk mengetahui apakah masih ada halaman berikutnya
+	jobs, err := uc.imageRepo.ListJobs(userID, organizationID, pageSize+1, before)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(jobs) <= pageSize {
+		return jobs, "", nil
+	}
+	jobs = jobs[:pageSize]
+	return jobs, jobs[pageSize-1].ID.String(), nil
+}
+
+// reserveJob saves the job if the user, or the organization it is generated
+// for, has quota left and warns the user once usage reaches
+// IMAGE_GENERATION_QUOTA_WARNING_PERCENT.
+func (uc *imageUseCase) reserveJob(userID ksuid.KSUID, organizationID ksuid.KSUID, prompt string, async bool) (*entities.ImageJob, error) {
+	cfg := uc.cfg
+	job := &entities.ImageJob{
+		ID:     uc.ids.NewID(),
+		UserID: userID,
+		Status: entities.ImageJobRunning,
+		Async:  async,
+		Prompt: prompt,
+	}
+	if async {
+		job.Status = entities.ImageJobPending
+	}
+
+	quota := int64(cfg.ImageGenerationQuota)
+	if organizationID != ksuid.Nil {
+		organization, err := uc.imageRepo.FindOrganization(organizationID)
+		if err != nil {
+			return nil, err
+		}
+		job.OrganizationID = &organization.ID
+		quota = int64(organization.GenerationQuota)
+	}
+
+	used, err := uc.imageRepo.ReserveJob(job, int(quota), uc.clock.Now().Add(-cfg.ImageGenerationQuotaPeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	if quota > 0 && cfg.ImageGenerationQuotaWarning > 0 {
+		// dibulatkan ke atas supaya peringatan tidak terlewat pada kuota kecil
+		threshold := (quota*int64(cfg.ImageGenerationQuotaWarning) + 99) / 100
+		if used == threshold {
+			uc.notify(userID, entities.NotificationQuotaNearlyExhausted, map[string]interface{}{
+				"Used":       used,
+				"Quota":      quota,
+				"PeriodDays": int(cfg.ImageGenerationQuotaPeriod.Hours() / 24),
+			})
+		}
+	}
+	return job, nil
+}
+
+func (uc *imageUseCase) runJob(job *entities.ImageJob) {
+	uc.workers <- struct{}{}
+	defer func() { <-uc.workers }()
+
+	job.Status = entities.ImageJobRunning
+	if err := uc.imageRepo.UpdateJob(job); err != nil {
+		log.Printf("Error starting image job %s: %v", job.ID, err)
+	}
+
+	imageURL, filename, err := uc.generate(job.Prompt)
+	uc.finishJob(job, imageURL, filename, err)
+
+	if err != nil {
+		uc.notify(job.UserID, entities.NotificationGenerationFailed, map[string]interface{}{
+			"JobID": job.ID.String(),
+		})
+		return
+	}
+	uc.notify(job.UserID, entities.NotificationGenerationFinished, map[string]interface{}{
+		"JobID":    job.ID.String(),
+		"Link":     imageURL,
+		"Filename": filename,
+	})
+}
+
+func (uc *imageUseCase) finishJob(job *entities.ImageJob, imageURL string, filename string, err error) {
+	now := uc.clock.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		job.Status = entities.ImageJobFailed
+		job.Error = err.Error()
+		log.Printf("Image job %s failed: %v", job.ID, err)
+	} else {
+		job.Status = entities.ImageJobSucceeded
+		job.ImageURL = imageURL
+		job.Filename = filename
+	}
+	if err := uc.imageRepo.UpdateJob(job); err != nil {
+		log.Printf("Error saving image job %s: %v", job.ID, err)
+	}
+
+	data := map[string]interface{}{
+		"job_id":      job.ID.String(),
+		"async":       job.Async,
+		"finished_at": job.FinishedAt.UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		// only the reason is sent, the error text may contain upstream details
+		reason := apperror.ErrImageGenerationFailed.Reason()
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) {
+			reason = appErr.Reason()
+		}
+		data["reason"] = reason
+		uc.dispatch(job.UserID, entities.WebhookImageFailed, data)
+		return
+	}
+	data["image_url"] = imageURL
+	data["filename"] = filename
+	uc.dispatch(job.UserID, entities.WebhookImageGenerated, data)
+}
+
+// dispatch never fails the generation that triggered the event.
+func (uc *imageUseCase) dispatch(userID ksuid.KSUID, event entities.WebhookEvent, data map[string]interface{}) {
+	if err := uc.webhooks.Dispatch(userID, event, data); err != nil {
+		log.Printf("Error queueing %s webhook for %s: %v", event, userID, err)
+	}
+}
+
+// notify never fails the generation that triggered the notification.
+func (uc *imageUseCase) notify(userID ksuid.KSUID, event entities.NotificationEvent, data map[string]interface{}) {
+	if err := uc.notifier.Notify(userID, event, data); err != nil {
+		log.Printf("Error sending %s notification to %s: %v", event, userID, err)
+	}
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repository "github.com/oriastanjung/stellar/internal/repository/image"
+	"github.com/oriastanjung/stellar/internal/utils/fake"
+	"github.com/segmentio/ksuid"
+)
+
+type testImage struct {
+	usecase    ImageUseCase
+	repo       *repository.MemoryImageRepository
+	clock      *fake.Clock
+	notifier   *fake.Notifier
+	dispatcher *fake.Dispatcher
+	cfg        *config.Config
+	// failing makes the upstream API answer 500, stalling makes it never
+	// answer
+	failing  atomic.Bool
+	stalling atomic.Bool
+}
+
+func newTestImage(t *testing.T, quota int) *testImage {
+	t.Helper()
+	image := &testImage{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if image.stalling.Load() {
+			// the context ends when the client gives up, once the body is read
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+			return
+		}
+		if image.failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "Here it is ![](https://cdn.stellar.test/images/lighthouse.jpeg)")
+	}))
+	t.Cleanup(upstream.Close)
+
+	image.cfg = &config.Config{
+		IMAGE_API_GENERATION_URL:    upstream.URL,
+		ImageGenerationWorkers:      2,
+		ImageGenerationQuota:        quota,
+		ImageGenerationQuotaPeriod:  30 * 24 * time.Hour,
+		ImageGenerationQuotaWarning: 80,
+		ImageAPIBreakerThreshold:    5,
+		ImageAPIBreakerCooldown:     30 * time.Second,
+		ImageAPITimeout:             500 * time.Millisecond,
+	}
+	image.clock = fake.NewClock(time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+	image.repo = repository.NewMemoryImageRepository(image.clock)
+	image.notifier = &fake.Notifier{}
+	image.dispatcher = &fake.Dispatcher{}
+	image.usecase = NewImageUseCase(image.repo, image.notifier, image.dispatcher, NewUpstreamBreaker(image.cfg, image.clock), image.clock, fake.NewIDs(), image.cfg)
+	return image
+}
+
+func (image *testImage) newUser() ksuid.KSUID {
+	userID := ksuid.New()
+	image.repo.AddUser(userID)
+	return userID
+}
+
+func TestGenerateImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		failing    bool
+		want       error
+		wantStatus entities.ImageJobStatus
+		wantEvent  entities.WebhookEvent
+	}{
+		{name: "success", wantStatus: entities.ImageJobSucceeded, wantEvent: entities.WebhookImageGenerated},
+		{name: "upstream error", failing: true, want: apperror.ErrImageGenerationFailed, wantStatus: entities.ImageJobFailed, wantEvent: entities.WebhookImageFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := newTestImage(t, 10)
+			image.failing.Store(tt.failing)
+			userID := image.newUser()
+
+			imageURL, filename, err := image.usecase.GenerateImage(userID, ksuid.Nil, "a lighthouse at dusk")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GenerateImage error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (imageURL != "https://cdn.stellar.test/images/lighthouse.jpeg" || filename != "lighthouse") {
+				t.Fatalf("GenerateImage = %q, %q", imageURL, filename)
+			}
+
+			jobs := image.repo.Jobs()
+			if len(jobs) != 1 || jobs[0].Status != tt.wantStatus || jobs[0].FinishedAt == nil {
+				t.Fatalf("jobs = %+v, want one %s job", jobs, tt.wantStatus)
+			}
+			if events := image.dispatcher.Events(string(tt.wantEvent)); len(events) != 1 {
+				t.Fatalf("%d %s webhook events, want 1", len(events), tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestGenerateImageTimeout(t *testing.T) {
+	image := newTestImage(t, 10)
+	image.stalling.Store(true)
+
+	started := time.Now()
+	_, _, err := image.usecase.GenerateImage(image.newUser(), ksuid.Nil, "prompt")
+	if !errors.Is(err, apperror.ErrImageGenerationFailed) {
+		t.Fatalf("GenerateImage = %v, want ErrImageGenerationFailed", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*image.cfg.ImageAPITimeout {
+		t.Fatalf("GenerateImage returned after %v, want about IMAGE_API_TIMEOUT", elapsed)
+	}
+	if jobs := image.repo.Jobs(); len(jobs) != 1 || jobs[0].Status != entities.ImageJobFailed {
+		t.Fatalf("jobs = %+v, want one failed job", jobs)
+	}
+}
+
+func TestGenerateImageQuota(t *testing.T) {
+	image := newTestImage(t, 3)
+	userID := image.newUser()
+
+	// 80% of 3 rounds up to the third generation
+	for i := 1; i <= 3; i++ {
+		if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); err != nil {
+			t.Fatalf("generation %d: %v", i, err)
+		}
+		wantWarnings := 0
+		if i == 3 {
+			wantWarnings = 1
+		}
+		if warnings := image.notifier.Sent(entities.NotificationQuotaNearlyExhausted); len(warnings) != wantWarnings {
+			t.Fatalf("after generation %d: %d quota warnings, want %d", i, len(warnings), wantWarnings)
+		}
+	}
+	if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); !errors.Is(err, apperror.ErrQuotaExceeded) {
+		t.Fatalf("generation over the quota = %v, want ErrQuotaExceeded", err)
+	}
+
+	// the quota is per user
+	if _, _, err := image.usecase.GenerateImage(image.newUser(), ksuid.Nil, "prompt"); err != nil {
+		t.Fatalf("generation of another user: %v", err)
+	}
+
+	// jobs older than the period no longer count
+	image.clock.Advance(image.cfg.ImageGenerationQuotaPeriod + time.Second)
+	if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); err != nil {
+		t.Fatalf("generation in the next period: %v", err)
+	}
+}
+
+func TestFailedGenerationDoesNotUseQuota(t *testing.T) {
+	image := newTestImage(t, 1)
+	userID := image.newUser()
+
+	image.failing.Store(true)
+	if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); !errors.Is(err, apperror.ErrImageGenerationFailed) {
+		t.Fatalf("GenerateImage = %v, want ErrImageGenerationFailed", err)
+	}
+	image.failing.Store(false)
+	if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); err != nil {
+		t.Fatalf("GenerateImage after a failed job: %v", err)
+	}
+}
+
+func TestGenerateImageForOrganization(t *testing.T) {
+	image := newTestImage(t, 0)
+	owner, member := image.newUser(), image.newUser()
+	granted := entities.Organization{ID: ksuid.New(), Name: "studio", GenerationQuota: 2, CreatedBy: owner}
+	image.repo.AddOrganization(granted)
+
+	tests := []struct {
+		name           string
+		userID         ksuid.KSUID
+		organizationID ksuid.KSUID
+		want           error
+	}{
+		{name: "unknown organization", userID: owner, organizationID: ksuid.New(), want: apperror.ErrOrganizationNotFound},
+		{name: "owner", userID: owner, organizationID: granted.ID},
+		{name: "member shares the pool", userID: member, organizationID: granted.ID},
+		{name: "pool used up", userID: member, organizationID: granted.ID, want: apperror.ErrQuotaExceeded},
+		// IMAGE_GENERATION_QUOTA=0 leaves personal generations unlimited
+		{name: "personal library", userID: member},
+	}
+	for _, tt := range tests {
+		_, _, err := image.usecase.GenerateImage(tt.userID, tt.organizationID, "prompt")
+		if !errors.Is(err, tt.want) {
+			t.Fatalf("%s: GenerateImage error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	jobs, _, err := image.usecase.ListImageJobs(owner, granted.ID, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("organization library has %d jobs, want 2", len(jobs))
+	}
+}
+
+func TestGenerateImageAsync(t *testing.T) {
+	image := newTestImage(t, 10)
+	userID := image.newUser()
+
+	queued, err := image.usecase.GenerateImageAsync(userID, ksuid.Nil, "a lighthouse at dusk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.Status != entities.ImageJobPending || !queued.Async {
+		t.Fatalf("queued job = %+v, want a pending async job", queued)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := image.usecase.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	job, err := image.usecase.GetImageJob(userID, ksuid.Nil, queued.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != entities.ImageJobSucceeded || job.Filename != "lighthouse" {
+		t.Fatalf("job after Drain = %+v, want succeeded", job)
+	}
+	if finished := image.notifier.Sent(entities.NotificationGenerationFinished); len(finished) != 1 || finished[0].UserID != userID {
+		t.Fatalf("finished notifications = %+v, want one for the user", finished)
+	}
+	if _, err := image.usecase.GetImageJob(image.newUser(), ksuid.Nil, queued.ID); !errors.Is(err, apperror.ErrImageJobNotFound) {
+		t.Fatalf("GetImageJob of another user = %v, want ErrImageJobNotFound", err)
+	}
+}
+
+func TestListImageJobsPages(t *testing.T) {
+	image := newTestImage(t, 0)
+	userID := image.newUser()
+	for range 5 {
+		if _, _, err := image.usecase.GenerateImage(userID, ksuid.Nil, "prompt"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen []ksuid.KSUID
+	pageToken := ""
+	for _, wantSize := range []int{2, 2, 1} {
+		jobs, next, err := image.usecase.ListImageJobs(userID, ksuid.Nil, 2, pageToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != wantSize {
+			t.Fatalf("page has %d jobs, want %d", len(jobs), wantSize)
+		}
+		for _, job := range jobs {
+			if len(seen) > 0 && ksuid.Compare(job.ID, seen[len(seen)-1]) >= 0 {
+				t.Fatal("jobs are not listed newest first")
+			}
+			seen = append(seen, job.ID)
+		}
+		pageToken = next
+	}
+	if pageToken != "" {
+		t.Fatalf("last page returned page token %q", pageToken)
+	}
+
+	if _, _, err := image.usecase.ListImageJobs(userID, ksuid.Nil, 2, "not-a-ksuid"); !errors.Is(err, apperror.ErrInvalidRequest) {
+		t.Fatalf("ListImageJobs with a bad page token = %v, want ErrInvalidRequest", err)
+	}
+}
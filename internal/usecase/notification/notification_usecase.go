@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repository "github.com/oriastanjung/stellar/internal/repository/notification"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	"github.com/segmentio/ksuid"
+)
+
+// Notifier emails a user about an event unless they turned it off. The
+// email is rendered from the template named after the event.
+type Notifier interface {
+	Notify(userID ksuid.KSUID, event entities.NotificationEvent, data map[string]interface{}) error
+}
+
+type NotificationUseCase interface {
+	Notifier
+	GetPreferences(userID ksuid.KSUID) ([]entities.NotificationPreference, error)
+	UpdatePreferences(userID ksuid.KSUID, preferences []entities.NotificationPreference) ([]entities.NotificationPreference, error)
+	Unsubscribe(token string) error
+}
+
+type notificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+	cfg              *config.Config
+}
+
+func NewNotificationUseCase(notificationRepo repository.NotificationRepository, cfg *config.Config) NotificationUseCase {
+	return &notificationUseCase{
+		notificationRepo: notificationRepo,
+		cfg:              cfg,
+	}
+}
+
+func (usecase *notificationUseCase) Notify(userID ksuid.KSUID, event entities.NotificationEvent, data map[string]interface{}) error {
+	preference, err := usecase.notificationRepo.FindPreference(userID, event)
+	if err != nil {
+		return err
+	}
+	if preference != nil && !preference.EmailEnabled {
+		return nil
+	}
+	user, err := usecase.notificationRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	templateData := map[string]interface{}{
+		"UnsubscribeLink": usecase.unsubscribeLink(userID, event),
+	}
+	for key, value := range data {
+		templateData[key] = value
+	}
+	msg, err := mailer.Render(string(event), user.PreferredLocale, user.Email, templateData)
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("error rendering %s notification: %w", event, err))
+	}
+	return usecase.notificationRepo.EnqueueEmail(mailer.NewOutboxEmail(msg))
+}
+
+// GetPreferences returns every event, with the default for those the user
+// never changed.
+func (usecase *notificationUseCase) GetPreferences(userID ksuid.KSUID) ([]entities.NotificationPreference, error) {
+	saved, err := usecase.notificationRepo.FindPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := map[entities.NotificationEvent]bool{}
+	for _, preference := range saved {
+		enabled[preference.Event] = preference.EmailEnabled
+	}
+
+	preferences := make([]entities.NotificationPreference, 0, len(entities.NotificationEvents))
+	for _, event := range entities.NotificationEvents {
+		emailEnabled, ok := enabled[event]
+		preferences = append(preferences, entities.NotificationPreference{
+			UserID:       userID,
+			Event:        event,
+			EmailEnabled: emailEnabled || !ok,
+		})
+	}
+	return preferences, nil
+}
+
+func (usecase *notificationUseCase) UpdatePreferences(userID ksuid.KSUID, preferences []entities.NotificationPreference) ([]entities.NotificationPreference, error) {
+	for i := range preferences {
+		if !preferences[i].Event.Valid() {
+			return nil, apperror.ErrUnknownNotificationEvent.WithMetadata("event", string(preferences[i].Event))
+		}
+		preferences[i].UserID = userID
+	}
+	if err := usecase.notificationRepo.SavePreferences(preferences); err != nil {
+		return nil, err
+	}
+	return usecase.GetPreferences(userID)
+}
+
+func (usecase *notificationUseCase) Unsubscribe(token string) error {
+	userID, event, ok := usecase.parseUnsubscribeToken(token)
+	if !ok {
+		return apperror.ErrInvalidUnsubscribeToken
+	}
+	err := usecase.notificationRepo.SavePreferences([]entities.NotificationPreference{{
+		UserID:       userID,
+		Event:        event,
+		EmailEnabled: false,
+	}})
+	if err != nil {
+		return err
+	}
+	log.Printf("User %s unsubscribed from %s notifications", userID, event)
+	return nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/segmentio/ksuid"
+)
+
+// Unsubscribe links must keep working for old emails, so instead of being
+// stored the token is "<user id>.<event>.<signature>" signed with a key
+// derived from AES_SECRET_KEY.
+
+func (usecase *notificationUseCase) unsubscribeLink(userID ksuid.KSUID, event entities.NotificationEvent) string {
+	cfg := usecase.cfg
+	if cfg.NotificationUnsubscribeLink == "" {
+		return ""
+	}
+	return cfg.NotificationUnsubscribeLink + "/" + usecase.unsubscribeToken(userID, event)
+}
+
+func (usecase *notificationUseCase) unsubscribeToken(userID ksuid.KSUID, event entities.NotificationEvent) string {
+	payload := userID.String() + "." + string(event)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(usecase.unsubscribeSignature(payload))
+}
+
+func (usecase *notificationUseCase) parseUnsubscribeToken(token string) (ksuid.KSUID, entities.NotificationEvent, bool) {
+	dot := strings.LastIndex(token, ".")
+	if dot == -1 {
+		return ksuid.Nil, "", false
+	}
+	payload, encoded := token[:dot], token[dot+1:]
+	signature, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || !hmac.Equal(signature, usecase.unsubscribeSignature(payload)) {
+		return ksuid.Nil, "", false
+	}
+
+	rawID, rawEvent, _ := strings.Cut(payload, ".")
+	userID, err := ksuid.Parse(rawID)
+	event := entities.NotificationEvent(rawEvent)
+	if err != nil || !event.Valid() {
+		return ksuid.Nil, "", false
+	}
+	return userID, event, true
+}
+
+func (usecase *notificationUseCase) unsubscribeSignature(payload string) []byte {
+	cfg := usecase.cfg
+	key := sha256.Sum256([]byte("stellar-unsubscribe:" + cfg.AESSecretKey))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
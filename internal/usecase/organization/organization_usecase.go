@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/i18n"
+	repository "github.com/oriastanjung/stellar/internal/repository/organization"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/mailer"
+	"github.com/segmentio/ksuid"
+)
+
+// MembershipFinder resolves the x-org-id of a request to the membership of
+// the caller, used by the token validation interceptor.
+type MembershipFinder interface {
+	FindMembership(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error)
+}
+
+// UserOrganization is an organization with the role of the user in it.
+type UserOrganization struct {
+	Organization entities.Organization
+	Role         entities.OrganizationRole
+}
+
+// Member is a membership with the account of the member.
+type Member struct {
+	entities.Membership
+	User entities.User
+}
+
+// OrganizationUseCase methods acting on an organization take the
+// membership of the caller, resolved from x-org-id, as actor.
+type OrganizationUseCase interface {
+	MembershipFinder
+	CreateOrganization(userID ksuid.KSUID, name string) (*entities.Organization, error)
+	SetGenerationQuota(adminID ksuid.KSUID, organizationID ksuid.KSUID, quota int) (*entities.Organization, error)
+	ListOrganizations(userID ksuid.KSUID) ([]UserOrganization, error)
+	DeleteOrganization(actor *entities.Membership) error
+	InviteMember(actor *entities.Membership, email string, role entities.OrganizationRole) (*entities.OrganizationInvitation, error)
+	ListInvitations(actor *entities.Membership) ([]entities.OrganizationInvitation, error)
+	RevokeInvitation(actor *entities.Membership, invitationID ksuid.KSUID) error
+	AcceptInvitation(userID ksuid.KSUID, token string) (*entities.Membership, error)
+	ListMembers(actor *entities.Membership) ([]Member, error)
+	UpdateMemberRole(actor *entities.Membership, userID ksuid.KSUID, role entities.OrganizationRole) error
+	RemoveMember(actor *entities.Membership, userID ksuid.KSUID) error
+}
+
+type organizationUseCase struct {
+	organizationRepo repository.OrganizationRepository
+	clock            utils.Clock
+	ids              utils.IDGenerator
+	cfg              *config.Config
+}
+
+func NewOrganizationUseCase(organizationRepo repository.OrganizationRepository, clock utils.Clock, ids utils.IDGenerator, cfg *config.Config) OrganizationUseCase {
+	return &organizationUseCase{
+		organizationRepo: organizationRepo,
+		clock:            clock,
+		ids:              ids,
+		cfg:              cfg,
+	}
+}
+
+func (usecase *organizationUseCase) FindMembership(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error) {
+	return usecase.organizationRepo.FindMembership(organizationID, userID)
+}
+
+// CreateOrganization makes the user its owner, the pooled quota starts at
+// ORGANIZATION_GENERATION_QUOTA. A user may create at most
+// ORGANIZATION_MAX_PER_USER organizations, each one is a new pool on top of
+// the personal quota.
+func (usecase *organizationUseCase) CreateOrganization(userID ksuid.KSUID, name string) (*entities.Organization, error) {
+	cfg := usecase.cfg
+	organization := &entities.Organization{
+		ID:              usecase.ids.NewID(),
+		Name:            strings.TrimSpace(name),
+		GenerationQuota: cfg.OrganizationGenerationQuota,
+		CreatedBy:       userID,
+	}
+	owner := &entities.Membership{
+		OrganizationID: organization.ID,
+		UserID:         userID,
+		Role:           entities.OrganizationOwner,
+	}
+	if err := usecase.organizationRepo.CreateOrganization(organization, owner, cfg.OrganizationMaxPerUser); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// SetGenerationQuota is called by an admin, quota is the number of
+// generations the organization may make per IMAGE_GENERATION_QUOTA_PERIOD,
+// 0 is unlimited.
+func (usecase *organizationUseCase) SetGenerationQuota(adminID ksuid.KSUID, organizationID ksuid.KSUID, quota int) (*entities.Organization, error) {
+	if quota < 0 {
+		return nil, apperror.ErrInvalidRequest.WithMetadata("generationQuota", fmt.Sprint(quota))
+	}
+	if err := usecase.organizationRepo.UpdateGenerationQuota(organizationID, quota); err != nil {
+		return nil, err
+	}
+	log.Printf("Admin %s set the generation quota of organization %s to %d", adminID, organizationID, quota)
+	return usecase.organizationRepo.FindOrganization(organizationID)
+}
+
+func (usecase *organizationUseCase) ListOrganizations(userID ksuid.KSUID) ([]UserOrganization, error) {
+	memberships, err := usecase.organizationRepo.FindMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+	roles := map[ksuid.KSUID]entities.OrganizationRole{}
+	ids := make([]ksuid.KSUID, 0, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.OrganizationID] = membership.Role
+		ids = append(ids, membership.OrganizationID)
+	}
+
+	organizations, err := usecase.organizationRepo.FindOrganizations(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]UserOrganization, 0, len(organizations))
+	for _, organization := range organizations {
+		result = append(result, UserOrganization{Organization: organization, Role: roles[organization.ID]})
+	}
+	return result, nil
+}
+
+func (usecase *organizationUseCase) DeleteOrganization(actor *entities.Membership) error {
+	if actor.Role != entities.OrganizationOwner {
+		return apperror.ErrOrganizationRole.WithMetadata("required", string(entities.OrganizationOwner))
+	}
+	if err := usecase.organizationRepo.DeleteOrganization(actor.OrganizationID); err != nil {
+		return err
+	}
+	log.Printf("User %s deleted organization %s", actor.UserID, actor.OrganizationID)
+	return nil
+}
+
+// InviteMember emails a single-use link, admins can only invite members
+// while owners can invite any role.
+func (usecase *organizationUseCase) InviteMember(actor *entities.Membership, email string, role entities.OrganizationRole) (*entities.OrganizationInvitation, error) {
+	cfg := usecase.cfg
+	if !role.Valid() {
+		return nil, apperror.ErrInvalidRequest.WithMetadata("role", string(role))
+	}
+	if !actor.Role.CanManage(role) {
+		return nil, apperror.ErrOrganizationRole.WithMetadata("role", string(role))
+	}
+
+	organization, err := usecase.organizationRepo.FindOrganization(actor.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := usecase.organizationRepo.FindUserByID(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, apperror.Internal(fmt.Errorf("error generating invitation token: %w", err))
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	// undangan dikirim dengan bahasa pengundang, penerima belum tentu punya akun
+	msg, err := mailer.Render("organization_invitation", inviter.PreferredLocale, email, map[string]interface{}{
+		"OrganizationName": organization.Name,
+		"InviterName":      inviter.Username,
+		"Role":             string(role),
+		"Link":             cfg.OrganizationInvitationLink + "/" + token,
+		"ExpiresIn":        i18n.FormatDuration(inviter.PreferredLocale, cfg.OrganizationInvitationTTL),
+	})
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	invitation := &entities.OrganizationInvitation{
+		ID:             usecase.ids.NewID(),
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      actor.UserID,
+		ExpiresAt:      usecase.clock.Now().Add(cfg.OrganizationInvitationTTL),
+	}
+	if err := usecase.organizationRepo.CreateInvitation(invitation, mailer.NewOutboxEmail(msg)); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (usecase *organizationUseCase) ListInvitations(actor *entities.Membership) ([]entities.OrganizationInvitation, error) {
+	if !actor.Role.AtLeast(entities.OrganizationAdmin) {
+		return nil, apperror.ErrOrganizationRole.WithMetadata("required", string(entities.OrganizationAdmin))
+	}
+	return usecase.organizationRepo.FindInvitations(actor.OrganizationID)
+}
+
+func (usecase *organizationUseCase) RevokeInvitation(actor *entities.Membership, invitationID ksuid.KSUID) error {
+	if !actor.Role.AtLeast(entities.OrganizationAdmin) {
+		return apperror.ErrOrganizationRole.WithMetadata("required", string(entities.OrganizationAdmin))
+	}
+	return usecase.organizationRepo.DeleteInvitation(actor.OrganizationID, invitationID)
+}
+
+// AcceptInvitation only works for the account with the invited email, so
+// a forwarded link cannot be used by someone else.
+func (usecase *organizationUseCase) AcceptInvitation(userID ksuid.KSUID, token string) (*entities.Membership, error) {
+	invitation, err := usecase.organizationRepo.FindInvitationByTokenHash(utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || usecase.clock.Now().After(invitation.ExpiresAt) {
+		return nil, apperror.ErrInvalidInvitation
+	}
+	user, err := usecase.organizationRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, apperror.ErrInvalidInvitation.WithMetadata("reason", "email_mismatch")
+	}
+
+	membership := &entities.Membership{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         userID,
+		Role:           invitation.Role,
+	}
+	if err := usecase.organizationRepo.AcceptInvitation(invitation, membership); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func (usecase *organizationUseCase) ListMembers(actor *entities.Membership) ([]Member, error) {
+	memberships, err := usecase.organizationRepo.FindMembers(actor.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]ksuid.KSUID, 0, len(memberships))
+	for _, membership := range memberships {
+		ids = append(ids, membership.UserID)
+	}
+	users, err := usecase.organizationRepo.FindUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[ksuid.KSUID]entities.User{}
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	members := make([]Member, 0, len(memberships))
+	for _, membership := range memberships {
+		members = append(members, Member{Membership: membership, User: byID[membership.UserID]})
+	}
+	return members, nil
+}
+
+func (usecase *organizationUseCase) UpdateMemberRole(actor *entities.Membership, userID ksuid.KSUID, role entities.OrganizationRole) error {
+	if !role.Valid() {
+		return apperror.ErrInvalidRequest.WithMetadata("role", string(role))
+	}
+	target, err := usecase.findMember(actor.OrganizationID, userID)
+	if err != nil {
+		return err
+	}
+	if !actor.Role.CanManage(target.Role) || !actor.Role.CanManage(role) {
+		return apperror.ErrOrganizationRole.WithMetadata("role", string(role))
+	}
+	return usecase.organizationRepo.UpdateMemberRole(actor.OrganizationID, userID, role)
+}
+
+// RemoveMember also lets any member leave by removing themselves.
+func (usecase *organizationUseCase) RemoveMember(actor *entities.Membership, userID ksuid.KSUID) error {
+	if userID != actor.UserID {
+		target, err := usecase.findMember(actor.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+		if !actor.Role.CanManage(target.Role) {
+			return apperror.ErrOrganizationRole.WithMetadata("role", string(target.Role))
+		}
+	}
+	return usecase.organizationRepo.RemoveMember(actor.OrganizationID, userID)
+}
+
+func (usecase *organizationUseCase) findMember(organizationID ksuid.KSUID, userID ksuid.KSUID) (*entities.Membership, error) {
+	membership, err := usecase.organizationRepo.FindMembership(organizationID, userID)
+	if errors.Is(err, apperror.ErrNotOrganizationMember) {
+		return nil, apperror.ErrMemberNotFound
+	}
+	return membership, err
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"github.com/oriastanjung/stellar/internal/entities"
+	repository "github.com/oriastanjung/stellar/internal/repository/webhook"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/webhook"
+	"github.com/segmentio/ksuid"
+)
+
+// deliveryLogLimit is how many deliveries ListDeliveries returns.
+const deliveryLogLimit = 50
+
+// Dispatcher queues an event for every webhook of the user subscribed to
+// it, the delivery worker sends it.
+type Dispatcher interface {
+	Dispatch(userID ksuid.KSUID, event entities.WebhookEvent, data map[string]interface{}) error
+}
+
+type WebhookUseCase interface {
+	Dispatcher
+	CreateWebhook(userID ksuid.KSUID, url string, events []entities.WebhookEvent) (*entities.Webhook, string, error)
+	ListWebhooks(userID ksuid.KSUID) ([]entities.Webhook, error)
+	DeleteWebhook(userID ksuid.KSUID, webhookID ksuid.KSUID) error
+	ListDeliveries(userID ksuid.KSUID, webhookID ksuid.KSUID) ([]entities.WebhookDelivery, error)
+	RedeliverWebhook(userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error)
+}
+
+type webhookUseCase struct {
+	webhookRepo repository.WebhookRepository
+	cipher      *utils.AESCipher
+	clock       utils.Clock
+	ids         utils.IDGenerator
+	cfg         *config.Config
+}
+
+// NewWebhookUseCase seals the endpoint secrets with cipher, the delivery
+// worker needs the same key to sign the payloads.
+func NewWebhookUseCase(webhookRepo repository.WebhookRepository, cipher *utils.AESCipher, clock utils.Clock, ids utils.IDGenerator, cfg *config.Config) WebhookUseCase {
+	return &webhookUseCase{
+		webhookRepo: webhookRepo,
+		cipher:      cipher,
+		clock:       clock,
+		ids:         ids,
+		cfg:         cfg,
+	}
+}
+
+// eventPayload is the JSON body posted to the endpoints.
+type eventPayload struct {
+	ID        string                 `json:"id"`
+	Type      entities.WebhookEvent  `json:"type"`
+	CreatedAt string                 `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// CreateWebhook returns the signing secret in plain text, it cannot be
+// read again afterwards. Leaving events empty subscribes to all of them.
+func (usecase *webhookUseCase) CreateWebhook(userID ksuid.KSUID, url string, events []entities.WebhookEvent) (*entities.Webhook, string, error) {
+	cfg := usecase.cfg
+	if err := webhook.ValidateURL(url, cfg.WebhookAllowInsecureURL); err != nil {
+		return nil, "", apperror.ErrInvalidWebhookURL.Wrap(err)
+	}
+
+	subscribed := []entities.WebhookEvent{}
+	seen := map[entities.WebhookEvent]bool{}
+	for _, event := range events {
+		if !event.Valid() {
+			return nil, "", apperror.ErrUnknownWebhookEvent.WithMetadata("event", string(event))
+		}
+		if !seen[event] {
+			seen[event] = true
+			subscribed = append(subscribed, event)
+		}
+	}
+	if len(subscribed) == 0 {
+		subscribed = append(subscribed, entities.WebhookEvents...)
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", apperror.Internal(fmt.Errorf("error generating webhook secret: %w", err))
+	}
+	secret := "whsec_" + token
+	sealed, err := usecase.cipher.Seal(secret)
+	if err != nil {
+		return nil, "", apperror.Internal(fmt.Errorf("error sealing webhook secret: %w", err))
+	}
+
+	created := &entities.Webhook{
+		ID:     usecase.ids.NewID(),
+		UserID: userID,
+		URL:    url,
+		Secret: sealed,
+		Events: subscribed,
+	}
+	if err := usecase.webhookRepo.CreateWebhook(created, cfg.WebhookMaxPerUser); err != nil {
+		return nil, "", err
+	}
+	return created, secret, nil
+}
+
+func (usecase *webhookUseCase) ListWebhooks(userID ksuid.KSUID) ([]entities.Webhook, error) {
+	return usecase.webhookRepo.FindWebhooks(userID)
+}
+
+func (usecase *webhookUseCase) DeleteWebhook(userID ksuid.KSUID, webhookID ksuid.KSUID) error {
+	return usecase.webhookRepo.DeleteWebhook(userID, webhookID)
+}
+
+func (usecase *webhookUseCase) ListDeliveries(userID ksuid.KSUID, webhookID ksuid.KSUID) ([]entities.WebhookDelivery, error) {
+	return usecase.webhookRepo.FindDeliveries(userID, webhookID, deliveryLogLimit)
+}
+
+// RedeliverWebhook queues the payload of a past delivery again as a new
+// delivery, the event ID is kept so receivers can tell it is a duplicate.
+func (usecase *webhookUseCase) RedeliverWebhook(userID ksuid.KSUID, deliveryID ksuid.KSUID) (*entities.WebhookDelivery, error) {
+	original, err := usecase.webhookRepo.FindDelivery(userID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	delivery := usecase.newDelivery(original.WebhookID, original.EventID, original.Event, original.Payload)
+	if err := usecase.webhookRepo.CreateDeliveries([]entities.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (usecase *webhookUseCase) Dispatch(userID ksuid.KSUID, event entities.WebhookEvent, data map[string]interface{}) error {
+	webhooks, err := usecase.webhookRepo.FindWebhooks(userID)
+	if err != nil {
+		return err
+	}
+
+	eventID := usecase.ids.NewID()
+	deliveries := []entities.WebhookDelivery{}
+	var payload []byte
+	for _, endpoint := range webhooks {
+		if !endpoint.Subscribed(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(eventPayload{
+				ID:        eventID.String(),
+				Type:      event,
+				CreatedAt: usecase.clock.Now().UTC().Format(time.RFC3339),
+				Data:      data,
+			})
+			if err != nil {
+				return apperror.Internal(fmt.Errorf("error marshaling %s payload: %w", event, err))
+			}
+		}
+		deliveries = append(deliveries, usecase.newDelivery(endpoint.ID, eventID, event, string(payload)))
+	}
+	return usecase.webhookRepo.CreateDeliveries(deliveries)
+}
+
+func (usecase *webhookUseCase) newDelivery(webhookID ksuid.KSUID, eventID ksuid.KSUID, event entities.WebhookEvent, payload string) entities.WebhookDelivery {
+	return entities.WebhookDelivery{
+		ID:            usecase.ids.NewID(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        entities.WebhookDeliveryPending,
+		NextAttemptAt: usecase.clock.Now(),
+	}
+}
@@ -5,57 +5,146 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
-
-	"github.com/oriastanjung/stellar/internal/config"
+	"strings"
 )
 
-// Fungsi untuk enkripsi dengan AES-128
-func EncryptAES(text string) (string, error) {
-	cfg := config.LoadEnv()
-	key := cfg.AESSecretKey
-	aesKey := []byte(key)
-	block, err := aes.NewCipher(aesKey)
+type jweHeader struct {
+	Algorithm   string `json:"alg"`
+	Encryption  string `json:"enc"`
+	ContentType string `json:"cty,omitempty"`
+}
+
+// AESCipher encrypts with AES-GCM under AES_SECRET_KEY, it seals secrets
+// stored at rest and wraps tokens in JWE.
+type AESCipher struct {
+	gcm cipher.AEAD
+	enc string
+}
+
+// NewAESCipher accepts a 16, 24 or 32 byte key.
+func NewAESCipher(key string) (*AESCipher, error) {
+	var enc string
+	switch len(key) {
+	case 16:
+		enc = "A128GCM"
+	case 24:
+		enc = "A192GCM"
+	case 32:
+		enc = "A256GCM"
+	default:
+		return nil, errors.New("AES_SECRET_KEY must be 16, 24 or 32 bytes")
+	}
+
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESCipher{gcm: gcm, enc: enc}, nil
+}
+
+// EncryptJWE wraps plaintext in a compact JWE using direct key agreement.
+func (c *AESCipher) EncryptJWE(plaintext string) (string, error) {
+	gcm, enc := c.gcm, c.enc
+	header, err := json.Marshal(jweHeader{Algorithm: "dir", Encryption: enc, ContentType: "JWT"})
 	if err != nil {
 		return "", err
 	}
+	protected := base64.RawURLEncoding.EncodeToString(header)
 
-	ciphertext := make([]byte, aes.BlockSize+len(text))
-	iv := ciphertext[:aes.BlockSize]
+	iv := make([]byte, gcm.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
 		return "", err
 	}
 
-	stream := cipher.NewCFBEncrypter(block, iv)
-	stream.XORKeyStream(ciphertext[aes.BlockSize:], []byte(text))
+	// the protected header is the additional authenticated data
+	sealed := gcm.Seal(nil, iv, []byte(plaintext), []byte(protected))
+	tagStart := len(sealed) - gcm.Overhead()
 
-	return base64.URLEncoding.EncodeToString(ciphertext), nil
+	return strings.Join([]string{
+		protected,
+		"",
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(sealed[:tagStart]),
+		base64.RawURLEncoding.EncodeToString(sealed[tagStart:]),
+	}, "."), nil
 }
 
-// Fungsi untuk dekripsi dengan AES-128
-func DecryptAES(cryptoText string) (string, error) {
-	ciphertext, err := base64.URLEncoding.DecodeString(cryptoText)
+// DecryptJWE reverses EncryptJWE, rejecting tampered or foreign tokens.
+func (c *AESCipher) DecryptJWE(compact string) (string, error) {
+	parts := strings.Split(compact, ".")
+	if len(parts) != 5 {
+		return "", errors.New("malformed JWE")
+	}
+	gcm, enc := c.gcm, c.enc
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
 	if err != nil {
 		return "", err
 	}
-	cfg := config.LoadEnv()
-	key := cfg.AESSecretKey
-	aesKey := []byte(key)
-	block, err := aes.NewCipher(aesKey)
-	if err != nil {
+	var header jweHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
 		return "", err
 	}
+	if header.Algorithm != "dir" || header.Encryption != enc || parts[1] != "" {
+		return "", fmt.Errorf("unsupported JWE algorithm: %s/%s", header.Algorithm, header.Encryption)
+	}
 
-	if len(ciphertext) < aes.BlockSize {
-		return "", errors.New("ciphertext too short")
+	iv, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", err
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(parts[4])
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != gcm.NonceSize() || len(tag) != gcm.Overhead() {
+		return "", errors.New("malformed JWE")
 	}
 
-	iv := ciphertext[:aes.BlockSize]
-	ciphertext = ciphertext[aes.BlockSize:]
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return "", errors.New("JWE authentication failed")
+	}
+	return string(plaintext), nil
+}
 
-	stream := cipher.NewCFBDecrypter(block, iv)
-	stream.XORKeyStream(ciphertext, ciphertext)
+// Seal encrypts secrets stored at rest (such as TOTP secrets), prefixing
+// the random nonce to the ciphertext.
+func (c *AESCipher) Seal(plaintext string) (string, error) {
+	gcm := c.gcm
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
 
-	return string(ciphertext), nil
+// Open decrypts a value produced by Seal.
+func (c *AESCipher) Open(sealed string) (string, error) {
+	gcm := c.gcm
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("ciphertext authentication failed")
+	}
+	return string(plaintext), nil
 }
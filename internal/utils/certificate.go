@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSVersions are the accepted TLS_MIN_VERSION values.
+var TLSVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSClientAuth maps TLS_CLIENT_AUTH to how client certificates are
+// checked, "optional" verifies a certificate only when one is sent.
+var TLSClientAuth = map[string]tls.ClientAuthType{
+	"none":     tls.NoClientCert,
+	"optional": tls.VerifyClientCertIfGiven,
+	"require":  tls.RequireAndVerifyClientCert,
+}
+
+// CipherSuiteIDs converts cipher suite names (TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+// to their IDs, insecure suites are rejected. TLS 1.3 suites are not
+// configurable and always enabled.
+func CipherSuiteIDs(names []string) ([]uint16, error) {
+	byName := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		byName[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CertificateStore serves the server certificate and the CA of client
+// certificates from files, both are read again when the files change so
+// renewed certificates are picked up without a restart.
+type CertificateStore struct {
+	mu         sync.RWMutex
+	certFile   string
+	keyFile    string
+	caFile     string
+	interval   time.Duration
+	cert       *tls.Certificate
+	clientCAs  *x509.CertPool
+	modTimes   map[string]time.Time
+	lastReload time.Time
+}
+
+// LoadCertificateStore reads the certificate, key and, when caFile is not
+// empty, the client CA bundle. Changes are looked for at most once per interval.
+func LoadCertificateStore(certFile string, keyFile string, caFile string, interval time.Duration) (*CertificateStore, error) {
+	store := &CertificateStore{certFile: certFile, keyFile: keyFile, caFile: caFile, interval: interval}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *CertificateStore) files() []string {
+	files := []string{store.certFile, store.keyFile}
+	if store.caFile != "" {
+		files = append(files, store.caFile)
+	}
+	return files
+}
+
+func (store *CertificateStore) load() error {
+	modTimes := map[string]time.Time{}
+	for _, file := range store.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(store.certFile, store.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading certificate %s: %w", store.certFile, err)
+	}
+	var clientCAs *x509.CertPool
+	if store.caFile != "" {
+		bundle, err := os.ReadFile(store.caFile)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", store.caFile, err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("no certificates found in %s", store.caFile)
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.cert = &cert
+	store.clientCAs = clientCAs
+	store.modTimes = modTimes
+	store.lastReload = time.Now()
+	return nil
+}
+
+// changed reports whether a file was modified since the last load, it
+// checks at most once per interval.
+func (store *CertificateStore) changed() bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if time.Since(store.lastReload) < store.interval {
+		return false
+	}
+	store.lastReload = time.Now()
+	for _, file := range store.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			// file sedang diganti, coba lagi di interval berikutnya
+			continue
+		}
+		if !info.ModTime().Equal(store.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// current returns the certificate and client CAs, reloading them first
+// when the files changed. A broken replacement is logged and the loaded
+// certificate kept.
+func (store *CertificateStore) current() (*tls.Certificate, *x509.CertPool) {
+	if store.changed() {
+		if err := store.load(); err != nil {
+			log.Printf("Error reloading TLS certificate, keeping the loaded one: %v", err)
+		} else {
+			log.Printf("Reloaded TLS certificate %s", store.certFile)
+		}
+	}
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.cert, store.clientCAs
+}
+
+// ServerConfig returns a TLS config taking the certificate and client CAs
+// from the store on every handshake.
+func (store *CertificateStore) ServerConfig(minVersion uint16, cipherSuites []uint16, clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := store.current()
+			return &tls.Config{
+				MinVersion:   minVersion,
+				CipherSuites: cipherSuites,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    clientCAs,
+				NextProtos:   []string{"h2"},
+			}, nil
+		},
+	}
+}
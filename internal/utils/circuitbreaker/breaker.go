@@ -0,0 +1,99 @@
+// Package circuitbreaker stops calling a dependency that keeps failing and
+// lets a single trial call through once a cool down has passed.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/utils"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half-open"
+)
+
+// ErrOpen is returned by Allow while calls are being refused.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker opens after threshold consecutive failures and refuses calls for
+// cooldown, then lets one call through: success closes it again, failure
+// opens it for another cooldown.
+type Breaker struct {
+	mu        sync.Mutex
+	name      string
+	threshold int
+	cooldown  time.Duration
+	clock     utils.Clock
+	state     State
+	failures  int
+	openedAt  time.Time
+	// trial is true while the half-open call is in flight
+	trial     bool
+	lastError error
+}
+
+func New(name string, threshold int, cooldown time.Duration, clock utils.Clock) *Breaker {
+	return &Breaker{name: name, threshold: max(threshold, 1), cooldown: cooldown, clock: clock, state: StateClosed}
+}
+
+func (breaker *Breaker) Name() string {
+	return breaker.name
+}
+
+// Allow reports whether a call may be made now, every allowed call must be
+// followed by Record.
+func (breaker *Breaker) Allow() error {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	switch breaker.state {
+	case StateOpen:
+		if breaker.clock.Now().Sub(breaker.openedAt) < breaker.cooldown {
+			return ErrOpen
+		}
+		breaker.state = StateHalfOpen
+		breaker.trial = true
+		return nil
+	case StateHalfOpen:
+		if breaker.trial {
+			return ErrOpen
+		}
+		breaker.trial = true
+		return nil
+	}
+	return nil
+}
+
+// Record counts the outcome of a call allowed by Allow, err is nil on success.
+func (breaker *Breaker) Record(err error) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	breaker.trial = false
+	if err == nil {
+		breaker.state = StateClosed
+		breaker.failures = 0
+		breaker.lastError = nil
+		return
+	}
+	breaker.lastError = err
+	breaker.failures++
+	if breaker.state == StateHalfOpen || breaker.failures >= breaker.threshold {
+		breaker.state = StateOpen
+		breaker.openedAt = breaker.clock.Now()
+	}
+}
+
+// State returns the current state and, unless closed, the last failure.
+func (breaker *Breaker) State() (State, error) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if breaker.state == StateClosed {
+		return breaker.state, nil
+	}
+	return breaker.state, breaker.lastError
+}
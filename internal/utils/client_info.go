@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientInfo describes the caller of an RPC.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// GetClientInfo reads the peer address and the user-agent metadata, headers
+// like x-forwarded-for are not trusted.
+func GetClientInfo(ctx context.Context) ClientInfo {
+	var info ClientInfo
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		info.IP = host
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("user-agent")) > 0 {
+		info.UserAgent = md.Get("user-agent")[0]
+	}
+	return info
+}
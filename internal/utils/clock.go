@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Clock tells the current time, usecases take one instead of calling
+// time.Now so expiry and lockout rules can be checked at a fixed time.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,200 @@
+// Package fake has deterministic stand-ins for the components the usecases
+// take in their constructors, so they can be exercised with the in-memory
+// repositories without Postgres, keys on disk or an SMTP server.
+package fake
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/segmentio/ksuid"
+)
+
+// Clock stays at the time it was set to until it is moved.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (clock *Clock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *Clock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+func (clock *Clock) Set(now time.Time) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = now
+}
+
+// IDs hands out increasing KSUIDs, later IDs always sort after earlier ones
+// even when the clock does not move.
+type IDs struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+func NewIDs() *IDs {
+	return &IDs{}
+}
+
+func (ids *IDs) NewID() ksuid.KSUID {
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+	ids.next++
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint64(payload[8:], ids.next)
+	id, _ := ksuid.FromParts(time.Unix(1_700_000_000, 0), payload)
+	return id
+}
+
+// Hasher "hashes" by prefixing the password, fast and easy to assert on.
+type Hasher struct{}
+
+func (Hasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (Hasher) Compare(hash string, password string) error {
+	if hash != "hashed:"+password {
+		return fmt.Errorf("password does not match")
+	}
+	return nil
+}
+
+// TokenIssuer issues opaque tokens and remembers their claims, expiry is
+// checked against clock.
+type TokenIssuer struct {
+	mu        sync.Mutex
+	clock     utils.Clock
+	accessTTL time.Duration
+	issued    map[string]utils.JWTClaims
+}
+
+func NewTokenIssuer(clock utils.Clock, accessTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{clock: clock, accessTTL: accessTTL, issued: map[string]utils.JWTClaims{}}
+}
+
+func (issuer *TokenIssuer) IssueAccessToken(user entities.User) (string, error) {
+	return issuer.Issue(user, utils.TokenUseAccess, issuer.accessTTL)
+}
+
+func (issuer *TokenIssuer) Issue(user entities.User, use string, ttl time.Duration) (string, error) {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	token := fmt.Sprintf("%s.%s.%d", use, user.ID, len(issuer.issued)+1)
+	now := issuer.clock.Now()
+	issuer.issued[token] = utils.JWTClaims{
+		UserId:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		Use:      use,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return token, nil
+}
+
+func (issuer *TokenIssuer) Verify(token string, uses ...string) (*utils.JWTClaims, error) {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	claims, ok := issuer.issued[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown token")
+	}
+	if !slices.Contains(uses, claims.Use) {
+		return nil, fmt.Errorf("token use %q is not accepted", claims.Use)
+	}
+	if !issuer.clock.Now().Before(claims.ExpiresAt.Time) {
+		return nil, fmt.Errorf("token is expired")
+	}
+	return &claims, nil
+}
+
+// Notification is one call to Notifier.Notify.
+type Notification struct {
+	UserID ksuid.KSUID
+	Event  entities.NotificationEvent
+	Data   map[string]interface{}
+}
+
+// Notifier records the notifications instead of emailing them.
+type Notifier struct {
+	mu   sync.Mutex
+	sent []Notification
+}
+
+func (notifier *Notifier) Notify(userID ksuid.KSUID, event entities.NotificationEvent, data map[string]interface{}) error {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	notifier.sent = append(notifier.sent, Notification{UserID: userID, Event: event, Data: data})
+	return nil
+}
+
+// Sent returns the recorded notifications of event, every event when it is empty.
+func (notifier *Notifier) Sent(event entities.NotificationEvent) []Notification {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	var sent []Notification
+	for _, notification := range notifier.sent {
+		if event == "" || notification.Event == event {
+			sent = append(sent, notification)
+		}
+	}
+	return sent
+}
+
+// Event is one call to Dispatcher.Dispatch.
+type Event struct {
+	UserID ksuid.KSUID
+	Type   entities.WebhookEvent
+	Data   map[string]interface{}
+}
+
+// Dispatcher records the webhook events instead of queueing deliveries.
+type Dispatcher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (dispatcher *Dispatcher) Dispatch(userID ksuid.KSUID, event entities.WebhookEvent, data map[string]interface{}) error {
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+	dispatcher.events = append(dispatcher.events, Event{UserID: userID, Type: event, Data: data})
+	return nil
+}
+
+// Events returns the recorded events whose type starts with prefix.
+func (dispatcher *Dispatcher) Events(prefix string) []Event {
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+	var events []Event
+	for _, event := range dispatcher.events {
+		if strings.HasPrefix(string(event.Type), prefix) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
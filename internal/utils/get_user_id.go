@@ -3,16 +3,41 @@ package utils
 import (
 	"context"
 
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/entities"
 	"github.com/segmentio/ksuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func GetUserId(ctx context.Context) (ksuid.KSUID, error) {
-	claims, ok := ctx.Value("claims").(*JWTClaims)
-	if !ok {
-		return ksuid.Nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	claims, err := GetClaims(ctx)
+	if err != nil {
+		return ksuid.Nil, err
 	}
 	userId := claims.UserId
 	return userId, nil
 }
+
+func GetClaims(ctx context.Context) (*JWTClaims, error) {
+	claims, ok := ctx.Value("claims").(*JWTClaims)
+	if !ok {
+		return nil, apperror.ErrUnauthenticated
+	}
+	return claims, nil
+}
+
+// GetMembership returns the membership of the organization selected with
+// x-org-id, nil when the call acts on the personal account.
+func GetMembership(ctx context.Context) *entities.Membership {
+	membership, _ := ctx.Value("membership").(*entities.Membership)
+	return membership
+}
+
+// RequireMembership is GetMembership for calls that only make sense on an
+// organization.
+func RequireMembership(ctx context.Context) (*entities.Membership, error) {
+	membership := GetMembership(ctx)
+	if membership == nil {
+		return nil, apperror.ErrOrganizationRequired
+	}
+	return membership, nil
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "golang.org/x/crypto/bcrypt"
+
+// Hasher hashes passwords and checks them against a stored hash.
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Compare returns an error when password does not match hash.
+	Compare(hash string, password string) error
+}
+
+// BcryptHasher hashes with the bcrypt cost from SALT_KEY.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (hasher BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), hasher.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (hasher BcryptHasher) Compare(hash string, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
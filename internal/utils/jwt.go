@@ -2,84 +2,177 @@ package utils
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/oriastanjung/stellar/internal/config"
 	"github.com/oriastanjung/stellar/internal/entities"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/segmentio/ksuid"
 )
 
+// Jenis token yang dibedakan lewat klaim Use
+const (
+	TokenUseAccess        = "access"
+	TokenUseMFAChallenge  = "mfa_challenge"
+	TokenUseMFAEnrollment = "mfa_enrollment"
+	// TokenUseAPIKey is never issued as a JWT, it marks claims built from
+	// an x-api-key, RegisteredClaims.ID is then the key ID
+	TokenUseAPIKey = "api_key"
+)
+
 // Buat struktur untuk klaim JWT (payload)
 type JWTClaims struct {
 	UserId   ksuid.KSUID
 	Username string
 	Email    string
 	Role     string
+	Use      string
 	jwt.RegisteredClaims
 }
 
-func GenerateTokenJWT(payload entities.User) (string, error) {
-	cfg := config.LoadEnv()
-	jwtSecret := cfg.JWTSecretKey
+// TokenIssuer issues and verifies the JWTs of the service.
+type TokenIssuer interface {
+	IssueAccessToken(user entities.User) (string, error)
+	// Issue creates a token restricted to use, such as the short lived
+	// MFA challenge handed out after the password step.
+	Issue(user entities.User, use string, ttl time.Duration) (string, error)
+	// Verify accepts only tokens issued for one of uses.
+	Verify(token string, uses ...string) (*JWTClaims, error)
+}
+
+// tokenType is the typ header of a token issued for use, access tokens
+// use the type of RFC 9068.
+func tokenType(use string) string {
+	if use == TokenUseAccess {
+		return "at+jwt"
+	}
+	return strings.ReplaceAll(use, "_", "-") + "+jwt"
+}
+
+// audience is the aud claim of a token issued for use. Only access tokens
+// carry the bare issuer, so a JWKS consumer checking aud or typ cannot
+// mistake an MFA token for an access token.
+func (issuer *JWTIssuer) audience(use string) string {
+	if use == TokenUseAccess {
+		return issuer.issuer
+	}
+	return issuer.issuer + "/" + use
+}
+
+// JWTIssuer signs with the active key of the ring, tokens are wrapped in
+// JWE when cipher is set.
+type JWTIssuer struct {
+	ring      *KeyRing
+	cipher    *AESCipher
+	issuer    string
+	accessTTL time.Duration
+	clock     Clock
+}
+
+// NewJWTIssuer leaves tokens unencrypted when cipher is nil.
+func NewJWTIssuer(ring *KeyRing, cipher *AESCipher, issuer string, accessTTL time.Duration, clock Clock) *JWTIssuer {
+	return &JWTIssuer{
+		ring:      ring,
+		cipher:    cipher,
+		issuer:    issuer,
+		accessTTL: accessTTL,
+		clock:     clock,
+	}
+}
+
+func (issuer *JWTIssuer) IssueAccessToken(payload entities.User) (string, error) {
+	return issuer.Issue(payload, TokenUseAccess, issuer.accessTTL)
+}
+
+func (issuer *JWTIssuer) Issue(payload entities.User, use string, ttl time.Duration) (string, error) {
+	key, err := issuer.ring.SigningKey()
+	if err != nil {
+		return "", err
+	}
 
 	// Buat claim JWT
+	now := issuer.clock.Now()
 	claims := JWTClaims{
 		UserId:   payload.ID,
 		Username: payload.Username,
 		Email:    payload.Email,
 		Role:     payload.Role,
+		Use:      use,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 3)),
+			Issuer:    issuer.issuer,
+			Audience:  jwt.ClaimStrings{issuer.audience(use)},
+			Subject:   payload.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	// Membuat token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Membuat token, kid menunjuk ke key yang dipakai untuk signing
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.KID
+	token.Header["typ"] = tokenType(use)
 
-	// Mengenerate token string JWT
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", err
 	}
 
-	// Enkripsi token string JWT dengan AES-128
-	encryptedToken, err := EncryptAES(tokenString)
-	if err != nil {
-		return "", err
+	if issuer.cipher == nil {
+		return tokenString, nil
 	}
-
-	// Return token yang sudah terenkripsi
-	return encryptedToken, nil
+	return issuer.cipher.EncryptJWE(tokenString)
 }
 
-func VerifyTokenJWT(tokenString string) (*JWTClaims, error) {
-	cfg := config.LoadEnv()
-	jwtSecret := cfg.JWTSecretKey
-
-	// Dekripsi token string yang sudah terenkripsi dengan AES-128
-	decryptedToken, err := DecryptAES(tokenString)
-	if err != nil {
-		return nil, err
+func (issuer *JWTIssuer) Verify(tokenString string, uses ...string) (*JWTClaims, error) {
+	var err error
+	// Token terenkripsi (JWE) memiliki lima segmen
+	if strings.Count(tokenString, ".") == 4 {
+		if issuer.cipher == nil {
+			return nil, fmt.Errorf("encrypted tokens are disabled")
+		}
+		tokenString, err = issuer.cipher.DecryptJWE(tokenString)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Parsing token JWT setelah didekripsi
-	token, err := jwt.ParseWithClaims(decryptedToken, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Memastikan bahwa algoritma yang digunakan adalah HMAC dengan SHA256
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := issuer.ring.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// Memastikan algoritma token sama dengan algoritma key
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(jwtSecret), nil
-	})
+		return key.PrivateKey.Public(), nil
+	},
+		jwt.WithValidMethods([]string{AlgorithmEdDSA, AlgorithmRS256}),
+		jwt.WithIssuer(issuer.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(issuer.clock.Now),
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validasi token dan klaim
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return claims, nil
-	} else {
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
 		return nil, fmt.Errorf("invalid token")
 	}
+	// Jenis token harus cocok di klaim Use, header typ dan aud
+	if !slices.Contains(uses, claims.Use) {
+		return nil, fmt.Errorf("token use %q is not accepted", claims.Use)
+	}
+	if typ, _ := token.Header["typ"].(string); typ != tokenType(claims.Use) {
+		return nil, fmt.Errorf("unexpected token type %q", typ)
+	}
+	if !slices.Contains(claims.Audience, issuer.audience(claims.Use)) {
+		return nil, fmt.Errorf("unexpected token audience")
+	}
+	return claims, nil
 }
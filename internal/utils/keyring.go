@@ -0,0 +1,346 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+
+	keyRingManifest = "keyring.json"
+	// keyRingReload is how often the ring is read again from disk, a key
+	// rotated by another process is signed with after at most this long
+	keyRingReload = 30 * time.Second
+)
+
+// SigningKey is a single private key in the key ring. A key without
+// RetiresAt is the active signing key; retired keys are kept around to
+// verify tokens issued before the rotation until RetiresAt passes.
+type SigningKey struct {
+	KID        string        `json:"kid"`
+	Algorithm  string        `json:"alg"`
+	CreatedAt  time.Time     `json:"created_at"`
+	RetiresAt  *time.Time    `json:"retires_at,omitempty"`
+	PrivateKey crypto.Signer `json:"-"`
+}
+
+func (key *SigningKey) usable(now time.Time) bool {
+	return key.RetiresAt == nil || now.Before(*key.RetiresAt)
+}
+
+func (key *SigningKey) method() jwt.SigningMethod {
+	if key.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// KeyRing holds the JWT signing keys persisted in a directory as
+// <kid>.pem files plus a keyring.json manifest.
+type KeyRing struct {
+	mu         sync.RWMutex
+	dir        string
+	algorithm  string
+	overlap    time.Duration
+	keys       []*SigningKey
+	lastReload time.Time
+}
+
+// JSONWebKey is the public half of a signing key as published in the JWKS.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KID       string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// LoadKeyRing reads the key ring from dir, generating the first key when
+// the directory is empty.
+func LoadKeyRing(dir string, algorithm string, overlap time.Duration) (*KeyRing, error) {
+	if algorithm != AlgorithmEdDSA && algorithm != AlgorithmRS256 {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	ring := &KeyRing{
+		dir:       dir,
+		algorithm: algorithm,
+		overlap:   overlap,
+	}
+	if err := ring.reload(); err != nil {
+		return nil, err
+	}
+	if ring.active() == nil {
+		if err := ring.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+// Rotate generates a new active key. The previous active key keeps
+// verifying tokens for the configured overlap window, and keys whose
+// window has passed are removed from disk.
+func (ring *KeyRing) Rotate() error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	key, err := generateSigningKey(ring.algorithm)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	retiresAt := now.Add(ring.overlap)
+	kept := make([]*SigningKey, 0, len(ring.keys)+1)
+	for _, existing := range ring.keys {
+		if existing.RetiresAt == nil {
+			existing.RetiresAt = &retiresAt
+		}
+		if !existing.usable(now) {
+			if err := os.Remove(filepath.Join(ring.dir, existing.KID+".pem")); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("error removing retired key %s: %w", existing.KID, err)
+			}
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	ring.keys = append(kept, key)
+
+	if err := ring.save(key); err != nil {
+		return err
+	}
+	log.Printf("Rotated JWT signing key, active kid %s", key.KID)
+	return nil
+}
+
+// SigningKey returns the key new tokens must be signed with, the newest
+// active key on disk once the ring has been reloaded.
+func (ring *KeyRing) SigningKey() (*SigningKey, error) {
+	ring.refresh()
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	key := ring.active()
+	if key == nil {
+		return nil, errors.New("no active signing key")
+	}
+	return key, nil
+}
+
+// VerificationKey looks a key up by kid. Unknown kids trigger a throttled
+// reload so rotations done by another instance are picked up.
+func (ring *KeyRing) VerificationKey(kid string) (*SigningKey, error) {
+	if key := ring.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	ring.refresh()
+	if key := ring.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key: %s", kid)
+}
+
+// JWKS returns the public keys of every key still valid for verification.
+func (ring *KeyRing) JWKS() JSONWebKeySet {
+	ring.refresh()
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	now := time.Now()
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range ring.keys {
+		if !key.usable(now) {
+			continue
+		}
+		jwk := JSONWebKey{KID: key.KID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.PrivateKey.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func (ring *KeyRing) lookup(kid string) *SigningKey {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	now := time.Now()
+	for _, key := range ring.keys {
+		if key.KID == kid && key.usable(now) {
+			return key
+		}
+	}
+	return nil
+}
+
+func (ring *KeyRing) active() *SigningKey {
+	for i := len(ring.keys) - 1; i >= 0; i-- {
+		if ring.keys[i].RetiresAt == nil {
+			return ring.keys[i]
+		}
+	}
+	return nil
+}
+
+// refresh reloads the ring at most once per keyRingReload. A rotation done
+// by `stellar rotate-keys` or another instance retires the key this process
+// signs with, so it has to switch to the new one well within the overlap.
+func (ring *KeyRing) refresh() {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	if time.Since(ring.lastReload) < keyRingReload {
+		return
+	}
+	previous := ring.active()
+	if err := ring.reloadLocked(); err != nil {
+		log.Printf("Error reloading key ring, keeping the loaded keys: %v", err)
+		return
+	}
+	if key := ring.active(); key != nil && (previous == nil || key.KID != previous.KID) {
+		log.Printf("Switched to JWT signing key %s", key.KID)
+	}
+}
+
+func (ring *KeyRing) reload() error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	return ring.reloadLocked()
+}
+
+func (ring *KeyRing) reloadLocked() error {
+	ring.lastReload = time.Now()
+
+	manifest, err := os.ReadFile(filepath.Join(ring.dir, keyRingManifest))
+	if errors.Is(err, os.ErrNotExist) {
+		// belum ada key di disk, key yang sudah dimuat tetap dipakai
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading key ring manifest: %w", err)
+	}
+
+	var keys []*SigningKey
+	if err := json.Unmarshal(manifest, &keys); err != nil {
+		return fmt.Errorf("error parsing key ring manifest: %w", err)
+	}
+	for _, key := range keys {
+		// manifests written before RetiresAt was a pointer store the
+		// active key with a zero time
+		if key.RetiresAt != nil && key.RetiresAt.IsZero() {
+			key.RetiresAt = nil
+		}
+		key.PrivateKey, err = readPrivateKey(filepath.Join(ring.dir, key.KID+".pem"))
+		if err != nil {
+			return err
+		}
+	}
+	ring.keys = keys
+	return nil
+}
+
+func (ring *KeyRing) save(created *SigningKey) error {
+	if err := os.MkdirAll(ring.dir, 0700); err != nil {
+		return fmt.Errorf("error creating key ring directory: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(created.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("error encoding private key: %w", err)
+	}
+	block := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(ring.dir, created.KID+".pem"), block, 0600); err != nil {
+		return fmt.Errorf("error writing private key: %w", err)
+	}
+
+	manifest, err := json.MarshalIndent(ring.keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write then rename so readers never see a partial manifest
+	tmp := filepath.Join(ring.dir, keyRingManifest+".tmp")
+	if err := os.WriteFile(tmp, manifest, 0600); err != nil {
+		return fmt.Errorf("error writing key ring manifest: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(ring.dir, keyRingManifest))
+}
+
+func generateSigningKey(algorithm string) (*SigningKey, error) {
+	var signer crypto.Signer
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer = private
+	case AlgorithmRS256:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		signer = private
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	return &SigningKey{
+		KID:        GenerateIDbyKSUID().String(),
+		Algorithm:  algorithm,
+		CreatedAt:  time.Now(),
+		PrivateKey: signer,
+	}, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading private key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key %s: %w", path, err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type in %s", path)
+	}
+	return signer, nil
+}
@@ -2,6 +2,18 @@ package utils
 
 import "github.com/segmentio/ksuid"
 
+// IDGenerator creates the IDs of new records.
+type IDGenerator interface {
+	NewID() ksuid.KSUID
+}
+
+// KSUIDGenerator creates random, time ordered KSUIDs.
+type KSUIDGenerator struct{}
+
+func (KSUIDGenerator) NewID() ksuid.KSUID {
+	return ksuid.New()
+}
+
 func GenerateIDbyKSUID() ksuid.KSUID {
 	return ksuid.New()
 }
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// fileMailer writes every message as an .eml file, handy in development
+// to open the emails in a mail client.
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir string, from string) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (mailer *fileMailer) Send(ctx context.Context, msg *Message) error {
+	raw, err := buildMIME(mailer.from, msg)
+	if err != nil {
+		return fmt.Errorf("error building message: %w", err)
+	}
+	if err := os.MkdirAll(mailer.dir, 0755); err != nil {
+		return fmt.Errorf("error creating mail folder: %w", err)
+	}
+	path := filepath.Join(mailer.dir, fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), ksuid.New().String()))
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("error writing mail file: %w", err)
+	}
+	log.Printf("Saved email to %s in %s\n", msg.To, path)
+	return nil
+}
+
+// logMailer only prints the plain text part.
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (mailer *logMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// MemoryMailer keeps the sent messages in memory, for tests and tools
+// that check what would have been emailed.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (mailer *MemoryMailer) Send(ctx context.Context, msg *Message) error {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	mailer.sent = append(mailer.sent, *msg)
+	return nil
+}
+
+// Sent returns a copy of the messages sent so far, oldest first.
+func (mailer *MemoryMailer) Sent() []Message {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	return append([]Message(nil), mailer.sent...)
+}
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oriastanjung/stellar/internal/config"
+)
+
+// Message is a rendered email with HTML and plain text alternatives.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer delivers a message, implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Pinger is implemented by mailers that talk to a server, the readiness
+// probe uses it to check the server can be reached.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// New builds the mailer for cfg.MailDriver: smtp, file (writes .eml files
+// to MAIL_FILE_DIR) or log (prints the plain text part).
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		return NewSMTPMailer(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+			TLS:      cfg.SMTPTLS,
+		})
+	case "file":
+		return NewFileMailer(cfg.MailFileDir, cfg.MailFrom), nil
+	case "log":
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MailDriver)
+	}
+}
@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/metrics"
+	"github.com/oriastanjung/stellar/internal/utils/queue"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+// claimLease keeps a claimed email from being picked up by another worker
+// while it is being sent
+const claimLease = 5 * time.Minute
+
+var retryBackoff = queue.Backoff{Base: 30 * time.Second, Max: time.Hour}
+
+// NewOutboxEmail turns a rendered message into an outbox row, save it in
+// the same transaction as the change that triggered the email.
+func NewOutboxEmail(msg *Message) *entities.OutboxEmail {
+	return &entities.OutboxEmail{
+		ID:            ksuid.New(),
+		To:            msg.To,
+		Subject:       msg.Subject,
+		HTMLBody:      msg.HTML,
+		TextBody:      msg.Text,
+		Status:        entities.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// OutboxWorker sends pending outbox emails, failures are retried with
+// exponential backoff until maxAttempts is reached.
+type OutboxWorker struct {
+	db           *gorm.DB
+	mailer       Mailer
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+}
+
+func NewOutboxWorker(db *gorm.DB, mailer Mailer, pollInterval time.Duration, batchSize int, maxAttempts int) *OutboxWorker {
+	return &OutboxWorker{
+		db:           db,
+		mailer:       mailer,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		maxAttempts:  maxAttempts,
+	}
+}
+
+// Run processes the outbox until ctx is cancelled, the batch being
+// processed then is finished first.
+func (worker *OutboxWorker) Run(ctx context.Context) {
+	queue.Poll(ctx, "mail outbox", worker.pollInterval, worker.batchSize, worker.ProcessBatch)
+}
+
+// ProcessBatch claims up to batchSize due emails and sends them, it returns
+// how many were claimed.
+func (worker *OutboxWorker) ProcessBatch(ctx context.Context) (int, error) {
+	emails, err := queue.Claim(ctx, worker.db, entities.OutboxStatusPending, worker.batchSize, claimLease,
+		func(email *entities.OutboxEmail) ksuid.KSUID { return email.ID }, nil)
+	if err != nil {
+		return 0, err
+	}
+	for i := range emails {
+		worker.send(ctx, &emails[i])
+	}
+	return len(emails), nil
+}
+
+func (worker *OutboxWorker) send(ctx context.Context, email *entities.OutboxEmail) {
+	err := worker.mailer.Send(ctx, &Message{
+		To:      email.To,
+		Subject: email.Subject,
+		HTML:    email.HTMLBody,
+		Text:    email.TextBody,
+	})
+
+	now := time.Now()
+	attempts := email.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+	switch {
+	case err == nil:
+		metrics.Emails.WithLabelValues("sent").Inc()
+		updates["status"] = entities.OutboxStatusSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+	case attempts >= worker.maxAttempts:
+		log.Printf("Giving up sending email %s to %s after %d attempts: %v", email.ID, email.To, attempts, err)
+		metrics.Emails.WithLabelValues("failed").Inc()
+		updates["status"] = entities.OutboxStatusFailed
+		updates["last_error"] = err.Error()
+	default:
+		log.Printf("Error sending email %s to %s, retrying: %v", email.ID, email.To, err)
+		metrics.Emails.WithLabelValues("retry").Inc()
+		updates["next_attempt_at"] = now.Add(retryBackoff.Delay(attempts))
+		updates["last_error"] = err.Error()
+	}
+
+	if err := queue.Save[entities.OutboxEmail](worker.db, email.ID, updates); err != nil {
+		log.Printf("Error updating outbox email %s: %v", email.ID, err)
+	}
+}
@@ -0,0 +1,137 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/database/databasetest"
+	"github.com/oriastanjung/stellar/internal/entities"
+	"gorm.io/gorm"
+)
+
+// scriptedMailer fails while failures is above zero, then records the
+// messages it is given.
+type scriptedMailer struct {
+	mu       sync.Mutex
+	failures int
+	sent     []*Message
+}
+
+func (mailer *scriptedMailer) Send(ctx context.Context, msg *Message) error {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+	if mailer.failures > 0 {
+		mailer.failures--
+		return errors.New("421 service not available")
+	}
+	mailer.sent = append(mailer.sent, msg)
+	return nil
+}
+
+func loadOutboxEmail(t *testing.T, db *gorm.DB, email *entities.OutboxEmail) entities.OutboxEmail {
+	t.Helper()
+	var saved entities.OutboxEmail
+	if err := db.Where("id = ?", email.ID).First(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+// makeDue moves the retry of email to now, as if the backoff had passed.
+func makeDue(t *testing.T, db *gorm.DB, email *entities.OutboxEmail) {
+	t.Helper()
+	if err := db.Model(&entities.OutboxEmail{}).Where("id = ?", email.ID).Update("next_attempt_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutboxWorkerProcessBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxAttempts  int
+		wantStatus   entities.OutboxStatus
+		wantAttempts int
+		wantSent     int
+	}{
+		{name: "sent right away", failures: 0, maxAttempts: 3, wantStatus: entities.OutboxStatusSent, wantAttempts: 1, wantSent: 1},
+		{name: "sent after retries", failures: 2, maxAttempts: 3, wantStatus: entities.OutboxStatusSent, wantAttempts: 3, wantSent: 1},
+		{name: "gives up", failures: 5, maxAttempts: 3, wantStatus: entities.OutboxStatusFailed, wantAttempts: 3, wantSent: 0},
+	}
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mailer := &scriptedMailer{failures: tt.failures}
+				worker := NewOutboxWorker(db, mailer, time.Minute, 10, tt.maxAttempts)
+				email := NewOutboxEmail(&Message{To: "budi@example.com", Subject: tt.name, HTML: "<p>Halo</p>", Text: "Halo"})
+				if err := db.Create(email).Error; err != nil {
+					t.Fatal(err)
+				}
+
+				for attempt := 1; attempt <= tt.maxAttempts+1; attempt++ {
+					claimed, err := worker.ProcessBatch(context.Background())
+					if err != nil {
+						t.Fatalf("ProcessBatch: %v", err)
+					}
+					saved := loadOutboxEmail(t, db, email)
+					if saved.Status != entities.OutboxStatusPending {
+						if claimed != 1 {
+							t.Fatalf("attempt %d claimed %d emails, want 1", attempt, claimed)
+						}
+						break
+					}
+					// a failed attempt is retried only after the backoff
+					if !saved.NextAttemptAt.After(time.Now()) || saved.LastError == "" {
+						t.Fatalf("after failed attempt %d next_attempt_at = %v, last_error = %q", attempt, saved.NextAttemptAt, saved.LastError)
+					}
+					if claimed, _ := worker.ProcessBatch(context.Background()); claimed != 0 {
+						t.Fatalf("email was claimed again %d time(s) before its retry was due", claimed)
+					}
+					makeDue(t, db, email)
+				}
+
+				saved := loadOutboxEmail(t, db, email)
+				if saved.Status != tt.wantStatus || saved.Attempts != tt.wantAttempts {
+					t.Fatalf("email status = %s after %d attempts, want %s after %d", saved.Status, saved.Attempts, tt.wantStatus, tt.wantAttempts)
+				}
+				if (saved.SentAt != nil) != (tt.wantStatus == entities.OutboxStatusSent) {
+					t.Fatalf("sent_at = %v with status %s", saved.SentAt, saved.Status)
+				}
+				if tt.wantStatus == entities.OutboxStatusFailed && saved.LastError == "" {
+					t.Fatal("failed email has no last_error")
+				}
+				if len(mailer.sent) != tt.wantSent {
+					t.Fatalf("mailer sent %d messages, want %d", len(mailer.sent), tt.wantSent)
+				}
+			})
+		}
+	})
+}
+
+func TestOutboxWorkerBatchSize(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T, db *gorm.DB) {
+		mailer := &scriptedMailer{}
+		worker := NewOutboxWorker(db, mailer, time.Minute, 2, 3)
+		for range 3 {
+			if err := db.Create(NewOutboxEmail(&Message{To: "budi@example.com", Subject: "Halo", Text: "Halo"})).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, want := range []int{2, 1, 0} {
+			claimed, err := worker.ProcessBatch(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimed != want {
+				t.Fatalf("ProcessBatch claimed %d emails, want %d", claimed, want)
+			}
+		}
+		if len(mailer.sent) != 3 {
+			t.Fatalf("mailer sent %d messages, want 3", len(mailer.sent))
+		}
+	})
+}
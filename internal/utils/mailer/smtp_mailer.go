@@ -0,0 +1,146 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// TLS modes for SMTPConfig.TLS.
+const (
+	TLSStartTLS = "starttls" // plain connection upgraded with STARTTLS, usually port 587
+	TLSImplicit = "tls"      // TLS from the first byte, usually port 465
+	TLSNone     = "none"     // only for local test servers
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      string
+	// TLSConfig overrides the default config, e.g. to trust a test CA
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+func NewSMTPMailer(config SMTPConfig) (Mailer, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	switch config.TLS {
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", config.TLS)
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail from address: %w", err)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{ServerName: config.Host}
+	}
+	return &smtpMailer{config: config, from: from}, nil
+}
+
+func (mailer *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	raw, err := buildMIME(mailer.from.String(), msg)
+	if err != nil {
+		return fmt.Errorf("error building message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailer.config.Timeout)
+	defer cancel()
+	client, err := mailer.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if mailer.config.Username != "" {
+		auth := smtp.PlainAuth("", mailer.config.Username, mailer.config.Password, mailer.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+	if err := client.Mail(mailer.from.Address); err != nil {
+		return fmt.Errorf("error on MAIL FROM: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("error on RCPT TO: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error on DATA: %w", err)
+	}
+	if _, err := writer.Write(raw); err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error finishing message: %w", err)
+	}
+	return client.Quit()
+}
+
+// Ping opens a session, including STARTTLS, and quits without sending.
+func (mailer *smtpMailer) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, mailer.config.Timeout)
+	defer cancel()
+	client, err := mailer.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
+
+func (mailer *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(mailer.config.Host, strconv.Itoa(mailer.config.Port))
+	dialer := &net.Dialer{}
+
+	var conn net.Conn
+	var err error
+	if mailer.config.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: mailer.config.TLSConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, mailer.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error starting smtp session: %w", err)
+	}
+	if mailer.config.TLS == TLSStartTLS {
+		if err := client.StartTLS(mailer.config.TLSConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("error on STARTTLS: %w", err)
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,183 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/oriastanjung/stellar/internal/utils/mailer/smtptest"
+)
+
+func startSMTP(t *testing.T, starttls bool) (*smtptest.Server, SMTPConfig) {
+	t.Helper()
+	start := smtptest.Start
+	if starttls {
+		start = smtptest.StartWithSTARTTLS
+	}
+	server, err := start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	host, port, _ := net.SplitHostPort(server.Addr)
+	portNumber, _ := strconv.Atoi(port)
+	return server, SMTPConfig{
+		Host:      host,
+		Port:      portNumber,
+		Username:  "stellar",
+		Password:  "secret",
+		From:      "Stellar <no-reply@stellar.test>",
+		TLSConfig: server.ClientTLSConfig(),
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	tests := []struct {
+		name     string
+		tls      string
+		starttls bool
+	}{
+		{name: "starttls", tls: TLSStartTLS, starttls: true},
+		{name: "none", tls: TLSNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, config := startSMTP(t, tt.starttls)
+			config.TLS = tt.tls
+			smtpMailer, err := NewSMTPMailer(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = smtpMailer.Send(context.Background(), &Message{
+				To:      "Budi <budi@example.com>",
+				Subject: "Verifikasi email Anda ✓",
+				HTML:    "<p>Klik <a href=\"https://stellar.test/verify\">tautan ini</a></p>",
+				Text:    "Klik tautan ini: https://stellar.test/verify",
+			})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			messages := server.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("server received %d messages, want 1", len(messages))
+			}
+			received := messages[0]
+			if received.TLS != tt.starttls {
+				t.Fatalf("message sent with TLS = %t, want %t", received.TLS, tt.starttls)
+			}
+			if received.From != "no-reply@stellar.test" || len(received.To) != 1 || received.To[0] != "budi@example.com" {
+				t.Fatalf("envelope from %s to %v", received.From, received.To)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerMultipartBody(t *testing.T) {
+	server, config := startSMTP(t, false)
+	config.TLS = TLSNone
+	smtpMailer, _ := NewSMTPMailer(config)
+	msg := &Message{
+		To:      "budi@example.com",
+		Subject: "Kata sandi diubah ✓",
+		HTML:    "<p>Kata sandi Anda telah diubah.</p>",
+		Text:    "Kata sandi Anda telah diubah. Baris ini cukup panjang sehingga quoted-printable harus memotongnya menjadi beberapa baris.",
+	}
+	if err := smtpMailer.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(server.Messages()[0].Data))
+	if err != nil {
+		t.Fatalf("message does not parse: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Fatalf("Subject = %q, %v, want %q", subject, err, msg.Subject)
+	}
+	if parsed.Header.Get("Message-ID") == "" || parsed.Header.Get("Date") == "" {
+		t.Fatal("Message-ID or Date header is missing")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", parsed.Header.Get("Content-Type"))
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	// the plain text part comes first for clients without HTML support
+	for _, want := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		if part.Header.Get("Content-Type") != want.contentType {
+			t.Fatalf("part Content-Type = %q, want %q", part.Header.Get("Content-Type"), want.contentType)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != want.body {
+			t.Fatalf("%s part = %q, want %q", want.contentType, body, want.body)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Fatalf("message has more than two parts: %v", err)
+	}
+}
+
+func TestSMTPMailerRequiresSTARTTLS(t *testing.T) {
+	// a server without STARTTLS must not receive the message in plain text
+	server, config := startSMTP(t, false)
+	config.TLS = TLSStartTLS
+	smtpMailer, _ := NewSMTPMailer(config)
+	err := smtpMailer.Send(context.Background(), &Message{To: "budi@example.com", Subject: "Halo", Text: "Halo", HTML: "<p>Halo</p>"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Send = %v, want a STARTTLS error", err)
+	}
+	if len(server.Messages()) != 0 {
+		t.Fatal("message was sent without TLS")
+	}
+}
+
+func TestSMTPMailerPing(t *testing.T) {
+	_, config := startSMTP(t, true)
+	config.TLS = TLSStartTLS
+	smtpMailer, _ := NewSMTPMailer(config)
+	if err := smtpMailer.(Pinger).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+}
+
+func TestNewSMTPMailerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  SMTPConfig
+		wantErr bool
+	}{
+		{name: "valid", config: SMTPConfig{Host: "smtp.example.com", From: "no-reply@example.com", TLS: TLSImplicit}},
+		{name: "missing host", config: SMTPConfig{From: "no-reply@example.com", TLS: TLSStartTLS}, wantErr: true},
+		{name: "unknown tls mode", config: SMTPConfig{Host: "smtp.example.com", From: "no-reply@example.com", TLS: "ssl"}, wantErr: true},
+		{name: "invalid from", config: SMTPConfig{Host: "smtp.example.com", From: "not an address", TLS: TLSNone}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := NewSMTPMailer(tt.config); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: NewSMTPMailer error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package smtptest runs a minimal in-process SMTP server that records what
+// it receives, to exercise the SMTP mailer without a real mail server.
+package smtptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Received is one message accepted by the server.
+type Received struct {
+	From string
+	To   []string
+	Data string
+	// TLS is set when the session was upgraded with STARTTLS
+	TLS bool
+}
+
+type Server struct {
+	Addr string
+
+	// tlsConfig is set when the server offers STARTTLS
+	tlsConfig   *tls.Config
+	certificate *x509.Certificate
+	listener    net.Listener
+	mu          sync.Mutex
+	messages    []Received
+	wg          sync.WaitGroup
+}
+
+// Start listens on a random localhost port, plain text AUTH PLAIN is
+// accepted because net/smtp allows it for localhost.
+func Start() (*Server, error) {
+	return start(nil)
+}
+
+// StartWithSTARTTLS is Start with STARTTLS offered, using a self-signed
+// certificate for 127.0.0.1 that ClientTLSConfig trusts.
+func StartWithSTARTTLS() (*Server, error) {
+	certificate, err := selfSigned()
+	if err != nil {
+		return nil, err
+	}
+	return start(&certificate)
+}
+
+func start(certificate *tls.Certificate) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{Addr: listener.Addr().String(), listener: listener}
+	if certificate != nil {
+		server.tlsConfig = &tls.Config{Certificates: []tls.Certificate{*certificate}}
+		server.certificate = certificate.Leaf
+	}
+	server.wg.Add(1)
+	go server.serve()
+	return server, nil
+}
+
+// ClientTLSConfig trusts the certificate of a server started with
+// StartWithSTARTTLS.
+func (server *Server) ClientTLSConfig() *tls.Config {
+	roots := x509.NewCertPool()
+	if server.certificate != nil {
+		roots.AddCert(server.certificate)
+	}
+	return &tls.Config{RootCAs: roots, ServerName: "127.0.0.1"}
+}
+
+// Messages returns a copy of the messages received so far.
+func (server *Server) Messages() []Received {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]Received{}, server.messages...)
+}
+
+func (server *Server) Close() error {
+	err := server.listener.Close()
+	server.wg.Wait()
+	return err
+}
+
+func (server *Server) serve() {
+	defer server.wg.Done()
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		server.wg.Add(1)
+		go func() {
+			defer server.wg.Done()
+			server.handle(conn)
+		}()
+	}
+}
+
+func (server *Server) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 smtptest ready")
+	var current Received
+	upgraded := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-smtptest")
+			if server.tlsConfig != nil && !upgraded {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case command == "STARTTLS" && server.tlsConfig != nil && !upgraded:
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, server.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			upgraded = true
+		case strings.HasPrefix(command, "HELO"):
+			reply("250 smtptest")
+		case strings.HasPrefix(command, "AUTH"):
+			reply("235 authenticated")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current = Received{From: addressOf(line), TLS: upgraded}
+			reply("250 ok")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			current.To = append(current.To, addressOf(line))
+			reply("250 ok")
+		case command == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				// undo dot stuffing
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			current.Data = data.String()
+			server.mu.Lock()
+			server.messages = append(server.messages, current)
+			server.mu.Unlock()
+			reply("250 queued")
+		case command == "RSET":
+			current = Received{TLS: upgraded}
+			reply("250 ok")
+		case command == "NOOP":
+			reply("250 ok")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func addressOf(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start == -1 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func selfSigned() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtptest"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
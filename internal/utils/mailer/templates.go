@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/oriastanjung/stellar/internal/i18n"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+type compiledTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var (
+	compiled   = map[string]*compiledTemplate{}
+	compiledMu sync.Mutex
+)
+
+// Render builds the message from templates/<locale>/<name>.html, wrapped in
+// layout.html, and templates/<locale>/<name>.txt, which also defines the
+// subject. Shared pieces such as the signature live in common.html and
+// common.txt. Locales without the template fall back to i18n.DefaultLocale.
+func Render(name string, locale string, to string, data interface{}) (*Message, error) {
+	locale = i18n.Normalize(locale)
+	if _, err := fs.Stat(templateFS, "templates/"+locale+"/"+name+".html"); err != nil {
+		locale = i18n.DefaultLocale
+	}
+	tmpl, err := loadTemplate(locale, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("error rendering %s subject: %w", name, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("error rendering %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("error rendering %s html: %w", name, err)
+	}
+
+	return &Message{
+		To:      to,
+		Subject: subject.String(),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+func loadTemplate(locale string, name string) (*compiledTemplate, error) {
+	compiledMu.Lock()
+	defer compiledMu.Unlock()
+	key := locale + "/" + name
+	if tmpl, ok := compiled[key]; ok {
+		return tmpl, nil
+	}
+
+	dir := "templates/" + locale + "/"
+	html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", dir+"common.html", dir+name+".html")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s html template: %w", key, err)
+	}
+	text, err := texttemplate.ParseFS(templateFS, dir+"common.txt", dir+name+".txt")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s text template: %w", key, err)
+	}
+
+	tmpl := &compiledTemplate{html: html, text: text}
+	compiled[key] = tmpl
+	return tmpl, nil
+}
@@ -0,0 +1,118 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/oriastanjung/stellar/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// githubProvider uses plain OAuth2, GitHub has no OIDC for user logins, so
+// the profile and verified email come from the REST API.
+type githubProvider struct {
+	name   string
+	apiURL string
+	oauth2 *oauth2.Config
+}
+
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func newGitHubProvider(providerConfig config.OAuthProviderConfig) *githubProvider {
+	issuer := strings.TrimSuffix(providerConfig.Issuer, "/")
+	scopes := providerConfig.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"read:user", "user:email"}
+	}
+	return &githubProvider{
+		name:   providerConfig.Name,
+		apiURL: strings.TrimSuffix(providerConfig.APIURL, "/"),
+		oauth2: &oauth2.Config{
+			RedirectURL:  providerConfig.RedirectURL,
+			ClientID:     providerConfig.ClientID,
+			ClientSecret: providerConfig.ClientSecret,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  issuer + "/login/oauth/authorize",
+				TokenURL: issuer + "/login/oauth/access_token",
+			},
+		},
+	}
+}
+
+func (provider *githubProvider) Name() string {
+	return provider.name
+}
+
+func (provider *githubProvider) AuthCodeURL(state, verifier, nonce string) (string, error) {
+	// GitHub ignores the nonce, the state and PKCE still protect the flow
+	return provider.oauth2.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (provider *githubProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	token, err := provider.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging code: %w", err)
+	}
+	client := provider.oauth2.Client(ctx, token)
+
+	var user githubUser
+	if err := provider.get(client, "/user", &user); err != nil {
+		return nil, err
+	}
+	var emails []githubEmail
+	if err := provider.get(client, "/user/emails", &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Provider: provider.name,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Picture:  user.AvatarURL,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+	return identity, nil
+}
+
+func (provider *githubProvider) get(client *http.Client, path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, provider.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling github %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code from github %s: %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,113 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/oriastanjung/stellar/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// oidcProvider works with any issuer supporting OpenID Connect discovery.
+type oidcProvider struct {
+	config config.OAuthProviderConfig
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+func newOIDCProvider(providerConfig config.OAuthProviderConfig) *oidcProvider {
+	return &oidcProvider{config: providerConfig}
+}
+
+func (provider *oidcProvider) Name() string {
+	return provider.config.Name
+}
+
+// discover runs OIDC discovery once, a failed discovery is retried on the next call.
+func (provider *oidcProvider) discover() (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.oauth2 != nil {
+		return provider.oauth2, provider.verifier, nil
+	}
+
+	// the provider keeps using this context to refresh its JWKS, so it
+	// must outlive the request that triggered discovery
+	discovered, err := oidc.NewProvider(context.Background(), provider.config.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error discovering %s: %w", provider.config.Name, err)
+	}
+
+	scopes := provider.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+	provider.oauth2 = &oauth2.Config{
+		RedirectURL:  provider.config.RedirectURL,
+		ClientID:     provider.config.ClientID,
+		ClientSecret: provider.config.ClientSecret,
+		Scopes:       scopes,
+		Endpoint:     discovered.Endpoint(),
+	}
+	provider.verifier = discovered.Verifier(&oidc.Config{ClientID: provider.config.ClientID})
+	return provider.oauth2, provider.verifier, nil
+}
+
+func (provider *oidcProvider) AuthCodeURL(state, verifier, nonce string) (string, error) {
+	oauthConfig, _, err := provider.discover()
+	if err != nil {
+		return "", err
+	}
+	return oauthConfig.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oidc.Nonce(nonce)), nil
+}
+
+func (provider *oidcProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	oauthConfig, idTokenVerifier, err := provider.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging code: %w", err)
+	}
+
+	// profile is only trusted from the signed ID token
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("id token missing")
+	}
+	idToken, err := idTokenVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("invalid id token nonce")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid id token claims: %w", err)
+	}
+
+	return &Identity{
+		Provider:      provider.config.Name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/oriastanjung/stellar/internal/config"
+)
+
+// ErrUnknownProvider is returned for provider names not in the registry.
+var ErrUnknownProvider = errors.New("unknown oauth provider")
+
+// Identity is the verified profile returned by a provider after the code exchange.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// Provider is a social login provider. The state, PKCE verifier and nonce
+// are generated and stored by the caller.
+type Provider interface {
+	Name() string
+	AuthCodeURL(state, verifier, nonce string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error)
+}
+
+// Registry holds the configured providers by name, Reload swaps them
+// while logins are running.
+type Registry struct {
+	providers atomic.Pointer[map[string]Provider]
+}
+
+func NewRegistry(providerConfigs []config.OAuthProviderConfig) (*Registry, error) {
+	registry := &Registry{}
+	if err := registry.Reload(providerConfigs); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Reload replaces the providers, logins already started keep working as
+// long as their provider is still configured. The registry is unchanged
+// when a provider config is invalid.
+func (registry *Registry) Reload(providerConfigs []config.OAuthProviderConfig) error {
+	providers := map[string]Provider{}
+	for _, providerConfig := range providerConfigs {
+		var provider Provider
+		switch providerConfig.Type {
+		case "oidc":
+			provider = newOIDCProvider(providerConfig)
+		case "github":
+			provider = newGitHubProvider(providerConfig)
+		default:
+			return fmt.Errorf("oauth provider %s has unsupported type %q", providerConfig.Name, providerConfig.Type)
+		}
+		providers[providerConfig.Name] = provider
+	}
+	registry.providers.Store(&providers)
+	return nil
+}
+
+func (registry *Registry) Get(name string) (Provider, error) {
+	provider, ok := (*registry.providers.Load())[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	return provider, nil
+}
+
+func (registry *Registry) Names() []string {
+	providers := *registry.providers.Load()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,99 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+//go:embed breached_sha1.txt
+var bundledBreachedList string
+
+// BreachedSource returns the SHA-1 suffixes of breached passwords sharing a
+// 5 character prefix, the full hash of the password never leaves the checker.
+type BreachedSource interface {
+	Range(prefix string) ([]string, error)
+}
+
+// rangeList is an in-memory BreachedSource built from a hash list.
+type rangeList map[string][]string
+
+func (list rangeList) Range(prefix string) ([]string, error) {
+	return list[prefix], nil
+}
+
+// parseRangeList reads one uppercase or lowercase SHA-1 hash per line, an
+// optional ":count" suffix as found in public breach dumps is ignored.
+func parseRangeList(reader io.Reader) (rangeList, error) {
+	list := rangeList{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("invalid breached password hash %q", hash)
+		}
+		hash = strings.ToUpper(hash)
+		list[hash[:5]] = append(list[hash[:5]], hash[5:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+var (
+	breachedSources   = map[string]BreachedSource{}
+	breachedSourcesMu sync.Mutex
+)
+
+// getBreachedSource returns the list in path, or the bundled list when path is empty.
+func getBreachedSource(path string) (BreachedSource, error) {
+	breachedSourcesMu.Lock()
+	defer breachedSourcesMu.Unlock()
+	if source, ok := breachedSources[path]; ok {
+		return source, nil
+	}
+
+	var reader io.Reader = strings.NewReader(bundledBreachedList)
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening breached password list: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+	source, err := parseRangeList(reader)
+	if err != nil {
+		return nil, err
+	}
+	breachedSources[path] = source
+	return source, nil
+}
+
+// IsBreached reports whether password appears in source.
+func IsBreached(source BreachedSource, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := source.Range(hash[:5])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if strings.EqualFold(suffix, hash[5:]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,144 @@
+package password
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oriastanjung/stellar/internal/apperror"
+	"github.com/oriastanjung/stellar/internal/config"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// Rule names reported in the error details.
+const (
+	RuleMinLength        = "min_length"
+	RuleMaxLength        = "max_length"
+	RuleUppercase        = "uppercase"
+	RuleLowercase        = "lowercase"
+	RuleDigit            = "digit"
+	RuleSymbol           = "symbol"
+	RuleContainsEmail    = "contains_email"
+	RuleContainsUsername = "contains_username"
+	RuleBreached         = "breached"
+)
+
+type Policy struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	CheckBreached  bool
+	BreachedSource BreachedSource
+}
+
+type Violation struct {
+	Rule        string
+	Description string
+}
+
+// PolicyFromConfig builds the policy from the PASSWORD_* settings. A
+// breached list that cannot be loaded disables only the breached check.
+func PolicyFromConfig(cfg *config.Config) Policy {
+	policy := Policy{
+		MinLength:     cfg.PasswordMinLength,
+		MaxLength:     cfg.PasswordMaxLength,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		CheckBreached: cfg.PasswordCheckBreached,
+	}
+	if policy.CheckBreached {
+		source, err := getBreachedSource(cfg.PasswordBreachedListFile)
+		if err != nil {
+			log.Printf("Error loading breached password list: %v", err)
+			policy.CheckBreached = false
+		}
+		policy.BreachedSource = source
+	}
+	return policy
+}
+
+// Check returns every rule the password violates, email and username may be empty.
+func (policy Policy) Check(password, email, username string) []Violation {
+	violations := []Violation{}
+	length := utf8.RuneCountInString(password)
+
+	if length < policy.MinLength {
+		violations = append(violations, Violation{RuleMinLength, fmt.Sprintf("Password must be at least %d characters", policy.MinLength)})
+	}
+	// bcrypt only uses the first 72 bytes
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		violations = append(violations, Violation{RuleMaxLength, fmt.Sprintf("Password must be at most %d bytes", policy.MaxLength)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{RuleUppercase, "Password must contain an uppercase letter"})
+	}
+	if policy.RequireLower && !hasLower {
+		violations = append(violations, Violation{RuleLowercase, "Password must contain a lowercase letter"})
+	}
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{RuleDigit, "Password must contain a digit"})
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{RuleSymbol, "Password must contain a symbol"})
+	}
+
+	lowered := strings.ToLower(password)
+	localPart, _, _ := strings.Cut(strings.ToLower(email), "@")
+	if len(localPart) >= 3 && strings.Contains(lowered, localPart) {
+		violations = append(violations, Violation{RuleContainsEmail, "Password must not contain your email"})
+	}
+	if name := strings.ToLower(strings.TrimSpace(username)); len(name) >= 3 && strings.Contains(lowered, name) {
+		violations = append(violations, Violation{RuleContainsUsername, "Password must not contain your username"})
+	}
+
+	if policy.CheckBreached && policy.BreachedSource != nil && password != "" {
+		breached, err := IsBreached(policy.BreachedSource, password)
+		if err != nil {
+			log.Printf("Error checking breached password: %v", err)
+		} else if breached {
+			violations = append(violations, Violation{RuleBreached, "Password has appeared in a data breach"})
+		}
+	}
+
+	return violations
+}
+
+// Validate returns an InvalidArgument error listing the violated rules, or nil.
+func (policy Policy) Validate(password, email, username string) error {
+	violations := policy.Check(password, email, username)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	rules := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "password",
+			Description: violation.Description,
+		})
+		rules = append(rules, violation.Rule)
+	}
+	return apperror.ErrPasswordPolicy.WithMetadata("rules", strings.Join(rules, ",")).WithDetails(badRequest)
+}
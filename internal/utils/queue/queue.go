@@ -0,0 +1,99 @@
+// Package queue has the polling loop shared by the workers that use a
+// table as a job queue, such as the mail outbox and the webhook
+// deliveries. Rows are picked up once their next_attempt_at is due.
+package queue
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Poll calls process until ctx is cancelled, right away and then every
+// interval. process returns how many rows it claimed, a full batch means
+// more are probably due so it is called again without waiting.
+//
+// process gets a context that is never cancelled, so the batch being
+// processed at shutdown is finished and no claimed row waits for its
+// lease to expire.
+func Poll(ctx context.Context, name string, interval time.Duration, batchSize int, process func(ctx context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	batchCtx := context.WithoutCancel(ctx)
+	for {
+		for ctx.Err() == nil {
+			claimed, err := process(batchCtx)
+			if err != nil {
+				log.Printf("Error processing %s: %v", name, err)
+				break
+			}
+			if claimed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Claim locks up to limit due rows of T whose status is pending and moves
+// their next_attempt_at lease into the future, so other workers skip them
+// while they are processed. Locked rows are skipped instead of waited for.
+// load, when not nil, runs in the same transaction to fetch related rows.
+func Claim[T any](ctx context.Context, db *gorm.DB, pending interface{}, limit int, lease time.Duration, id func(row *T) ksuid.KSUID, load func(tx *gorm.DB, rows []T) error) ([]T, error) {
+	var rows []T
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", pending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		ids := make([]ksuid.KSUID, len(rows))
+		for i := range rows {
+			ids[i] = id(&rows[i])
+		}
+		if load != nil {
+			if err := load(tx, rows); err != nil {
+				return err
+			}
+		}
+		return tx.Model(new(T)).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return rows, err
+}
+
+// Save records the outcome of an attempt on the row of T with id. It
+// ignores the context of the batch on purpose: a processed row left
+// pending would be processed twice.
+func Save[T any](db *gorm.DB, id ksuid.KSUID, updates map[string]interface{}) error {
+	return db.Model(new(T)).Where("id = ?", id).Updates(updates).Error
+}
+
+// Backoff doubles the delay before every retry, starting at Base and
+// never waiting longer than Max.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay is the wait before the next attempt after attempts failed ones.
+func (backoff Backoff) Delay(attempts int) time.Duration {
+	if attempts > 20 {
+		return backoff.Max
+	}
+	return min(backoff.Base<<(attempts-1), backoff.Max)
+}
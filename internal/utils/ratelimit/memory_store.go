@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps buckets in process, suitable for single instance deploys.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweeps  int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (store *MemoryStore) Allow(ctx context.Context, key string, rule Rule) (bool, time.Duration, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+	current, ok := store.buckets[key]
+	if !ok {
+		current = &bucket{tokens: float64(rule.Burst), updatedAt: now}
+		store.buckets[key] = current
+	}
+
+	tokens, allowed, wait := take(current.tokens, current.updatedAt, now, rule)
+	current.tokens = tokens
+	current.updatedAt = now
+
+	store.sweep(now)
+	return allowed, wait, nil
+}
+
+// sweep drops idle buckets every few thousand calls so keys such as
+// spoofed emails cannot grow the map without bound.
+func (store *MemoryStore) sweep(now time.Time) {
+	store.sweeps++
+	if store.sweeps < 4096 {
+		return
+	}
+	store.sweeps = 0
+	for key, current := range store.buckets {
+		if now.Sub(current.updatedAt) > time.Hour {
+			delete(store.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore shares buckets between instances through the
+// rate_limit_buckets table, rows are locked while a token is taken.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (store *PostgresStore) Allow(ctx context.Context, key string, rule Rule) (bool, time.Duration, error) {
+	var allowed bool
+	var wait time.Duration
+
+	err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// make sure the row exists so it can be locked
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.RateLimitBucket{
+			Key:       key,
+			Tokens:    float64(rule.Burst),
+			UpdatedAt: now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var current entities.RateLimitBucket
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&current).Error
+		if err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, allowed, wait = take(current.Tokens, current.UpdatedAt, now, rule)
+		return tx.Model(&entities.RateLimitBucket{}).Where("key = ?", key).
+			Updates(map[string]interface{}{"tokens": tokens, "updated_at": now}).Error
+	})
+	return allowed, wait, err
+}
+
+// Cleanup removes buckets idle for longer than maxIdle.
+func (store *PostgresStore) Cleanup(ctx context.Context, maxIdle time.Duration) error {
+	return store.db.WithContext(ctx).Where("updated_at < ?", time.Now().Add(-maxIdle)).Delete(&entities.RateLimitBucket{}).Error
+}
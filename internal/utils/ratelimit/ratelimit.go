@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule is a token bucket refilled at Rate tokens per second up to Burst.
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Store keeps token buckets by key. Allow takes one token and reports how
+// long to wait for the next one when the bucket is empty.
+type Store interface {
+	Allow(ctx context.Context, key string, rule Rule) (bool, time.Duration, error)
+}
+
+// take applies the token bucket algorithm to a bucket last seen at updatedAt.
+func take(tokens float64, updatedAt time.Time, now time.Time, rule Rule) (float64, bool, time.Duration) {
+	elapsed := now.Sub(updatedAt).Seconds()
+	if elapsed > 0 {
+		tokens = math.Min(float64(rule.Burst), tokens+elapsed*rule.Rate)
+	}
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+	if rule.Rate <= 0 {
+		return tokens, false, time.Hour
+	}
+	wait := time.Duration((1 - tokens) / rule.Rate * float64(time.Second))
+	return tokens, false, wait
+}
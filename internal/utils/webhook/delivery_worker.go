@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oriastanjung/stellar/internal/entities"
+	"github.com/oriastanjung/stellar/internal/utils"
+	"github.com/oriastanjung/stellar/internal/utils/queue"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// claimLease keeps a claimed delivery from being picked up by another
+	// worker while the endpoint is being called
+	claimLease = 5 * time.Minute
+	// maxErrorLength trims response bodies saved in the delivery log
+	maxErrorLength = 1024
+)
+
+var retryBackoff = queue.Backoff{Base: 30 * time.Second, Max: 6 * time.Hour}
+
+// DeliveryWorker posts pending webhook deliveries, failures (network errors
+// and non 2xx responses) are retried with exponential backoff until
+// maxAttempts is reached.
+type DeliveryWorker struct {
+	db           *gorm.DB
+	cipher       *utils.AESCipher
+	client       *http.Client
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+}
+
+// NewDeliveryWorker creates the worker, allowInsecure lets it call
+// endpoints on private addresses (see ValidateURL). cipher opens the
+// webhook secrets sealed by the webhook usecase.
+func NewDeliveryWorker(db *gorm.DB, cipher *utils.AESCipher, timeout time.Duration, allowInsecure bool, pollInterval time.Duration, batchSize int, maxAttempts int) *DeliveryWorker {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// never use HTTP_PROXY, the dialer must see the endpoint address
+	transport.Proxy = nil
+	if !allowInsecure {
+		transport.DialContext = (&net.Dialer{Timeout: timeout, Control: dialControl}).DialContext
+	}
+	return &DeliveryWorker{
+		db:     db,
+		cipher: cipher,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// a redirect could point the signed payload somewhere else
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		maxAttempts:  maxAttempts,
+	}
+}
+
+// Run processes pending deliveries until ctx is cancelled. Deliveries
+// already claimed are still posted and recorded before it returns.
+func (worker *DeliveryWorker) Run(ctx context.Context) {
+	queue.Poll(ctx, "webhook deliveries", worker.pollInterval, worker.batchSize, worker.ProcessBatch)
+}
+
+// ProcessBatch claims up to batchSize due deliveries and posts them, it
+// returns how many were claimed.
+func (worker *DeliveryWorker) ProcessBatch(ctx context.Context) (int, error) {
+	webhooks := map[ksuid.KSUID]*entities.Webhook{}
+	deliveries, err := queue.Claim(ctx, worker.db, entities.WebhookDeliveryPending, worker.batchSize, claimLease,
+		func(delivery *entities.WebhookDelivery) ksuid.KSUID { return delivery.ID },
+		func(tx *gorm.DB, deliveries []entities.WebhookDelivery) error {
+			webhookIDs := make([]ksuid.KSUID, len(deliveries))
+			for i, delivery := range deliveries {
+				webhookIDs[i] = delivery.WebhookID
+			}
+			var found []entities.Webhook
+			if err := tx.Where("id IN ?", webhookIDs).Find(&found).Error; err != nil {
+				return err
+			}
+			for i := range found {
+				webhooks[found[i].ID] = &found[i]
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		worker.deliver(ctx, &deliveries[i], webhooks[deliveries[i].WebhookID])
+	}
+	return len(deliveries), nil
+}
+
+func (worker *DeliveryWorker) deliver(ctx context.Context, delivery *entities.WebhookDelivery, webhook *entities.Webhook) {
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts}
+
+	status, err := worker.post(ctx, delivery, webhook)
+	updates["response_status"] = status
+	switch {
+	case err == nil:
+		updates["status"] = entities.WebhookDeliverySucceeded
+		updates["delivered_at"] = now
+		updates["last_error"] = ""
+	case webhook == nil || attempts >= worker.maxAttempts:
+		log.Printf("Giving up webhook delivery %s after %d attempts: %v", delivery.ID, attempts, err)
+		updates["status"] = entities.WebhookDeliveryFailed
+		updates["last_error"] = err.Error()
+	default:
+		log.Printf("Error delivering webhook %s to %s, retrying: %v", delivery.ID, webhook.URL, err)
+		updates["next_attempt_at"] = now.Add(retryBackoff.Delay(attempts))
+		updates["last_error"] = err.Error()
+	}
+
+	if err := queue.Save[entities.WebhookDelivery](worker.db, delivery.ID, updates); err != nil {
+		log.Printf("Error updating webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// post returns the response status code, 0 when no response was received.
+func (worker *DeliveryWorker) post(ctx context.Context, delivery *entities.WebhookDelivery, webhook *entities.Webhook) (int, error) {
+	if webhook == nil {
+		return 0, fmt.Errorf("webhook %s was deleted", delivery.WebhookID)
+	}
+	secret, err := worker.cipher.Open(webhook.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("error opening webhook secret: %w", err)
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Stellar-Webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+
+	resp, err := worker.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		// postgres rejects invalid UTF-8 and NUL bytes in text columns
+		text := strings.ReplaceAll(strings.ToValidUTF8(string(respBody), ""), "\x00", "")
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, text)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+var errPrivateAddress = errors.New("webhook url resolves to a private address")
+
+// ValidateURL accepts absolute https URLs whose host is not a loopback or
+// private address. allowInsecure also accepts http and private hosts so
+// endpoints on a developer machine can be used.
+func ValidateURL(raw string, allowInsecure bool) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if parsed.Host == "" || parsed.User != nil {
+		return fmt.Errorf("webhook url must be absolute without credentials")
+	}
+	if allowInsecure {
+		if parsed.Scheme != "https" && parsed.Scheme != "http" {
+			return fmt.Errorf("unsupported webhook url scheme %q", parsed.Scheme)
+		}
+		return nil
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("webhook url must use https")
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivate(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// dialControl refuses connections to private addresses, the check at
+// creation cannot catch hostnames that later resolve to one.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
@@ -27,23 +27,57 @@ const (
 	AuthServiceRoutes_VerifyUser_FullMethodName                 = "/auth.AuthServiceRoutes/VerifyUser"
 	AuthServiceRoutes_RequestForgetPassword_FullMethodName      = "/auth.AuthServiceRoutes/RequestForgetPassword"
 	AuthServiceRoutes_ResetPasswordByToken_FullMethodName       = "/auth.AuthServiceRoutes/ResetPasswordByToken"
+	AuthServiceRoutes_UnlockAccount_FullMethodName              = "/auth.AuthServiceRoutes/UnlockAccount"
+	AuthServiceRoutes_ResendVerificationEmail_FullMethodName    = "/auth.AuthServiceRoutes/ResendVerificationEmail"
+	AuthServiceRoutes_UpdatePreferredLocale_FullMethodName      = "/auth.AuthServiceRoutes/UpdatePreferredLocale"
+	AuthServiceRoutes_UpdateSubscription_FullMethodName         = "/auth.AuthServiceRoutes/UpdateSubscription"
 	AuthServiceRoutes_LoginUserViaGoogle_FullMethodName         = "/auth.AuthServiceRoutes/LoginUserViaGoogle"
 	AuthServiceRoutes_LoginUserViaGoogleCallback_FullMethodName = "/auth.AuthServiceRoutes/LoginUserViaGoogleCallback"
+	AuthServiceRoutes_EnrollTOTP_FullMethodName                 = "/auth.AuthServiceRoutes/EnrollTOTP"
+	AuthServiceRoutes_ConfirmTOTP_FullMethodName                = "/auth.AuthServiceRoutes/ConfirmTOTP"
+	AuthServiceRoutes_VerifyMFA_FullMethodName                  = "/auth.AuthServiceRoutes/VerifyMFA"
+	AuthServiceRoutes_DisableTOTP_FullMethodName                = "/auth.AuthServiceRoutes/DisableTOTP"
+	AuthServiceRoutes_LoginViaProvider_FullMethodName           = "/auth.AuthServiceRoutes/LoginViaProvider"
+	AuthServiceRoutes_LoginViaProviderCallback_FullMethodName   = "/auth.AuthServiceRoutes/LoginViaProviderCallback"
+	AuthServiceRoutes_StartLinkIdentity_FullMethodName          = "/auth.AuthServiceRoutes/StartLinkIdentity"
+	AuthServiceRoutes_LinkIdentity_FullMethodName               = "/auth.AuthServiceRoutes/LinkIdentity"
+	AuthServiceRoutes_UnlinkIdentity_FullMethodName             = "/auth.AuthServiceRoutes/UnlinkIdentity"
+	AuthServiceRoutes_ListIdentities_FullMethodName             = "/auth.AuthServiceRoutes/ListIdentities"
+	AuthServiceRoutes_CreateAdminInvitation_FullMethodName      = "/auth.AuthServiceRoutes/CreateAdminInvitation"
+	AuthServiceRoutes_ListAdminInvitations_FullMethodName       = "/auth.AuthServiceRoutes/ListAdminInvitations"
+	AuthServiceRoutes_RevokeAdminInvitation_FullMethodName      = "/auth.AuthServiceRoutes/RevokeAdminInvitation"
 )
 
 // AuthServiceRoutesClient is the client API for AuthServiceRoutes service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AuthServiceRoutesClient interface {
-	SignUpAdmin(ctx context.Context, in *SignUpRequest, opts ...grpc.CallOption) (*SignUpResponse, error)
+	SignUpAdmin(ctx context.Context, in *SignUpAdminRequest, opts ...grpc.CallOption) (*SignUpResponse, error)
 	LoginAdmin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	SignUpUser(ctx context.Context, in *SignUpRequest, opts ...grpc.CallOption) (*SignUpResponse, error)
 	LoginUser(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	VerifyUser(ctx context.Context, in *VerifyUserRequest, opts ...grpc.CallOption) (*VerifyUserResponse, error)
 	RequestForgetPassword(ctx context.Context, in *RequestForgetPasswordRequest, opts ...grpc.CallOption) (*RequestForgetPasswordResponse, error)
 	ResetPasswordByToken(ctx context.Context, in *ResetPasswordByTokenRequest, opts ...grpc.CallOption) (*ResetPasswordByTokenResponse, error)
+	UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error)
+	ResendVerificationEmail(ctx context.Context, in *ResendVerificationEmailRequest, opts ...grpc.CallOption) (*ResendVerificationEmailResponse, error)
+	UpdatePreferredLocale(ctx context.Context, in *UpdatePreferredLocaleRequest, opts ...grpc.CallOption) (*UpdatePreferredLocaleResponse, error)
+	UpdateSubscription(ctx context.Context, in *UpdateSubscriptionRequest, opts ...grpc.CallOption) (*UpdateSubscriptionResponse, error)
 	LoginUserViaGoogle(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LoginGoogleResponse, error)
 	LoginUserViaGoogleCallback(ctx context.Context, in *LoginGoogleRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	EnrollTOTP(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error)
+	VerifyMFA(ctx context.Context, in *VerifyMFARequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*DisableTOTPResponse, error)
+	LoginViaProvider(ctx context.Context, in *ProviderLoginRequest, opts ...grpc.CallOption) (*ProviderLoginResponse, error)
+	LoginViaProviderCallback(ctx context.Context, in *ProviderCallbackRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	StartLinkIdentity(ctx context.Context, in *ProviderLoginRequest, opts ...grpc.CallOption) (*ProviderLoginResponse, error)
+	LinkIdentity(ctx context.Context, in *ProviderCallbackRequest, opts ...grpc.CallOption) (*LinkIdentityResponse, error)
+	UnlinkIdentity(ctx context.Context, in *UnlinkIdentityRequest, opts ...grpc.CallOption) (*UnlinkIdentityResponse, error)
+	ListIdentities(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListIdentitiesResponse, error)
+	CreateAdminInvitation(ctx context.Context, in *CreateAdminInvitationRequest, opts ...grpc.CallOption) (*AdminInvitation, error)
+	ListAdminInvitations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListAdminInvitationsResponse, error)
+	RevokeAdminInvitation(ctx context.Context, in *RevokeAdminInvitationRequest, opts ...grpc.CallOption) (*RevokeAdminInvitationResponse, error)
 }
 
 type authServiceRoutesClient struct {
@@ -54,7 +88,7 @@ func NewAuthServiceRoutesClient(cc grpc.ClientConnInterface) AuthServiceRoutesCl
 	return &authServiceRoutesClient{cc}
 }
 
-func (c *authServiceRoutesClient) SignUpAdmin(ctx context.Context, in *SignUpRequest, opts ...grpc.CallOption) (*SignUpResponse, error) {
+func (c *authServiceRoutesClient) SignUpAdmin(ctx context.Context, in *SignUpAdminRequest, opts ...grpc.CallOption) (*SignUpResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SignUpResponse)
 	err := c.cc.Invoke(ctx, AuthServiceRoutes_SignUpAdmin_FullMethodName, in, out, cOpts...)
@@ -124,6 +158,46 @@ func (c *authServiceRoutesClient) ResetPasswordByToken(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *authServiceRoutesClient) UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockAccountResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_UnlockAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) ResendVerificationEmail(ctx context.Context, in *ResendVerificationEmailRequest, opts ...grpc.CallOption) (*ResendVerificationEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResendVerificationEmailResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_ResendVerificationEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) UpdatePreferredLocale(ctx context.Context, in *UpdatePreferredLocaleRequest, opts ...grpc.CallOption) (*UpdatePreferredLocaleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePreferredLocaleResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_UpdatePreferredLocale_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) UpdateSubscription(ctx context.Context, in *UpdateSubscriptionRequest, opts ...grpc.CallOption) (*UpdateSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateSubscriptionResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_UpdateSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authServiceRoutesClient) LoginUserViaGoogle(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LoginGoogleResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LoginGoogleResponse)
@@ -144,19 +218,166 @@ func (c *authServiceRoutesClient) LoginUserViaGoogleCallback(ctx context.Context
 	return out, nil
 }
 
+func (c *authServiceRoutesClient) EnrollTOTP(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollTOTPResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_EnrollTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest, opts ...grpc.CallOption) (*ConfirmTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTOTPResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_ConfirmTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) VerifyMFA(ctx context.Context, in *VerifyMFARequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_VerifyMFA_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) DisableTOTP(ctx context.Context, in *DisableTOTPRequest, opts ...grpc.CallOption) (*DisableTOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableTOTPResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_DisableTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) LoginViaProvider(ctx context.Context, in *ProviderLoginRequest, opts ...grpc.CallOption) (*ProviderLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProviderLoginResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_LoginViaProvider_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) LoginViaProviderCallback(ctx context.Context, in *ProviderCallbackRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_LoginViaProviderCallback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) StartLinkIdentity(ctx context.Context, in *ProviderLoginRequest, opts ...grpc.CallOption) (*ProviderLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProviderLoginResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_StartLinkIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) LinkIdentity(ctx context.Context, in *ProviderCallbackRequest, opts ...grpc.CallOption) (*LinkIdentityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkIdentityResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_LinkIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) UnlinkIdentity(ctx context.Context, in *UnlinkIdentityRequest, opts ...grpc.CallOption) (*UnlinkIdentityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlinkIdentityResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_UnlinkIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) ListIdentities(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListIdentitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIdentitiesResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_ListIdentities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) CreateAdminInvitation(ctx context.Context, in *CreateAdminInvitationRequest, opts ...grpc.CallOption) (*AdminInvitation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminInvitation)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_CreateAdminInvitation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) ListAdminInvitations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListAdminInvitationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAdminInvitationsResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_ListAdminInvitations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceRoutesClient) RevokeAdminInvitation(ctx context.Context, in *RevokeAdminInvitationRequest, opts ...grpc.CallOption) (*RevokeAdminInvitationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAdminInvitationResponse)
+	err := c.cc.Invoke(ctx, AuthServiceRoutes_RevokeAdminInvitation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceRoutesServer is the server API for AuthServiceRoutes service.
 // All implementations must embed UnimplementedAuthServiceRoutesServer
 // for forward compatibility.
 type AuthServiceRoutesServer interface {
-	SignUpAdmin(context.Context, *SignUpRequest) (*SignUpResponse, error)
+	SignUpAdmin(context.Context, *SignUpAdminRequest) (*SignUpResponse, error)
 	LoginAdmin(context.Context, *LoginRequest) (*LoginResponse, error)
 	SignUpUser(context.Context, *SignUpRequest) (*SignUpResponse, error)
 	LoginUser(context.Context, *LoginRequest) (*LoginResponse, error)
 	VerifyUser(context.Context, *VerifyUserRequest) (*VerifyUserResponse, error)
 	RequestForgetPassword(context.Context, *RequestForgetPasswordRequest) (*RequestForgetPasswordResponse, error)
 	ResetPasswordByToken(context.Context, *ResetPasswordByTokenRequest) (*ResetPasswordByTokenResponse, error)
+	UnlockAccount(context.Context, *UnlockAccountRequest) (*UnlockAccountResponse, error)
+	ResendVerificationEmail(context.Context, *ResendVerificationEmailRequest) (*ResendVerificationEmailResponse, error)
+	UpdatePreferredLocale(context.Context, *UpdatePreferredLocaleRequest) (*UpdatePreferredLocaleResponse, error)
+	UpdateSubscription(context.Context, *UpdateSubscriptionRequest) (*UpdateSubscriptionResponse, error)
 	LoginUserViaGoogle(context.Context, *emptypb.Empty) (*LoginGoogleResponse, error)
 	LoginUserViaGoogleCallback(context.Context, *LoginGoogleRequest) (*LoginResponse, error)
+	EnrollTOTP(context.Context, *emptypb.Empty) (*EnrollTOTPResponse, error)
+	ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error)
+	VerifyMFA(context.Context, *VerifyMFARequest) (*LoginResponse, error)
+	DisableTOTP(context.Context, *DisableTOTPRequest) (*DisableTOTPResponse, error)
+	LoginViaProvider(context.Context, *ProviderLoginRequest) (*ProviderLoginResponse, error)
+	LoginViaProviderCallback(context.Context, *ProviderCallbackRequest) (*LoginResponse, error)
+	StartLinkIdentity(context.Context, *ProviderLoginRequest) (*ProviderLoginResponse, error)
+	LinkIdentity(context.Context, *ProviderCallbackRequest) (*LinkIdentityResponse, error)
+	UnlinkIdentity(context.Context, *UnlinkIdentityRequest) (*UnlinkIdentityResponse, error)
+	ListIdentities(context.Context, *emptypb.Empty) (*ListIdentitiesResponse, error)
+	CreateAdminInvitation(context.Context, *CreateAdminInvitationRequest) (*AdminInvitation, error)
+	ListAdminInvitations(context.Context, *emptypb.Empty) (*ListAdminInvitationsResponse, error)
+	RevokeAdminInvitation(context.Context, *RevokeAdminInvitationRequest) (*RevokeAdminInvitationResponse, error)
 	mustEmbedUnimplementedAuthServiceRoutesServer()
 }
 
@@ -167,7 +388,7 @@ type AuthServiceRoutesServer interface {
 // pointer dereference when methods are called.
 type UnimplementedAuthServiceRoutesServer struct{}
 
-func (UnimplementedAuthServiceRoutesServer) SignUpAdmin(context.Context, *SignUpRequest) (*SignUpResponse, error) {
+func (UnimplementedAuthServiceRoutesServer) SignUpAdmin(context.Context, *SignUpAdminRequest) (*SignUpResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignUpAdmin not implemented")
 }
 func (UnimplementedAuthServiceRoutesServer) LoginAdmin(context.Context, *LoginRequest) (*LoginResponse, error) {
@@ -188,12 +409,63 @@ func (UnimplementedAuthServiceRoutesServer) RequestForgetPassword(context.Contex
 func (UnimplementedAuthServiceRoutesServer) ResetPasswordByToken(context.Context, *ResetPasswordByTokenRequest) (*ResetPasswordByTokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResetPasswordByToken not implemented")
 }
+func (UnimplementedAuthServiceRoutesServer) UnlockAccount(context.Context, *UnlockAccountRequest) (*UnlockAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockAccount not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) ResendVerificationEmail(context.Context, *ResendVerificationEmailRequest) (*ResendVerificationEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendVerificationEmail not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) UpdatePreferredLocale(context.Context, *UpdatePreferredLocaleRequest) (*UpdatePreferredLocaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePreferredLocale not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) UpdateSubscription(context.Context, *UpdateSubscriptionRequest) (*UpdateSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSubscription not implemented")
+}
 func (UnimplementedAuthServiceRoutesServer) LoginUserViaGoogle(context.Context, *emptypb.Empty) (*LoginGoogleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoginUserViaGoogle not implemented")
 }
 func (UnimplementedAuthServiceRoutesServer) LoginUserViaGoogleCallback(context.Context, *LoginGoogleRequest) (*LoginResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LoginUserViaGoogleCallback not implemented")
 }
+func (UnimplementedAuthServiceRoutesServer) EnrollTOTP(context.Context, *emptypb.Empty) (*EnrollTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) ConfirmTOTP(context.Context, *ConfirmTOTPRequest) (*ConfirmTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) VerifyMFA(context.Context, *VerifyMFARequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMFA not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) DisableTOTP(context.Context, *DisableTOTPRequest) (*DisableTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) LoginViaProvider(context.Context, *ProviderLoginRequest) (*ProviderLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginViaProvider not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) LoginViaProviderCallback(context.Context, *ProviderCallbackRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginViaProviderCallback not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) StartLinkIdentity(context.Context, *ProviderLoginRequest) (*ProviderLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartLinkIdentity not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) LinkIdentity(context.Context, *ProviderCallbackRequest) (*LinkIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkIdentity not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) UnlinkIdentity(context.Context, *UnlinkIdentityRequest) (*UnlinkIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlinkIdentity not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) ListIdentities(context.Context, *emptypb.Empty) (*ListIdentitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIdentities not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) CreateAdminInvitation(context.Context, *CreateAdminInvitationRequest) (*AdminInvitation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAdminInvitation not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) ListAdminInvitations(context.Context, *emptypb.Empty) (*ListAdminInvitationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAdminInvitations not implemented")
+}
+func (UnimplementedAuthServiceRoutesServer) RevokeAdminInvitation(context.Context, *RevokeAdminInvitationRequest) (*RevokeAdminInvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAdminInvitation not implemented")
+}
 func (UnimplementedAuthServiceRoutesServer) mustEmbedUnimplementedAuthServiceRoutesServer() {}
 func (UnimplementedAuthServiceRoutesServer) testEmbeddedByValue()                           {}
 
@@ -216,7 +488,7 @@ func RegisterAuthServiceRoutesServer(s grpc.ServiceRegistrar, srv AuthServiceRou
 }
 
 func _AuthServiceRoutes_SignUpAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignUpRequest)
+	in := new(SignUpAdminRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -228,7 +500,7 @@ func _AuthServiceRoutes_SignUpAdmin_Handler(srv interface{}, ctx context.Context
 		FullMethod: AuthServiceRoutes_SignUpAdmin_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServiceRoutesServer).SignUpAdmin(ctx, req.(*SignUpRequest))
+		return srv.(AuthServiceRoutesServer).SignUpAdmin(ctx, req.(*SignUpAdminRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -341,6 +613,78 @@ func _AuthServiceRoutes_ResetPasswordByToken_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthServiceRoutes_UnlockAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).UnlockAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_UnlockAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).UnlockAccount(ctx, req.(*UnlockAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_ResendVerificationEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendVerificationEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).ResendVerificationEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_ResendVerificationEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).ResendVerificationEmail(ctx, req.(*ResendVerificationEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_UpdatePreferredLocale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePreferredLocaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).UpdatePreferredLocale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_UpdatePreferredLocale_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).UpdatePreferredLocale(ctx, req.(*UpdatePreferredLocaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_UpdateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).UpdateSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_UpdateSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).UpdateSubscription(ctx, req.(*UpdateSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthServiceRoutes_LoginUserViaGoogle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -377,6 +721,240 @@ func _AuthServiceRoutes_LoginUserViaGoogleCallback_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthServiceRoutes_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_EnrollTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).EnrollTOTP(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_ConfirmTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).ConfirmTOTP(ctx, req.(*ConfirmTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_VerifyMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMFARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).VerifyMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_VerifyMFA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).VerifyMFA(ctx, req.(*VerifyMFARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_DisableTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).DisableTOTP(ctx, req.(*DisableTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_LoginViaProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProviderLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).LoginViaProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_LoginViaProvider_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).LoginViaProvider(ctx, req.(*ProviderLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_LoginViaProviderCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProviderCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).LoginViaProviderCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_LoginViaProviderCallback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).LoginViaProviderCallback(ctx, req.(*ProviderCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_StartLinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProviderLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).StartLinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_StartLinkIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).StartLinkIdentity(ctx, req.(*ProviderLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_LinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProviderCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).LinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_LinkIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).LinkIdentity(ctx, req.(*ProviderCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_UnlinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).UnlinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_UnlinkIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).UnlinkIdentity(ctx, req.(*UnlinkIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_ListIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).ListIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_ListIdentities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).ListIdentities(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_CreateAdminInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAdminInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).CreateAdminInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_CreateAdminInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).CreateAdminInvitation(ctx, req.(*CreateAdminInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_ListAdminInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).ListAdminInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_ListAdminInvitations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).ListAdminInvitations(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthServiceRoutes_RevokeAdminInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAdminInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceRoutesServer).RevokeAdminInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthServiceRoutes_RevokeAdminInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceRoutesServer).RevokeAdminInvitation(ctx, req.(*RevokeAdminInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthServiceRoutes_ServiceDesc is the grpc.ServiceDesc for AuthServiceRoutes service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -412,6 +990,22 @@ var AuthServiceRoutes_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResetPasswordByToken",
 			Handler:    _AuthServiceRoutes_ResetPasswordByToken_Handler,
 		},
+		{
+			MethodName: "UnlockAccount",
+			Handler:    _AuthServiceRoutes_UnlockAccount_Handler,
+		},
+		{
+			MethodName: "ResendVerificationEmail",
+			Handler:    _AuthServiceRoutes_ResendVerificationEmail_Handler,
+		},
+		{
+			MethodName: "UpdatePreferredLocale",
+			Handler:    _AuthServiceRoutes_UpdatePreferredLocale_Handler,
+		},
+		{
+			MethodName: "UpdateSubscription",
+			Handler:    _AuthServiceRoutes_UpdateSubscription_Handler,
+		},
 		{
 			MethodName: "LoginUserViaGoogle",
 			Handler:    _AuthServiceRoutes_LoginUserViaGoogle_Handler,
@@ -420,6 +1014,58 @@ var AuthServiceRoutes_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LoginUserViaGoogleCallback",
 			Handler:    _AuthServiceRoutes_LoginUserViaGoogleCallback_Handler,
 		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _AuthServiceRoutes_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _AuthServiceRoutes_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "VerifyMFA",
+			Handler:    _AuthServiceRoutes_VerifyMFA_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _AuthServiceRoutes_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "LoginViaProvider",
+			Handler:    _AuthServiceRoutes_LoginViaProvider_Handler,
+		},
+		{
+			MethodName: "LoginViaProviderCallback",
+			Handler:    _AuthServiceRoutes_LoginViaProviderCallback_Handler,
+		},
+		{
+			MethodName: "StartLinkIdentity",
+			Handler:    _AuthServiceRoutes_StartLinkIdentity_Handler,
+		},
+		{
+			MethodName: "LinkIdentity",
+			Handler:    _AuthServiceRoutes_LinkIdentity_Handler,
+		},
+		{
+			MethodName: "UnlinkIdentity",
+			Handler:    _AuthServiceRoutes_UnlinkIdentity_Handler,
+		},
+		{
+			MethodName: "ListIdentities",
+			Handler:    _AuthServiceRoutes_ListIdentities_Handler,
+		},
+		{
+			MethodName: "CreateAdminInvitation",
+			Handler:    _AuthServiceRoutes_CreateAdminInvitation_Handler,
+		},
+		{
+			MethodName: "ListAdminInvitations",
+			Handler:    _AuthServiceRoutes_ListAdminInvitations_Handler,
+		},
+		{
+			MethodName: "RevokeAdminInvitation",
+			Handler:    _AuthServiceRoutes_RevokeAdminInvitation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth/auth.proto",
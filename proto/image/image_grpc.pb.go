@@ -21,6 +21,9 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	ImageService_GenerateImage_FullMethodName        = "/images.ImageService/GenerateImage"
 	ImageService_DownloadAndSaveImage_FullMethodName = "/images.ImageService/DownloadAndSaveImage"
+	ImageService_GenerateImageAsync_FullMethodName   = "/images.ImageService/GenerateImageAsync"
+	ImageService_GetImageJob_FullMethodName          = "/images.ImageService/GetImageJob"
+	ImageService_ListImageJobs_FullMethodName        = "/images.ImageService/ListImageJobs"
 )
 
 // ImageServiceClient is the client API for ImageService service.
@@ -33,6 +36,15 @@ type ImageServiceClient interface {
 	GenerateImage(ctx context.Context, in *ImageRequest, opts ...grpc.CallOption) (*ImageResponse, error)
 	// DownloadAndSaveImage uses the URL and filename to download the image
 	DownloadAndSaveImage(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error)
+	// GenerateImageAsync queues the generation and returns the pending job,
+	// the user is emailed when it finishes
+	GenerateImageAsync(ctx context.Context, in *ImageRequest, opts ...grpc.CallOption) (*ImageJob, error)
+	// GetImageJob returns a job of the authenticated user, or of the
+	// organization selected with x-org-id
+	GetImageJob(ctx context.Context, in *GetImageJobRequest, opts ...grpc.CallOption) (*ImageJob, error)
+	// ListImageJobs pages through the library newest first, with x-org-id it
+	// lists the jobs of every member of the organization
+	ListImageJobs(ctx context.Context, in *ListImageJobsRequest, opts ...grpc.CallOption) (*ListImageJobsResponse, error)
 }
 
 type imageServiceClient struct {
@@ -63,6 +75,36 @@ func (c *imageServiceClient) DownloadAndSaveImage(ctx context.Context, in *Downl
 	return out, nil
 }
 
+func (c *imageServiceClient) GenerateImageAsync(ctx context.Context, in *ImageRequest, opts ...grpc.CallOption) (*ImageJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImageJob)
+	err := c.cc.Invoke(ctx, ImageService_GenerateImageAsync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imageServiceClient) GetImageJob(ctx context.Context, in *GetImageJobRequest, opts ...grpc.CallOption) (*ImageJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImageJob)
+	err := c.cc.Invoke(ctx, ImageService_GetImageJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imageServiceClient) ListImageJobs(ctx context.Context, in *ListImageJobsRequest, opts ...grpc.CallOption) (*ListImageJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListImageJobsResponse)
+	err := c.cc.Invoke(ctx, ImageService_ListImageJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ImageServiceServer is the server API for ImageService service.
 // All implementations must embed UnimplementedImageServiceServer
 // for forward compatibility.
@@ -73,6 +115,15 @@ type ImageServiceServer interface {
 	GenerateImage(context.Context, *ImageRequest) (*ImageResponse, error)
 	// DownloadAndSaveImage uses the URL and filename to download the image
 	DownloadAndSaveImage(context.Context, *DownloadRequest) (*DownloadResponse, error)
+	// GenerateImageAsync queues the generation and returns the pending job,
+	// the user is emailed when it finishes
+	GenerateImageAsync(context.Context, *ImageRequest) (*ImageJob, error)
+	// GetImageJob returns a job of the authenticated user, or of the
+	// organization selected with x-org-id
+	GetImageJob(context.Context, *GetImageJobRequest) (*ImageJob, error)
+	// ListImageJobs pages through the library newest first, with x-org-id it
+	// lists the jobs of every member of the organization
+	ListImageJobs(context.Context, *ListImageJobsRequest) (*ListImageJobsResponse, error)
 	mustEmbedUnimplementedImageServiceServer()
 }
 
@@ -89,6 +140,15 @@ func (UnimplementedImageServiceServer) GenerateImage(context.Context, *ImageRequ
 func (UnimplementedImageServiceServer) DownloadAndSaveImage(context.Context, *DownloadRequest) (*DownloadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DownloadAndSaveImage not implemented")
 }
+func (UnimplementedImageServiceServer) GenerateImageAsync(context.Context, *ImageRequest) (*ImageJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateImageAsync not implemented")
+}
+func (UnimplementedImageServiceServer) GetImageJob(context.Context, *GetImageJobRequest) (*ImageJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImageJob not implemented")
+}
+func (UnimplementedImageServiceServer) ListImageJobs(context.Context, *ListImageJobsRequest) (*ListImageJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImageJobs not implemented")
+}
 func (UnimplementedImageServiceServer) mustEmbedUnimplementedImageServiceServer() {}
 func (UnimplementedImageServiceServer) testEmbeddedByValue()                      {}
 
@@ -146,6 +206,60 @@ func _ImageService_DownloadAndSaveImage_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ImageService_GenerateImageAsync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServiceServer).GenerateImageAsync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImageService_GenerateImageAsync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServiceServer).GenerateImageAsync(ctx, req.(*ImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImageService_GetImageJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImageJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServiceServer).GetImageJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImageService_GetImageJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServiceServer).GetImageJob(ctx, req.(*GetImageJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImageService_ListImageJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImageJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServiceServer).ListImageJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImageService_ListImageJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServiceServer).ListImageJobs(ctx, req.(*ListImageJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ImageService_ServiceDesc is the grpc.ServiceDesc for ImageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +275,18 @@ var ImageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DownloadAndSaveImage",
 			Handler:    _ImageService_DownloadAndSaveImage_Handler,
 		},
+		{
+			MethodName: "GenerateImageAsync",
+			Handler:    _ImageService_GenerateImageAsync_Handler,
+		},
+		{
+			MethodName: "GetImageJob",
+			Handler:    _ImageService_GetImageJob_Handler,
+		},
+		{
+			MethodName: "ListImageJobs",
+			Handler:    _ImageService_ListImageJobs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "image/image.proto",